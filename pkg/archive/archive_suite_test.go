@@ -0,0 +1,53 @@
+package archive_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+func TestArchive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Archive Suite")
+}
+
+// replicaSetRevision returns a ReplicaSet revision with the given number and image tag.
+func replicaSetRevision(revision int64, tag int) history.Revision {
+	rev, err := history.NewReplicaSet(&appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("app-%d", tag),
+			Namespace: "test",
+			Annotations: map[string]string{
+				deploymentutil.RevisionAnnotation: strconv.FormatInt(revision, 10),
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app":                                  "app",
+						appsv1.DefaultDeploymentUniqueLabelKey: fmt.Sprintf("hash-%d", tag),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "app",
+						Image: fmt.Sprintf("app:%d", tag),
+					}},
+				},
+			},
+		},
+		Status: appsv1.ReplicaSetStatus{Replicas: 1, ReadyReplicas: 1},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return rev
+}
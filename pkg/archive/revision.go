@@ -0,0 +1,173 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// MarkerArchived marks revisions that were loaded from the archive instead of from the cluster.
+const MarkerArchived = "archived"
+
+// Snapshot is a single revision stored in the archive.
+type Snapshot struct {
+	// Hash is the content hash of the revision's pod template, see Hash.
+	Hash string `json:"hash"`
+	// Revision is the revision number when the revision was last recorded.
+	Revision int64 `json:"revision"`
+	// RecordedAt is the time when the revision was last recorded.
+	RecordedAt metav1.Time `json:"recordedAt"`
+	// Template is the revision's pod template as returned by history.Revision.PodTemplate.
+	Template corev1.PodTemplateSpec `json:"template"`
+	// Object is the full revision object (e.g., the ReplicaSet or ControllerRevision).
+	Object runtime.RawExtension `json:"object"`
+}
+
+// Hash returns the content hash of the given revision's pod template. Revisions with equal pod templates have the same
+// hash.
+func Hash(rev history.Revision) (string, error) {
+	data, err := json.Marshal(rev.PodTemplate())
+	if err != nil {
+		return "", fmt.Errorf("error marshalling pod template: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+var (
+	_ history.Revision       = &Revision{}
+	_ history.MarkedRevision = &Revision{}
+	_ history.RoledRevision  = &Revision{}
+)
+
+// Revision is a history.Revision loaded from the archive.
+type Revision struct {
+	Snapshot *Snapshot
+	// Obj is the decoded revision object of Snapshot.
+	Obj client.Object
+}
+
+// GetObjectKind implements runtime.Object.
+func (r *Revision) GetObjectKind() schema.ObjectKind {
+	if r == nil || r.Obj == nil {
+		return &metav1.TypeMeta{}
+	}
+	return r.Obj.GetObjectKind()
+}
+
+// DeepCopyObject implements runtime.Object.
+func (r *Revision) DeepCopyObject() runtime.Object {
+	if r == nil {
+		return nil
+	}
+
+	out := new(Revision)
+	*out = *r
+	if r.Snapshot != nil {
+		snapshot := *r.Snapshot
+		snapshot.Template = *r.Snapshot.Template.DeepCopy()
+		snapshot.Object = *r.Snapshot.Object.DeepCopy()
+		out.Snapshot = &snapshot
+	}
+	if r.Obj != nil {
+		out.Obj = r.Obj.DeepCopyObject().(client.Object)
+	}
+	return out
+}
+
+func (r *Revision) Number() int64 {
+	return r.Snapshot.Revision
+}
+
+func (r *Revision) Name() string {
+	return r.Obj.GetName()
+}
+
+func (r *Revision) Object() client.Object {
+	return r.Obj
+}
+
+func (r *Revision) PodTemplate() *corev1.Pod {
+	t := r.Snapshot.Template.DeepCopy()
+	return &corev1.Pod{
+		ObjectMeta: t.ObjectMeta,
+		Spec:       t.Spec,
+	}
+}
+
+// CurrentReplicas returns 0, archived revisions don't have any replicas.
+func (r *Revision) CurrentReplicas() int32 {
+	return 0
+}
+
+// ReadyReplicas returns 0, archived revisions don't have any replicas.
+func (r *Revision) ReadyReplicas() int32 {
+	return 0
+}
+
+func (r *Revision) Markers() []string {
+	return []string{MarkerArchived}
+}
+
+// Role returns history.RoleOld, archived revisions are not part of the workload's rollout anymore.
+func (r *Revision) Role() history.Role {
+	return history.RoleOld
+}
+
+// Merge merges the given archived revisions into the given live revisions and returns a sorted list. Archived revisions
+// are only added if neither a live revision with the same pod template nor a live revision with the same number exists.
+func Merge(live, archived history.Revisions) (history.Revisions, error) {
+	var (
+		hashes  = make(map[string]struct{}, len(live))
+		numbers = make(map[int64]struct{}, len(live))
+		merged  = make(history.Revisions, 0, len(live)+len(archived))
+	)
+
+	for _, rev := range live {
+		hash, err := hashOf(rev)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes[hash] = struct{}{}
+		numbers[rev.Number()] = struct{}{}
+		merged = append(merged, rev)
+	}
+
+	for _, rev := range archived {
+		hash, err := hashOf(rev)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := hashes[hash]; ok {
+			continue
+		}
+		if _, ok := numbers[rev.Number()]; ok {
+			continue
+		}
+
+		merged = append(merged, rev)
+	}
+
+	history.Sort(merged)
+	return merged, nil
+}
+
+// hashOf returns the recorded hash of archived revisions and calculates the hash of all other revisions.
+func hashOf(rev history.Revision) (string, error) {
+	if archived, ok := rev.(*Revision); ok && archived.Snapshot.Hash != "" {
+		return archived.Snapshot.Hash, nil
+	}
+	return Hash(rev)
+}
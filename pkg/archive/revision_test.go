@@ -0,0 +1,63 @@
+package archive_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/archive"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("Hash", func() {
+	It("should only depend on the pod template", func() {
+		Expect(Hash(replicaSetRevision(1, 1))).To(Equal(must(Hash(replicaSetRevision(2, 1)))))
+		Expect(Hash(replicaSetRevision(1, 1))).NotTo(Equal(must(Hash(replicaSetRevision(1, 2)))))
+	})
+})
+
+var _ = Describe("Merge", func() {
+	archived := func(revision int64, tag int) history.Revision {
+		rev := replicaSetRevision(revision, tag)
+		return &Revision{
+			Snapshot: &Snapshot{Hash: must(Hash(rev)), Revision: revision},
+			Obj:      rev.Object(),
+		}
+	}
+
+	It("should add archived revisions that are not live anymore", func() {
+		live := history.Revisions{replicaSetRevision(3, 3), replicaSetRevision(4, 4)}
+
+		merged, err := Merge(live, history.Revisions{archived(1, 1), archived(2, 2), archived(3, 3)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(HaveExactElements(
+			BeAssignableToTypeOf(&Revision{}),
+			BeAssignableToTypeOf(&Revision{}),
+			BeIdenticalTo(live[0]),
+			BeIdenticalTo(live[1]),
+		))
+		Expect(merged[0].Number()).To(BeEquivalentTo(1))
+		Expect(merged[1].Number()).To(BeEquivalentTo(2))
+	})
+
+	It("should prefer live revisions with the same pod template", func() {
+		// revision 1 was rolled back to and became revision 3
+		live := history.Revisions{replicaSetRevision(2, 2), replicaSetRevision(3, 1)}
+
+		merged, err := Merge(live, history.Revisions{archived(1, 1)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(HaveExactElements(BeIdenticalTo(live[0]), BeIdenticalTo(live[1])))
+	})
+
+	It("should prefer live revisions with the same number", func() {
+		live := history.Revisions{replicaSetRevision(1, 2)}
+
+		merged, err := Merge(live, history.Revisions{archived(1, 1)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(HaveExactElements(BeIdenticalTo(live[0])))
+	})
+})
+
+func must[T any](v T, err error) T {
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return v
+}
@@ -0,0 +1,198 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// DefaultDir returns the default directory of the archive, i.e., kubectl-revisions/archive in the user's configuration
+// directory (e.g., ~/.config on Linux).
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubectl-revisions", "archive")
+}
+
+// Store is a local on-disk archive of revisions. Revisions are stored in one file per workload object and pod template,
+// i.e., the archive is content-addressed by the hash of the pod template (see Hash):
+//
+//	<dir>/<namespace>/<kind>.<group>/<name>/<hash>.yaml
+type Store struct {
+	// Dir is the root directory of the archive.
+	Dir string
+	// Now is used for determining the time when revisions are recorded. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewStore creates a new Store in the given directory. If dir is empty, DefaultDir is used.
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("could not determine the default archive directory, specify it explicitly")
+	}
+
+	return &Store{Dir: dir}, nil
+}
+
+// Record stores the given revisions of the given workload object in the archive. Revisions already contained in the
+// archive are updated, e.g., if their revision number changed. It returns the number of newly recorded revisions.
+func (s *Store) Record(gk schema.GroupKind, obj client.Object, revs history.Revisions) (int, error) {
+	dir := s.objectDir(gk, obj.GetNamespace(), obj.GetName())
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return 0, fmt.Errorf("error creating archive directory: %w", err)
+	}
+
+	var recorded int
+	for _, rev := range revs {
+		if _, ok := rev.(*Revision); ok {
+			// don't record revisions loaded from the archive again
+			continue
+		}
+
+		hash, err := Hash(rev)
+		if err != nil {
+			return recorded, err
+		}
+
+		path := filepath.Join(dir, hash+".yaml")
+		existing, err := readSnapshot(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return recorded, err
+		}
+		if existing != nil && existing.Revision == rev.Number() {
+			continue
+		}
+
+		snapshot, err := s.newSnapshot(hash, rev)
+		if err != nil {
+			return recorded, err
+		}
+		if err := writeSnapshot(path, snapshot); err != nil {
+			return recorded, err
+		}
+
+		if existing == nil {
+			recorded++
+		}
+	}
+
+	return recorded, nil
+}
+
+// List returns all archived revisions of the given workload object. The returned list is sorted by revision number.
+func (s *Store) List(gk schema.GroupKind, namespace, name string) (history.Revisions, error) {
+	files, err := os.ReadDir(s.objectDir(gk, namespace, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading archive: %w", err)
+	}
+
+	decoder := serializer.NewCodecFactory(history.Scheme).UniversalDeserializer()
+
+	var revs history.Revisions
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+			continue
+		}
+
+		snapshot, err := readSnapshot(filepath.Join(s.objectDir(gk, namespace, name), file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		obj, _, err := decoder.Decode(snapshot.Object.Raw, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding archived revision %s: %w", file.Name(), err)
+		}
+		clientObj, ok := obj.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type %T in archived revision %s", obj, file.Name())
+		}
+
+		revs = append(revs, &Revision{Snapshot: snapshot, Obj: clientObj})
+	}
+
+	history.Sort(revs)
+	return revs, nil
+}
+
+func (s *Store) objectDir(gk schema.GroupKind, namespace, name string) string {
+	kind := strings.TrimSuffix(strings.ToLower(gk.Kind)+"."+gk.Group, ".")
+	return filepath.Join(s.Dir, namespace, kind, name)
+}
+
+func (s *Store) newSnapshot(hash string, rev history.Revision) (*Snapshot, error) {
+	obj := rev.Object().DeepCopyObject().(client.Object)
+	gvk, err := apiutil.GVKForObject(obj, history.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+
+	template := history.PodTemplateSpec(rev.PodTemplate())
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	return &Snapshot{
+		Hash:       hash,
+		Revision:   rev.Number(),
+		RecordedAt: metav1.NewTime(now().UTC().Truncate(time.Second)),
+		Template:   *template,
+		Object:     runtime.RawExtension{Object: obj},
+	}, nil
+}
+
+func readSnapshot(path string) (*Snapshot, error) {
+	// nolint:gosec // the path is constructed from the archive directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := yaml.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("error parsing archived revision %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+func writeSnapshot(path string, snapshot *Snapshot) error {
+	data, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("error marshalling archived revision: %w", err)
+	}
+
+	// write to a temporary file and rename it to make sure that readers never see partially written files
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("error writing archived revision: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing archived revision: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package archive_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/archive"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		store      *Store
+		gk         schema.GroupKind
+		deployment *appsv1.Deployment
+		now        time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		store, err = NewStore(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+
+		now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		store.Now = func() time.Time { return now }
+
+		gk = appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind()
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test"}}
+	})
+
+	Describe("#Record", func() {
+		It("should store one file per pod template", func() {
+			Expect(store.Record(gk, deployment, history.Revisions{replicaSetRevision(1, 1), replicaSetRevision(2, 2)})).To(Equal(2))
+
+			hash, err := Hash(replicaSetRevision(1, 1))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(store.Dir, "test", "deployment.apps", "app", hash+".yaml")).To(BeARegularFile())
+		})
+
+		It("should not record the same revision twice", func() {
+			Expect(store.Record(gk, deployment, history.Revisions{replicaSetRevision(1, 1)})).To(Equal(1))
+			Expect(store.Record(gk, deployment, history.Revisions{replicaSetRevision(1, 1), replicaSetRevision(2, 2)})).To(Equal(1))
+		})
+
+		It("should update the revision number of existing snapshots", func() {
+			Expect(store.Record(gk, deployment, history.Revisions{replicaSetRevision(1, 1)})).To(Equal(1))
+			// rolling back to revision 1 creates revision 2 with the same pod template
+			Expect(store.Record(gk, deployment, history.Revisions{replicaSetRevision(2, 1)})).To(Equal(0))
+
+			revs, err := store.List(gk, "test", "app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs).To(HaveLen(1))
+			Expect(revs[0].Number()).To(BeEquivalentTo(2))
+		})
+
+		It("should not record archived revisions", func() {
+			Expect(store.Record(gk, deployment, history.Revisions{&Revision{Snapshot: &Snapshot{Revision: 1}}})).To(Equal(0))
+		})
+	})
+
+	Describe("#List", func() {
+		It("should return an empty list if nothing was recorded", func() {
+			Expect(store.List(gk, "test", "app")).To(BeEmpty())
+		})
+
+		It("should return the sorted list of recorded revisions", func() {
+			rev1, rev2 := replicaSetRevision(1, 1), replicaSetRevision(2, 2)
+			Expect(store.Record(gk, deployment, history.Revisions{rev2, rev1})).To(Equal(2))
+
+			revs, err := store.List(gk, "test", "app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs).To(HaveLen(2))
+
+			for i, rev := range []history.Revision{rev1, rev2} {
+				Expect(revs[i]).To(BeAssignableToTypeOf(&Revision{}))
+				Expect(revs[i].Number()).To(Equal(rev.Number()))
+				Expect(revs[i].Name()).To(Equal(rev.Name()))
+				Expect(revs[i].Object()).To(BeAssignableToTypeOf(&appsv1.ReplicaSet{}))
+				Expect(revs[i].PodTemplate()).To(Equal(rev.PodTemplate()))
+				Expect(revs[i].CurrentReplicas()).To(BeEquivalentTo(0))
+				Expect(revs[i].(history.MarkedRevision).Markers()).To(ConsistOf(MarkerArchived))
+
+				snapshot := revs[i].(*Revision).Snapshot
+				Expect(snapshot.RecordedAt.Time).To(BeTemporally("==", now))
+				Expect(Hash(revs[i])).To(Equal(snapshot.Hash))
+			}
+		})
+
+		It("should ignore other files", func() {
+			Expect(store.Record(gk, deployment, history.Revisions{replicaSetRevision(1, 1)})).To(Equal(1))
+			Expect(os.WriteFile(filepath.Join(store.Dir, "test", "deployment.apps", "app", "README"), nil, 0600)).To(Succeed())
+
+			Expect(store.List(gk, "test", "app")).To(HaveLen(1))
+		})
+	})
+})
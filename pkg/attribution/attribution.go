@@ -0,0 +1,149 @@
+package attribution
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// Tolerance is the maximum time between the last write of a field manager and the creation of a revision for
+// attributing the revision to the field manager. Controllers create new revisions within seconds after the pod template
+// of the workload object was changed.
+const Tolerance = time.Minute
+
+// Attribution describes the field manager that most plausibly caused a revision, see Attribute.
+type Attribution struct {
+	// Manager is the name of the field manager, e.g., kubectl-client-side-apply, helm, or argocd-controller.
+	Manager string `json:"manager"`
+	// Operation is the type of the manager's write, i.e., Apply or Update.
+	Operation metav1.ManagedFieldsOperationType `json:"operation,omitempty"`
+	// Time is the time of the manager's last write if it matches the creation of the revision.
+	Time *metav1.Time `json:"time,omitempty"`
+	// Exact is true if the manager's last write matches the creation of the revision. Otherwise, the revision was only
+	// attributed to the manager because it is the only one owning fields of the pod template.
+	Exact bool `json:"exact"`
+}
+
+// Attributions maps revisions to the field managers that caused them, see Attribute.
+type Attributions map[history.Revision]*Attribution
+
+// Attribute attributes each of the given revisions of the given workload object to the field manager that most
+// plausibly caused it based on the managedFields of the workload object and of the revision objects.
+//
+// managedFields only record the time of the last write of each manager. Hence, a revision is attributed to the manager
+// whose last write is closest to the revision's creation (within Tolerance). This is accurate for the latest revisions
+// but older revisions might not match any write anymore. In this case, the revision is attributed to the only manager
+// owning fields of the workload's pod template, if there is exactly one. Revisions that cannot be attributed are not part
+// of the result.
+//
+// Controllers, i.e., managers that also write the workload's status (like kube-controller-manager), are never
+// considered as they only create the revision objects on behalf of other actors.
+func Attribute(obj client.Object, revs history.Revisions) Attributions {
+	var (
+		controllers = statusManagers(obj.GetManagedFields())
+		templates   []metav1.ManagedFieldsEntry
+		owners      = map[string]metav1.ManagedFieldsEntry{}
+	)
+
+	for _, entry := range obj.GetManagedFields() {
+		if controllers[entry.Manager] || writesStatus(entry) || !ownsField(entry, "f:spec", "f:template") {
+			continue
+		}
+		templates = append(templates, entry)
+		owners[entry.Manager] = entry
+	}
+
+	attributions := Attributions{}
+	for _, rev := range revs {
+		candidates := templates
+		for _, entry := range rev.Object().GetManagedFields() {
+			if !controllers[entry.Manager] && !writesStatus(entry) {
+				candidates = append(candidates, entry)
+			}
+		}
+
+		if entry := closestEntry(candidates, rev.Object().GetCreationTimestamp().Time); entry != nil {
+			attributions[rev] = &Attribution{
+				Manager:   entry.Manager,
+				Operation: entry.Operation,
+				Time:      entry.Time,
+				Exact:     true,
+			}
+			continue
+		}
+
+		if len(owners) == 1 {
+			for _, entry := range owners {
+				attributions[rev] = &Attribution{Manager: entry.Manager, Operation: entry.Operation}
+			}
+		}
+	}
+
+	return attributions
+}
+
+// closestEntry returns the entry with the time closest to the given time within Tolerance, or nil if there is none.
+func closestEntry(entries []metav1.ManagedFieldsEntry, t time.Time) *metav1.ManagedFieldsEntry {
+	var (
+		closest  *metav1.ManagedFieldsEntry
+		distance time.Duration
+	)
+
+	for i, entry := range entries {
+		if entry.Time == nil {
+			continue
+		}
+
+		d := entry.Time.Sub(t).Abs()
+		if d > Tolerance {
+			continue
+		}
+		if closest == nil || d < distance {
+			closest, distance = &entries[i], d
+		}
+	}
+
+	return closest
+}
+
+// statusManagers returns the set of managers that write the status of an object, i.e., its controllers.
+func statusManagers(entries []metav1.ManagedFieldsEntry) map[string]bool {
+	managers := map[string]bool{}
+	for _, entry := range entries {
+		if writesStatus(entry) {
+			managers[entry.Manager] = true
+		}
+	}
+	return managers
+}
+
+// writesStatus returns true if the entry belongs to a write of the status, either via the status subresource or (in
+// older Kubernetes versions) via the main resource.
+func writesStatus(entry metav1.ManagedFieldsEntry) bool {
+	return entry.Subresource == "status" || ownsField(entry, "f:status")
+}
+
+// ownsField returns true if the entry's fieldsV1 contain the given path, e.g., f:spec, f:template.
+func ownsField(entry metav1.ManagedFieldsEntry, path ...string) bool {
+	if entry.FieldsV1 == nil {
+		return false
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+		return false
+	}
+
+	for _, key := range path {
+		value, ok := fields[key].(map[string]any)
+		if !ok {
+			return false
+		}
+		fields = value
+	}
+	return true
+}
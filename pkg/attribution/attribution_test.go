@@ -0,0 +1,112 @@
+package attribution_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/attribution"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+)
+
+var _ = Describe("Attribute", func() {
+	var (
+		now        time.Time
+		deployment *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		now = time.Now().Truncate(time.Second)
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "nginx",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				entry("kube-controller-manager", metav1.ManagedFieldsOperationUpdate, now, "status", `{"f:status":{"f:replicas":{}}}`),
+			},
+		}}
+	})
+
+	It("should attribute revisions to the manager whose last write matches the creation time", func() {
+		deployment.ManagedFields = append(deployment.ManagedFields,
+			entry("kubectl-client-side-apply", metav1.ManagedFieldsOperationUpdate, now.Add(-time.Hour), "", `{"f:spec":{"f:template":{"f:spec":{}}}}`),
+			entry("kubectl", metav1.ManagedFieldsOperationApply, now.Add(-10*time.Minute), "", `{"f:spec":{"f:template":{"f:spec":{}}}}`),
+		)
+		rev1 := fake.ReplicaSetRevision(Default, 1, now.Add(-time.Hour+time.Second), corev1.PodSpec{})
+		rev2 := fake.ReplicaSetRevision(Default, 2, now.Add(-10*time.Minute), corev1.PodSpec{})
+		rev3 := fake.ReplicaSetRevision(Default, 3, now.Add(-5*time.Minute), corev1.PodSpec{})
+
+		attributions := Attribute(deployment, history.Revisions{rev1, rev2, rev3})
+		Expect(attributions).To(HaveLen(2))
+		Expect(attributions[rev1]).To(Equal(&Attribution{
+			Manager:   "kubectl-client-side-apply",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			Time:      ptr.To(metav1.NewTime(now.Add(-time.Hour))),
+			Exact:     true,
+		}))
+		Expect(attributions[rev2]).To(HaveField("Manager", "kubectl"))
+		Expect(attributions[rev2]).To(HaveField("Operation", metav1.ManagedFieldsOperationApply))
+	})
+
+	It("should ignore managers that don't own fields of the pod template", func() {
+		deployment.ManagedFields = append(deployment.ManagedFields,
+			entry("kubectl-scale", metav1.ManagedFieldsOperationUpdate, now, "scale", `{"f:spec":{"f:replicas":{}}}`),
+			entry("helm", metav1.ManagedFieldsOperationUpdate, now.Add(-time.Hour), "", `{"f:spec":{"f:template":{}}}`),
+		)
+		rev1 := fake.ReplicaSetRevision(Default, 1, now, corev1.PodSpec{})
+
+		Expect(Attribute(deployment, history.Revisions{rev1})).To(HaveKeyWithValue(rev1, &Attribution{
+			Manager:   "helm",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+		}))
+	})
+
+	It("should consider the managedFields of the revision object but not the controller", func() {
+		rev1 := fake.ReplicaSetRevision(Default, 1, now.Add(-time.Hour), corev1.PodSpec{})
+		rev1.Object().SetManagedFields([]metav1.ManagedFieldsEntry{
+			entry("kube-controller-manager", metav1.ManagedFieldsOperationUpdate, now.Add(-time.Hour), "", `{"f:spec":{}}`),
+			entry("ci-bot", metav1.ManagedFieldsOperationApply, now.Add(-time.Hour), "", `{"f:metadata":{}}`),
+		})
+
+		Expect(Attribute(deployment, history.Revisions{rev1})).To(HaveKeyWithValue(rev1, HaveField("Manager", "ci-bot")))
+	})
+
+	It("should treat managers writing the status field as controllers", func() {
+		deployment.ManagedFields = []metav1.ManagedFieldsEntry{
+			entry("old-controller", metav1.ManagedFieldsOperationUpdate, now, "", `{"f:spec":{"f:template":{}},"f:status":{}}`),
+		}
+
+		Expect(Attribute(deployment, history.Revisions{fake.ReplicaSetRevision(Default, 1, now, corev1.PodSpec{})})).To(BeEmpty())
+	})
+
+	It("should not attribute revisions without managedFields", func() {
+		// e.g., objects read from dumps created with `kubectl get -o yaml`, which strips managedFields
+		deployment.ManagedFields = nil
+
+		Expect(Attribute(deployment, history.Revisions{fake.ReplicaSetRevision(Default, 1, now, corev1.PodSpec{})})).To(BeEmpty())
+	})
+
+	It("should not guess if multiple managers own fields of the pod template", func() {
+		deployment.ManagedFields = append(deployment.ManagedFields,
+			entry("helm", metav1.ManagedFieldsOperationUpdate, now.Add(-time.Hour), "", `{"f:spec":{"f:template":{}}}`),
+			entry("kubectl-rollout", metav1.ManagedFieldsOperationUpdate, now.Add(-2*time.Hour), "", `{"f:spec":{"f:template":{}}}`),
+		)
+
+		Expect(Attribute(deployment, history.Revisions{fake.ReplicaSetRevision(Default, 1, now.Add(-5*time.Hour), corev1.PodSpec{})})).To(BeEmpty())
+	})
+})
+
+func entry(manager string, operation metav1.ManagedFieldsOperationType, t time.Time, subresource, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   operation,
+		Time:        ptr.To(metav1.NewTime(t)),
+		Subresource: subresource,
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
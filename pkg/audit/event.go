@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// StageResponseComplete is the audit stage of events that are logged once the response has been sent.
+const StageResponseComplete = "ResponseComplete"
+
+// Event is an API server audit event (audit.k8s.io/v1) as written by the log backend. Only the fields needed for
+// finding the requests that caused revisions are decoded.
+type Event struct {
+	AuditID    string `json:"auditID"`
+	Stage      string `json:"stage"`
+	RequestURI string `json:"requestURI"`
+	// Verb is the kubernetes verb of the request, e.g., create, update, or patch.
+	Verb             string           `json:"verb"`
+	User             UserInfo         `json:"user"`
+	ImpersonatedUser *UserInfo        `json:"impersonatedUser,omitempty"`
+	SourceIPs        []string         `json:"sourceIPs,omitempty"`
+	UserAgent        string           `json:"userAgent,omitempty"`
+	ObjectRef        *ObjectReference `json:"objectRef,omitempty"`
+	ResponseStatus   *metav1.Status   `json:"responseStatus,omitempty"`
+
+	// RequestObject is the object from the request body. It is only logged with level Request or RequestResponse.
+	RequestObject json.RawMessage `json:"requestObject,omitempty"`
+	// ResponseObject is the object returned in the response body. It is only logged with level RequestResponse.
+	ResponseObject json.RawMessage `json:"responseObject,omitempty"`
+
+	RequestReceivedTimestamp metav1.MicroTime `json:"requestReceivedTimestamp"`
+	StageTimestamp           metav1.MicroTime `json:"stageTimestamp"`
+}
+
+// UserInfo identifies the user of a request.
+type UserInfo struct {
+	Username string   `json:"username"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// ObjectReference identifies the object of a request.
+type ObjectReference struct {
+	Resource    string `json:"resource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	APIGroup    string `json:"apiGroup,omitempty"`
+	APIVersion  string `json:"apiVersion,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+}
+
+// Username returns the name of the user that sent the request. For impersonated requests, the impersonating user is
+// included, e.g., `alice (as system:serviceaccount:ci:deployer)`.
+func (e *Event) Username() string {
+	if e.ImpersonatedUser != nil {
+		return fmt.Sprintf("%s (as %s)", e.User.Username, e.ImpersonatedUser.Username)
+	}
+	return e.User.Username
+}
+
+// SourceIP returns the source IPs of the request separated by commas.
+func (e *Event) SourceIP() string {
+	return strings.Join(e.SourceIPs, ",")
+}
+
+// Succeeded returns true if the request succeeded or if the response status was not logged.
+func (e *Event) Succeeded() bool {
+	return e.ResponseStatus == nil || e.ResponseStatus.Code == 0 || (e.ResponseStatus.Code >= 200 && e.ResponseStatus.Code < 300)
+}
+
+// Object returns the workload object as stored after the request, i.e., the response object. If the response object
+// was not logged, the request object of create and update requests is returned, which holds the full object but
+// without defaults. nil is returned if the event doesn't contain the full object, e.g., for patch requests without a
+// response object or for events with level Metadata.
+func (e *Event) Object() (client.Object, error) {
+	data := e.ResponseObject
+	if len(data) == 0 && (e.Verb == "create" || e.Verb == "update") {
+		data = e.RequestObject
+	}
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("error decoding object of audit event %s: %w", e.AuditID, err)
+	}
+	if u.GetKind() == "Status" {
+		// failed requests return a Status object
+		return nil, nil
+	}
+
+	typed, err := history.ToTyped(u)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding object of audit event %s: %w", e.AuditID, err)
+	}
+	return typed.(client.Object), nil
+}
+
+// ReadEvents reads audit events from the given reader in the format of the API server's log backend, i.e., one JSON
+// event per line. Empty lines are skipped.
+func ReadEvents(r io.Reader) ([]*Event, error) {
+	var (
+		events  []*Event
+		scanner = bufio.NewScanner(r)
+		line    int
+	)
+	// audit events with request and response objects can get large
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		event := &Event{}
+		if err := json.Unmarshal(data, event); err != nil {
+			return nil, fmt.Errorf("error decoding audit event in line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading audit events: %w", err)
+	}
+	return events, nil
+}
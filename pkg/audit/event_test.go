@@ -0,0 +1,80 @@
+package audit_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/audit"
+)
+
+var _ = Describe("Event", func() {
+	Describe("ReadEvents", func() {
+		It("should read one event per line", func() {
+			events, err := ReadEvents(strings.NewReader(`{"auditID":"a1","verb":"create","user":{"username":"alice"}}
+
+{"auditID":"a2","verb":"patch","user":{"username":"bob"},"sourceIPs":["10.0.0.1","10.0.0.2"]}
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveExactElements(
+				HaveField("AuditID", "a1"),
+				HaveField("AuditID", "a2"),
+			))
+			Expect(events[1].SourceIP()).To(Equal("10.0.0.1,10.0.0.2"))
+		})
+
+		It("should fail for invalid lines", func() {
+			Expect(ReadEvents(strings.NewReader("{}\nfoo\n"))).Error().To(MatchError(ContainSubstring("error decoding audit event in line 2")))
+		})
+	})
+
+	Describe("#Username", func() {
+		It("should include impersonation", func() {
+			event := &Event{User: UserInfo{Username: "alice"}}
+			Expect(event.Username()).To(Equal("alice"))
+
+			event.ImpersonatedUser = &UserInfo{Username: "system:serviceaccount:ci:deployer"}
+			Expect(event.Username()).To(Equal("alice (as system:serviceaccount:ci:deployer)"))
+		})
+	})
+
+	Describe("#Succeeded", func() {
+		It("should check the response code", func() {
+			Expect((&Event{}).Succeeded()).To(BeTrue())
+			Expect((&Event{ResponseStatus: &metav1.Status{Code: 201}}).Succeeded()).To(BeTrue())
+			Expect((&Event{ResponseStatus: &metav1.Status{Code: 409}}).Succeeded()).To(BeFalse())
+		})
+	})
+
+	Describe("#Object", func() {
+		It("should prefer the response object", func() {
+			event := &Event{
+				Verb:           "update",
+				RequestObject:  deploymentJSON("nginx:1.25"),
+				ResponseObject: deploymentJSON("nginx:1.26"),
+			}
+
+			obj, err := event.Object()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj).To(BeAssignableToTypeOf(&appsv1.Deployment{}))
+			Expect(obj.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.26"))
+		})
+
+		It("should fall back to the request object of update requests", func() {
+			obj, err := (&Event{Verb: "update", RequestObject: deploymentJSON("nginx:1.25")}).Object()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25"))
+		})
+
+		It("should not use the request object of patch requests", func() {
+			Expect((&Event{Verb: "patch", RequestObject: []byte(`{"spec":{"replicas":3}}`)}).Object()).To(BeNil())
+		})
+
+		It("should ignore Status objects", func() {
+			Expect((&Event{Verb: "update", ResponseObject: []byte(`{"apiVersion":"v1","kind":"Status","code":409}`)}).Object()).To(BeNil())
+		})
+	})
+})
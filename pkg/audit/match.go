@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"slices"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/attribution"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// RevisionEvent is an audit event of a request that caused a revision, i.e., that changed the pod template of the
+// workload object to the revision's pod template.
+type RevisionEvent struct {
+	Revision history.Revision
+	Event    *Event
+}
+
+// Match finds the audit events of the requests that caused the given revisions of the given workload object. resource
+// is the API resource of the workload object, e.g., deployments.apps.
+//
+// The events of successful create, update, and patch requests to the workload object are processed in chronological
+// order. The pod template of the object after each request is matched with the revisions' pod templates. An event
+// caused a revision if the pod template matches the revision but didn't match it before the request, e.g., requests
+// that only scaled the workload are skipped. If a rollback re-activates an old revision, the revision has multiple
+// events.
+// If the pod template before a request is unknown (e.g., for the first event in the log or after events without the
+// full object), the event is only considered if it occurred around the creation of the matching revision (within
+// attribution.Tolerance).
+//
+// The result is sorted by time.
+func Match(events []*Event, obj client.Object, resource schema.GroupResource, revs history.Revisions) ([]RevisionEvent, error) {
+	var relevant []*Event
+	for _, event := range events {
+		if isWriteTo(event, obj, resource) {
+			relevant = append(relevant, event)
+		}
+	}
+	slices.SortStableFunc(relevant, func(a, b *Event) int {
+		return a.StageTimestamp.Compare(b.StageTimestamp.Time)
+	})
+
+	var (
+		result []RevisionEvent
+		// previous is the revision matching the pod template before the current event, nil if there is none
+		previous history.Revision
+		// known is false if the pod template before the current event is unknown
+		known bool
+	)
+
+	for _, event := range relevant {
+		object, err := event.Object()
+		if err != nil {
+			return nil, err
+		}
+		if object == nil {
+			known = false
+			continue
+		}
+		if object.GetName() != obj.GetName() {
+			// create request for another object
+			continue
+		}
+
+		rev, err := matchRevision(object, revs)
+		if err != nil {
+			return nil, err
+		}
+
+		caused := rev != nil && (rev != previous || !known)
+		if caused && !known {
+			caused = event.StageTimestamp.Sub(rev.Object().GetCreationTimestamp().Time).Abs() <= attribution.Tolerance
+		}
+		if caused {
+			result = append(result, RevisionEvent{Revision: rev, Event: event})
+		}
+
+		previous, known = rev, true
+	}
+
+	return result, nil
+}
+
+// isWriteTo returns true if the event belongs to a completed and successful write request to the given object.
+func isWriteTo(event *Event, obj client.Object, resource schema.GroupResource) bool {
+	if event.Stage != StageResponseComplete || !event.Succeeded() {
+		return false
+	}
+	if event.Verb != "create" && event.Verb != "update" && event.Verb != "patch" {
+		return false
+	}
+
+	ref := event.ObjectRef
+	if ref == nil || ref.Subresource != "" || ref.APIGroup != resource.Group || ref.Resource != resource.Resource ||
+		ref.Namespace != obj.GetNamespace() {
+		return false
+	}
+
+	// the name of create requests is only known if the request object was logged
+	return ref.Name == obj.GetName() || (ref.Name == "" && event.Verb == "create")
+}
+
+// matchRevision returns the newest revision whose pod template equals the pod template of the given object. If there
+// is none, the newest revision whose pod template contains all fields of the given object's pod template is returned,
+// e.g., for request objects that haven't been defaulted by the API server yet.
+func matchRevision(object client.Object, revs history.Revisions) (history.Revision, error) {
+	template, err := history.PodTemplateOf(object)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(revs) - 1; i >= 0; i-- {
+		if apiequality.Semantic.DeepEqual(revs[i].PodTemplate(), template) {
+			return revs[i], nil
+		}
+	}
+
+	for i := len(revs) - 1; i >= 0; i-- {
+		contains, err := history.PodTemplateContains(revs[i].PodTemplate(), template)
+		if err != nil {
+			return nil, err
+		}
+		if contains {
+			return revs[i], nil
+		}
+	}
+
+	return nil, nil
+}
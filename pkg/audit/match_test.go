@@ -0,0 +1,134 @@
+package audit_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/audit"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+)
+
+var _ = Describe("Match", func() {
+	var (
+		start      time.Time
+		deployment *appsv1.Deployment
+		resource   = schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+		rev1, rev2 history.Revision
+		revs       history.Revisions
+	)
+
+	BeforeEach(func() {
+		start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"}}
+
+		rev1 = fake.ReplicaSetRevision(Default, 1, start, podTemplate("nginx:1.25").Spec)
+		rev2 = fake.ReplicaSetRevision(Default, 2, start.Add(24*time.Hour), podTemplate("nginx:1.26").Spec)
+		revs = history.Revisions{rev1, rev2}
+	})
+
+	It("should find the requests that changed the pod template", func() {
+		events := []*Event{
+			writeEvent("a3", "patch", start.Add(24*time.Hour), deploymentJSON("nginx:1.26")),
+			writeEvent("a1", "create", start, deploymentJSON("nginx:1.25")),
+			// scaling doesn't change the pod template
+			writeEvent("a2", "patch", start.Add(time.Hour), deploymentJSON("nginx:1.25")),
+			// rollback
+			writeEvent("a4", "update", start.Add(48*time.Hour), deploymentJSON("nginx:1.25")),
+		}
+
+		Expect(Match(events, deployment, resource, revs)).To(HaveExactElements(
+			RevisionEvent{Revision: rev1, Event: events[1]},
+			RevisionEvent{Revision: rev2, Event: events[0]},
+			RevisionEvent{Revision: rev1, Event: events[3]},
+		))
+	})
+
+	It("should ignore events of other objects, failed requests, and other stages", func() {
+		other := writeEvent("a1", "create", start, deploymentJSON("nginx:1.25"))
+		other.ObjectRef.Name = "other"
+
+		failed := writeEvent("a2", "update", start, deploymentJSON("nginx:1.25"))
+		failed.ResponseStatus = &metav1.Status{Code: 409}
+
+		received := writeEvent("a3", "create", start, deploymentJSON("nginx:1.25"))
+		received.Stage = "RequestReceived"
+
+		scale := writeEvent("a4", "update", start, deploymentJSON("nginx:1.25"))
+		scale.ObjectRef.Subresource = "scale"
+
+		Expect(Match([]*Event{other, failed, received, scale}, deployment, resource, revs)).To(BeEmpty())
+	})
+
+	It("should only consider events around the revision's creation if the previous pod template is unknown", func() {
+		events := []*Event{
+			// the log starts after the creation of revision 1
+			writeEvent("a1", "patch", start.Add(time.Hour), deploymentJSON("nginx:1.25")),
+			// metadata-level event
+			writeEvent("a2", "patch", start.Add(23*time.Hour), nil),
+			writeEvent("a3", "patch", start.Add(24*time.Hour), deploymentJSON("nginx:1.26")),
+		}
+
+		Expect(Match(events, deployment, resource, revs)).To(HaveExactElements(
+			RevisionEvent{Revision: rev2, Event: events[2]},
+		))
+	})
+
+	It("should match request objects without defaults", func() {
+		rs := rev2.Object().(*appsv1.ReplicaSet).DeepCopy()
+		rs.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+		defaulted, err := history.NewReplicaSet(rs)
+		Expect(err).NotTo(HaveOccurred())
+
+		event := writeEvent("a1", "update", start.Add(24*time.Hour), nil)
+		event.RequestObject = deploymentJSON("nginx:1.26")
+
+		Expect(Match([]*Event{event}, deployment, resource, history.Revisions{rev1, defaulted})).To(HaveExactElements(
+			RevisionEvent{Revision: defaulted, Event: event},
+		))
+	})
+})
+
+func writeEvent(auditID, verb string, t time.Time, responseObject []byte) *Event {
+	return &Event{
+		AuditID: auditID,
+		Stage:   StageResponseComplete,
+		Verb:    verb,
+		User:    UserInfo{Username: "alice"},
+		ObjectRef: &ObjectReference{
+			Resource:   "deployments",
+			Namespace:  "default",
+			Name:       "nginx",
+			APIGroup:   "apps",
+			APIVersion: "v1",
+		},
+		ResponseStatus: &metav1.Status{Code: 200},
+		ResponseObject: responseObject,
+		StageTimestamp: metav1.NewMicroTime(t),
+	}
+}
+
+func podTemplate(image string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nginx"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: image}}},
+	}
+}
+
+func deploymentJSON(image string) []byte {
+	data, err := json.Marshal(&appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Template: podTemplate(image)},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return data
+}
@@ -0,0 +1,276 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	utilcomp "k8s.io/kubectl/pkg/util/completion"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/audit"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/offline"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+type Options struct {
+	genericiooptions.IOStreams
+
+	Namespace    string
+	FromFiles    []string
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+
+	AuditLogs  []string
+	Revision   string
+	Selector   history.Selector
+	PrintFlags *genericclioptions.PrintFlags
+	NoHeaders  bool
+}
+
+func NewOptions(streams genericiooptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:    streams,
+		PrintFlags:   genericclioptions.NewPrintFlags("").WithTypeSetter(scheme.Scheme),
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+	}
+}
+
+func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use: "audit (TYPE[.VERSION][.GROUP] NAME | TYPE[.VERSION][.GROUP]/NAME) --audit-log=FILE",
+
+		Short: "Show who caused each revision of a workload resource based on API server audit logs",
+		Long: `Show who caused each revision of a workload resource based on API server audit logs.
+
+The given audit log files (JSON lines of audit.k8s.io/v1 Events as written by the API server's log backend) are searched
+for create, update, and patch requests to the workload resource. The pod template of the object after each request is
+matched with the pod templates of the revisions. For every request that changed the pod template to a revision's pod
+template, the user (including impersonation), the verb, the user agent, the source IP, and the time of the request are
+printed. Requests that didn't change the pod template (e.g., scaling) are skipped. If a revision was rolled out multiple
+times (e.g., by a rollback), it is printed once per request. Revisions without a matching request are printed with an
+unknown user. With other output formats than the default and wide format (e.g., -o yaml), the matching audit events
+are printed as a List instead.
+
+Matching requires the object after the request in the audit events, i.e., the audit policy must log the workload
+resources with level RequestResponse. With level Request, only create and update requests can be matched, as the
+request object of patch requests doesn't hold the full object.
+
+In contrast to the MANAGER column of "kubectl revisions get -o wide", which is based on managedFields and only knows the
+last write of each field manager, the audit log shows the full chain of requests.
+`,
+
+		Example: `# Show who caused each revision of the nginx Deployment
+kubectl revisions audit deploy nginx --audit-log=/var/log/kubernetes/audit.log
+
+# Search multiple (rotated) audit log files
+kubectl revisions audit deploy nginx --audit-log=audit.log --audit-log=audit-2024-01-02T00-00-00.000.log
+
+# Show who caused the latest revision including the audit IDs of the requests
+kubectl revisions audit deploy nginx --audit-log=audit.log --revision=latest -o wide
+
+# Print the audit events of the requests that caused the revisions
+kubectl revisions audit deploy nginx --audit-log=audit.log -o yaml
+`,
+
+		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.AuditLogs, "audit-log", o.AuditLogs, "Audit log file of the API server to search for the requests "+
+		"that caused the revisions, - reads from stdin. Can be specified multiple times.")
+	cmdutil.CheckErr(cmd.MarkFlagRequired("audit-log"))
+	cmdutil.CheckErr(cmd.MarkFlagFilename("audit-log", "log", "json", "jsonl"))
+
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().Lookup("output").Usage = fmt.Sprintf("Output format. One of: (%s).", strings.Join(o.allowedFormats(), ", "))
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When using the default output format, don't print headers (default print headers).")
+	cmd.Flags().StringVarP(&o.Revision, "revision", "r", o.Revision, "Only show the requests that caused the specified revision. "+
+		"Specify -1 for the latest revision, -2 for the one before the latest, etc. "+
+		util.RevisionSelectorHelp)
+	util.AddFromFileFlag(cmd, &o.FromFiles)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+func (o *Options) allowedFormats() []string {
+	return append([]string{"wide"}, o.PrintFlags.AllowedFormats()...)
+}
+
+func (o *Options) outputFormat() string {
+	if o.PrintFlags.OutputFormat == nil {
+		return ""
+	}
+	return *o.PrintFlags.OutputFormat
+}
+
+// tableOutput returns true if the requests should be printed as a table.
+func (o *Options) tableOutput() bool {
+	return o.outputFormat() == "" || o.outputFormat() == "wide"
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f util.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if o.Revision != "" {
+		o.Selector, err = history.ParseSelector(o.Revision)
+	}
+	return err
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	// template formats carry their argument, e.g., jsonpath={.items}
+	if format, _, _ := strings.Cut(o.outputFormat(), "="); format != "" && !slices.Contains(o.allowedFormats(), format) {
+		return genericclioptions.NoCompatiblePrinterError{OutputFormat: o.PrintFlags.OutputFormat, AllowedFormats: o.allowedFormats()}
+	}
+	if slices.Contains(o.AuditLogs, offline.StdinPath) && slices.Contains(o.FromFiles, offline.StdinPath) {
+		return fmt.Errorf("--audit-log and --from-file cannot both read from stdin")
+	}
+	return nil
+}
+
+// Run performs the audit operation.
+func (o *Options) Run(ctx context.Context, f util.Factory, args []string) error {
+	objectRevisions, err := util.ListObjectRevisions(ctx, f, util.ObjectRevisionsOptions{
+		Namespace:    o.Namespace,
+		FromFiles:    o.FromFiles,
+		In:           o.In,
+		HistoryFlags: o.HistoryFlags,
+		ArchiveFlags: o.ArchiveFlags,
+	}, args)
+	if err != nil {
+		return err
+	}
+
+	events, err := o.readAuditLogs()
+	if err != nil {
+		return err
+	}
+
+	revs := objectRevisions.Revisions
+	revisionEvents, err := audit.Match(events, objectRevisions.Info.Object.(client.Object), objectRevisions.Info.Mapping.Resource.GroupResource(), revs)
+	if err != nil {
+		return err
+	}
+	if len(revisionEvents) == 0 {
+		_, _ = fmt.Fprintf(o.ErrOut, "Warning: no requests found in the audit logs that caused revisions of %s.\n", objectRevisions)
+	}
+
+	if o.Selector != nil {
+		rev, err := o.Selector.Select(revs)
+		if err != nil {
+			return err
+		}
+		revs = history.Revisions{rev}
+	}
+
+	if o.tableOutput() {
+		p := printers.NewTablePrinter(printers.PrintOptions{
+			NoHeaders: o.NoHeaders,
+			Wide:      o.outputFormat() == "wide",
+		})
+		return p.PrintObj(printer.AuditTable(revs, revisionEvents), o.Out)
+	}
+
+	p, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	// collect the audit events of the selected revisions in an unstructured list, which is properly handled by all used
+	// printers
+	list := &unstructured.UnstructuredList{
+		Object: map[string]interface{}{
+			"kind":       "List",
+			"apiVersion": "v1",
+			"metadata": map[string]interface{}{
+				"resourceVersion": "",
+			},
+		},
+	}
+
+	for _, e := range revisionEvents {
+		if !slices.Contains(revs, e.Revision) {
+			continue
+		}
+
+		item, err := toUnstructured(e.Event)
+		if err != nil {
+			return err
+		}
+		list.Items = append(list.Items, *item)
+	}
+
+	return p.PrintObj(list, o.Out)
+}
+
+// toUnstructured converts the given audit event to an unstructured audit.k8s.io/v1 Event.
+func toUnstructured(event *audit.Event) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling audit event %s: %w", event.AuditID, err)
+	}
+
+	item := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &item.Object); err != nil {
+		return nil, fmt.Errorf("error converting audit event %s: %w", event.AuditID, err)
+	}
+	item.SetAPIVersion("audit.k8s.io/v1")
+	item.SetKind("Event")
+	return item, nil
+}
+
+// readAuditLogs reads the audit events from all given audit log files.
+func (o *Options) readAuditLogs() ([]*audit.Event, error) {
+	var events []*audit.Event
+	for _, path := range o.AuditLogs {
+		fileEvents, err := readAuditLog(path, o.In)
+		if err != nil {
+			return nil, fmt.Errorf("error reading audit log %s: %w", path, err)
+		}
+		events = append(events, fileEvents...)
+	}
+	return events, nil
+}
+
+func readAuditLog(path string, in io.Reader) ([]*audit.Event, error) {
+	if path == offline.StdinPath {
+		return audit.ReadEvents(in)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return audit.ReadEvents(file)
+}
@@ -0,0 +1,182 @@
+package blame
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta/table"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	utilcomp "k8s.io/kubectl/pkg/util/completion"
+
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/diff"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+type Options struct {
+	genericiooptions.IOStreams
+
+	Namespace    string
+	FromFiles    []string
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+	Revision     int64
+}
+
+func NewOptions(streams genericiooptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:    streams,
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+		Revision:     -1,
+	}
+}
+
+func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use: "blame (TYPE[.VERSION][.GROUP] NAME | TYPE[.VERSION][.GROUP]/NAME)",
+
+		Short: "Show which revision last changed each line of a workload resource's pod template",
+		Long: `Show which revision last changed each line of a workload resource's pod template.
+
+The pod template of the latest revision is printed in YAML. Each line is annotated with the number and age of the
+revision that introduced it. Like "git blame", all revisions are walked in order and every pod template is compared with
+the pod template of its predecessor.
+
+The history is based on the ReplicaSets/ControllerRevisions still in the system. I.e., the history is limited by the
+configured revisionHistoryLimit. Lines that didn't change since the oldest revision still in the system are attributed
+to the oldest revision.
+If the --archive flag is given, revisions recorded in the local archive using "kubectl revisions record" are merged with
+the revisions still in the system.
+
+The --revision flag allows selecting another revision than the latest one to annotate.
+
+If the --from-file flag is given, the workload resource and its revisions are read from the given files or directories
+(e.g., dumps created with "kubectl get -o yaml" or must-gather archives) instead of from a live cluster.
+`,
+
+		Example: `# Find out which revision changed each line of the nginx Deployment's pod template
+kubectl revisions blame deploy nginx
+
+# Annotate the pod template of the revision before the latest one
+kubectl revisions blame deploy nginx --revision=-2
+`,
+
+		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+		},
+	}
+
+	cmd.Flags().Int64VarP(&o.Revision, "revision", "r", o.Revision, "Annotate the pod template of the specified revision. "+
+		"Specify -1 for the latest revision, -2 for the one before the latest, etc.")
+	util.AddFromFileFlag(cmd, &o.FromFiles)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f util.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	return err
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	if o.Revision == 0 {
+		return fmt.Errorf("invalid revision 0")
+	}
+
+	return nil
+}
+
+// Run performs the blame operation.
+func (o *Options) Run(ctx context.Context, f util.Factory, args []string) error {
+	objectRevisions, err := util.ListObjectRevisions(ctx, f, util.ObjectRevisionsOptions{
+		Namespace:    o.Namespace,
+		FromFiles:    o.FromFiles,
+		In:           o.In,
+		HistoryFlags: o.HistoryFlags,
+		ArchiveFlags: o.ArchiveFlags,
+	}, args)
+	if err != nil {
+		return err
+	}
+
+	selected, err := objectRevisions.Revisions.ByNumber(o.Revision)
+	if err != nil {
+		return err
+	}
+
+	// walk all revisions up to the selected one in order
+	var (
+		revs     history.Revisions
+		versions [][]string
+	)
+	for _, rev := range objectRevisions.Revisions {
+		if rev.Number() > selected.Number() {
+			break
+		}
+
+		lines, err := templateLines(rev)
+		if err != nil {
+			return err
+		}
+
+		revs = append(revs, rev)
+		versions = append(versions, lines)
+	}
+
+	blamed := diff.Blame(versions)
+
+	// align the annotations of all lines
+	var (
+		numbers = make([]string, len(revs))
+		ages    = make([]string, len(revs))
+
+		numberWidth, ageWidth int
+	)
+	for i, rev := range revs {
+		numbers[i] = strconv.FormatInt(rev.Number(), 10)
+		ages[i] = table.ConvertToHumanReadableDateType(rev.Object().GetCreationTimestamp())
+		numberWidth = max(numberWidth, len(numbers[i]))
+		ageWidth = max(ageWidth, len(ages[i]))
+	}
+
+	for _, line := range blamed {
+		if _, err := fmt.Fprintf(o.Out, "%*s %-*s  %s\n", numberWidth, numbers[line.Origin], ageWidth, ages[line.Origin], line.Line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// templateLines returns the lines of the given revision's pod template printed in YAML.
+func templateLines(rev history.Revision) ([]string, error) {
+	var buf bytes.Buffer
+	p := printer.RevisionPrinter{
+		Delegate:     printers.NewTypeSetter(scheme.Scheme).ToPrinter(&printers.YAMLPrinter{}),
+		TemplateOnly: true,
+	}
+	if err := p.PrintObj(rev, &buf); err != nil {
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
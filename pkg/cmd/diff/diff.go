@@ -4,31 +4,64 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	utilcomp "k8s.io/kubectl/pkg/util/completion"
-	"k8s.io/utils/exec"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
 	"github.com/timebertt/kubectl-revisions/pkg/diff"
 	"github.com/timebertt/kubectl-revisions/pkg/history"
-	"github.com/timebertt/kubectl-revisions/pkg/runutil"
+	"github.com/timebertt/kubectl-revisions/pkg/offline"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
 )
 
+// FormatFieldPath is an output format that prints one line per changed field instead of a diff of the printed revisions.
+const FormatFieldPath = "fieldpath"
+
+// ErrDifferencesFound is returned by Options.Run in exit code mode if relevant differences were found.
+var ErrDifferencesFound = errors.New("differences found")
+
 type Options struct {
 	genericiooptions.IOStreams
 
-	Namespace  string
-	Revisions  []int64
-	PrintFlags *util.PrintFlags
-
-	Diff diff.Program
+	Namespace    string
+	FromFiles    []string
+	Filename     string
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+	Revisions    []string
+	Selectors    []history.Selector
+	PrintFlags   *util.PrintFlags
+
+	// Range is set if a revision range is given, e.g., --revision=3..7.
+	Range *history.Range
+	// Ignore configures fields that are removed from both sides before comparing. Unset fields default to the
+	// configuration file.
+	Ignore diff.Ignore
+
+	// ExitCode enables the exit code mode: instead of printing a diff, a machine-readable summary of the changes is
+	// printed and the command exits with 1 if relevant changes were found.
+	ExitCode bool
+	// FailOnPaths are the field paths that are considered relevant in exit code mode. If empty, all changes are relevant.
+	FailOnPaths []string
+	failOn      []diff.FieldPathPattern
+	report      *diff.ChangeReport
+
+	// AgainstContext and AgainstNamespace select the workload resource to compare with in another cluster or namespace.
+	AgainstContext   string
+	AgainstNamespace string
+	// Identical lists the revisions with identical pod templates on both sides instead of comparing revisions.
+	Identical bool
+
+	DiffEngine diff.Engine
+	Diff       diff.Program
 }
 
 func NewOptions(streams genericiooptions.IOStreams) *Options {
@@ -39,11 +72,17 @@ func NewOptions(streams genericiooptions.IOStreams) *Options {
 	printFlags.TableFlags = nil
 	printFlags.CustomColumnsFlags = nil
 	printFlags.NamePrintFlags = nil
+	printFlags.CommandFormats = []string{FormatFieldPath}
+	for _, patchType := range diff.PatchTypes {
+		printFlags.CommandFormats = append(printFlags.CommandFormats, string(patchType))
+	}
 
 	return &Options{
-		IOStreams:  streams,
-		PrintFlags: printFlags,
-		Diff:       diff.NewProgram(streams),
+		IOStreams:    streams,
+		PrintFlags:   printFlags,
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+		DiffEngine:   diff.EngineAuto,
 	}
 }
 
@@ -55,19 +94,63 @@ func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Comma
 		Aliases: []string{"why"},
 
 		Short: "Compare multiple revisions of a workload resource",
-		Long: `Compare multiple revisions of a workload resource (Deployment, StatefulSet, or DaemonSet).
+		Long: `Compare multiple revisions of a workload resource (Deployment, StatefulSet, DaemonSet, or Argo Rollout).
 A.k.a., "Why was my Deployment rolled?"
 
 The history is based on the ReplicaSets/ControllerRevisions still in the system. I.e., the history is limited by the
 configured revisionHistoryLimit.
+If the --archive flag is given, revisions recorded in the local archive using "kubectl revisions record" are merged with
+the revisions still in the system.
 
 By default, the latest two revisions are compared. The --revision flag allows selecting the revisions to compare.
+Besides revision numbers, the revision that is currently running (current) and the one that is being rolled out
+(update) can be selected. Revisions can also be selected by name or pod-template-hash (e.g., nginx-5d8f7c or 5d8f7c),
+latest, @{2h} for the revision that was live 2 hours ago (based on creation timestamps), image=nginx:1.25 for the
+newest revision running the given image, and commit:3f2a9c1 for the newest revision deployed from the given commit.
+A revision range like --revision=3..7 compares every pair of consecutive revisions in the range (3 and 4, 4 and 5,
+etc.), so that the entire evolution of the workload resource can be reviewed in one go. Each diff is preceded by a
+header line naming the compared revisions. Either end of the range can be omitted, e.g., --revision=3.. compares all
+revisions starting from revision 3.
+
+With --output=fieldpath, the revisions are compared field by field and every changed field is printed in a single line,
+e.g., "spec.containers[name=app].image: nginx:1.25 -> nginx:1.26". List items are matched by their merge key (e.g., the
+container or env var name) so that reordered lists don't show up as changes.
+
+With --output=json-patch, merge-patch, or strategic-merge-patch, a patch of the given type is printed that transforms the
+older revision's pod template (or the full revision object with --template-only=false) into the newer one's, e.g., for
+feeding change descriptions into audit pipelines.
+
+Fields that change with every rollout but are irrelevant for the comparison can be removed from both sides using the
+--ignore-path, --ignore-annotation, and --ignore-label flags. Field paths use the same syntax as printed by
+--output=fieldpath. Defaults for these flags can be configured in the configuration file under diff.ignore.
+
+With --exit-code, a machine-readable JSON summary of the changed fields is printed instead of a diff, and the command
+exits with 1 if there are relevant changes, 0 if there are none, and 2 on errors, e.g., for use in scripts and CI gates.
+Use --fail-on-path to consider only changes of the given fields relevant.
+
+If the --from-file flag is given, the workload resource and its revisions are read from the given files or directories
+(e.g., dumps created with "kubectl get -o yaml" or must-gather archives) instead of from a live cluster.
+
+If the --filename (-f) flag is given, a revision (the latest by default) is compared with the workload resource in the
+given manifest file, e.g., before applying it. Use "-f -" to read the manifest from stdin. If no workload resource is
+given as arguments, the one contained in the manifest is used. The manifest is defaulted by the API server using a
+dry-run request so that fields omitted in the manifest don't show up as removed. With --from-file, the manifest is not
+defaulted, i.e., it should contain all fields of the revisions that should not show up as removed.
+
+If the --against-context or --against-namespace flag is given, the workload resource is compared with the same workload
+resource in another kubeconfig context (e.g., another cluster) or namespace. The first revision given via --revision
+(latest by default) is selected from the compared workload resource, the second one (defaulting to the first one) from
+the other workload resource. With --identical, the revisions with identical pod templates on both sides are listed
+instead, e.g., for finding out which revision in production runs the same pod template as staging.
 
 The ` + "`" + `KUBECTL_EXTERNAL_DIFF` + "`" + ` environment variable can be used to select your own diff command. Users can use external
 commands with params too, e.g.: ` + "`" + `KUBECTL_EXTERNAL_DIFF="colordiff -N -u"` + "`" + `
 
 By default, the ` + "`" + `diff` + "`" + ` command available in your path will be run with the ` + "`" + `-u` + "`" + ` (unified diff) and ` + "`" + `-N` + "`" + ` (treat absent
-files as empty) options.`,
+files as empty) options.
+
+If the external diff program cannot be found in your path, a builtin diff engine is used that produces a unified diff
+without any external dependencies. Use --diff-engine to explicitly select the builtin or external diff engine.`,
 
 		Example: `# Find out why the nginx Deployment was rolled: compare the latest two revisions
 kubectl revisions diff deploy nginx
@@ -78,6 +161,48 @@ kubectl revisions diff deploy nginx --revision=1,3
 # Compare the previous revision and the revision before that
 kubectl revisions diff deploy nginx --revision=-2
 
+# Compare the revision that is currently running with the revision that is being rolled out
+kubectl revisions diff deploy nginx --revision=current,update
+
+# Compare the revision that was live a day ago with the latest revision
+kubectl revisions diff deploy nginx --revision=@{24h},latest
+
+# Review all changes from revision 3 to revision 7 as a series of diffs (3 and 4, 4 and 5, etc.)
+kubectl revisions diff deploy nginx --revision=3..7
+
+# Review all changes of the last week
+kubectl revisions diff deploy nginx --revision=@{168h}..latest
+
+# Compare the latest two revisions of the nginx Deployment in a directory of YAML dumps instead of a live cluster
+kubectl revisions diff deploy nginx --from-file=dump/
+
+# Print the changed fields of the latest revision compared to its predecessor
+kubectl revisions diff deploy nginx -o fieldpath
+
+# Print a JSON patch (RFC 6902) that transforms the previous revision into the latest revision
+kubectl revisions diff deploy nginx -o json-patch
+
+# Fail if the latest rollout changed anything besides the container images
+kubectl revisions diff deploy nginx --exit-code --ignore-path='spec.containers[*].image'
+
+# Fail if the latest rollout changed any container resources
+kubectl revisions diff deploy nginx --fail-on-path='spec.containers[*].resources'
+
+# Ignore the restart annotation and a CI build id env var
+kubectl revisions diff deploy nginx --ignore-annotation=kubectl.kubernetes.io/restartedAt --ignore-path='spec.containers[*].env[name=BUILD_ID]'
+
+# Preview what applying a manifest would change compared to the latest revision
+kubectl revisions diff -f deploy.yaml
+
+# Compare revision 1 with a manifest read from stdin
+helm template my-release ./chart | kubectl revisions diff deploy nginx --revision=1 -f -
+
+# Compare the latest revision in the staging cluster with the latest revision in the prod cluster
+kubectl revisions diff deploy nginx --context=staging --against-context=prod
+
+# Find out which revision in the prod cluster is identical to the revision currently running in staging
+kubectl revisions diff deploy nginx --context=staging --against-context=prod --identical --revision=current
+
 # Use a colored external diff program
 KUBECTL_EXTERNAL_DIFF="colordiff -u" kubectl revisions diff deploy nginx
 
@@ -86,21 +211,62 @@ KUBECTL_EXTERNAL_DIFF="dyff between --omit-header" kubectl revisions diff deploy
 
 # Show diff in VS Code
 KUBECTL_EXTERNAL_DIFF="code --diff --wait" kubectl revisions diff deploy nginx
+
+# Use the builtin diff engine instead of an external diff program
+kubectl revisions diff deploy nginx --diff-engine=builtin
 `,
 
 		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(f))
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+			// In exit code mode, exit code 1 signals relevant differences and errors are signaled by exit code 2 like
+			// with diff and kubectl diff.
+			checkErr := cmdutil.CheckErr
+			if o.ExitCode || len(o.FailOnPaths) > 0 {
+				checkErr = cmdutil.CheckDiffErr
+			}
+
+			checkErr(o.Complete(f))
+			checkErr(o.Validate())
+			if err := o.Run(cmd.Context(), f, args); errors.Is(err, ErrDifferencesFound) {
+				cmdutil.CheckErr(cmdutil.ErrExit)
+			} else {
+				checkErr(err)
+			}
 		},
 	}
 
 	o.PrintFlags.AddFlags(cmd)
 
-	cmd.Flags().Int64SliceVarP(&o.Revisions, "revision", "r", nil, "Compare the specified revision with its predecessor. "+
-		"Specify -1 for the latest revision, -2 for the one before the latest, etc.\n"+
-		"If given twice, compare the specified two revisions. If not given, compare the latest two revisions.")
+	cmd.Flags().StringSliceVarP(&o.Revisions, "revision", "r", nil, "Compare the specified revision with its predecessor. "+
+		"Specify -1 for the latest revision, -2 for the one before the latest, etc. "+
+		util.RevisionSelectorHelp+"\n"+
+		"If given twice, compare the specified two revisions. If not given, compare the latest two revisions. "+
+		"A range like 3..7 compares every pair of consecutive revisions in the range (either end can be omitted).")
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "Compare the selected revision (the latest one by default) "+
+		"with the pod template of the workload resource in the given manifest file instead of another revision, e.g., before applying it. "+
+		"- reads from stdin.")
+	cmdutil.CheckErr(cmd.MarkFlagFilename("filename", "yaml", "yml", "json"))
+	cmd.Flags().StringVar(&o.AgainstContext, "against-context", o.AgainstContext, "Compare with the same workload resource "+
+		"in the given kubeconfig context, e.g., in another cluster.")
+	cmd.Flags().StringVar(&o.AgainstNamespace, "against-namespace", o.AgainstNamespace, "Compare with the same workload "+
+		"resource in the given namespace (defaults to the namespace of the compared workload resource).")
+	cmd.Flags().BoolVar(&o.Identical, "identical", o.Identical, "List the revisions that have identical pod templates "+
+		"in both contexts/namespaces instead of comparing revisions. Requires --against-context or --against-namespace.")
+	cmd.Flags().StringSliceVar(&o.Ignore.Paths, "ignore-path", o.Ignore.Paths, "Field paths to ignore when comparing revisions "+
+		"in the same syntax as printed by -o fieldpath, e.g., spec.containers[name=app].env[name=BUILD_ID] or "+
+		"metadata.annotations['ci.example.com/build-id']. [*] matches all list items.")
+	cmd.Flags().StringSliceVar(&o.Ignore.Annotations, "ignore-annotation", o.Ignore.Annotations, "Annotation keys to ignore "+
+		"when comparing revisions, e.g., kubectl.kubernetes.io/restartedAt.")
+	cmd.Flags().StringSliceVar(&o.Ignore.Labels, "ignore-label", o.Ignore.Labels, "Label keys to ignore when comparing revisions.")
+	cmd.Flags().BoolVar(&o.ExitCode, "exit-code", o.ExitCode, "Print a machine-readable JSON summary of the changed fields "+
+		"instead of a diff and exit with 1 if there are relevant changes, 0 if there are none, and 2 on errors.")
+	cmd.Flags().StringSliceVar(&o.FailOnPaths, "fail-on-path", o.FailOnPaths, "Field paths that are considered relevant "+
+		"changes for --exit-code, including nested and parent fields, e.g., spec.containers[*].resources. "+
+		"If not given, all changes are relevant. Implies --exit-code.")
+	util.AddDiffEngineFlag(cmd, &o.DiffEngine)
+	util.AddFromFileFlag(cmd, &o.FromFiles)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddFlags(cmd)
 
 	return cmd
 }
@@ -109,12 +275,59 @@ KUBECTL_EXTERNAL_DIFF="code --diff --wait" kubectl revisions diff deploy nginx
 func (o *Options) Complete(f util.Factory) error {
 	var err error
 	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	// default to the latest revision if none is given, when listing identical revisions default to all revisions
+	if len(o.Revisions) == 0 && !o.Identical {
+		o.Revisions = []string{history.SelectorLatest}
+	}
+
+	o.Selectors = make([]history.Selector, 0, len(o.Revisions))
+	for _, revision := range o.Revisions {
+		if history.IsRange(revision) {
+			if o.Range, err = history.ParseRange(revision); err != nil {
+				return err
+			}
+			continue
+		}
+
+		selector, err := history.ParseSelector(revision)
+		if err != nil {
+			return err
+		}
+		o.Selectors = append(o.Selectors, selector)
+	}
+
+	o.failOn = make([]diff.FieldPathPattern, 0, len(o.FailOnPaths))
+	for _, path := range o.FailOnPaths {
+		pattern, err := diff.ParseFieldPathPattern(path)
+		if err != nil {
+			return err
+		}
+		o.failOn = append(o.failOn, pattern)
+	}
+	if len(o.FailOnPaths) > 0 {
+		o.ExitCode = true
+	}
 
-	// default to the latest revision if none is given
-	if len(o.Revisions) == 0 {
-		o.Revisions = []int64{-1}
+	// default to the ignored fields in the configuration file, the --ignore-* flags replace the configured values
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+	if o.Ignore.Paths == nil {
+		o.Ignore.Paths = cfg.Diff.Ignore.Paths
+	}
+	if o.Ignore.Annotations == nil {
+		o.Ignore.Annotations = cfg.Diff.Ignore.Annotations
+	}
+	if o.Ignore.Labels == nil {
+		o.Ignore.Labels = cfg.Diff.Ignore.Labels
 	}
 
+	o.Diff, err = diff.NewProgramForEngine(o.DiffEngine, o.IOStreams)
 	return err
 }
 
@@ -124,9 +337,44 @@ func (o *Options) Validate() error {
 		return fmt.Errorf("expected at maximum 2 revisions, but got %d", len(o.Revisions))
 	}
 
-	for _, revision := range o.Revisions {
-		if revision == 0 {
-			return fmt.Errorf("invalid revision 0")
+	if err := o.Ignore.Validate(); err != nil {
+		return err
+	}
+
+	if o.Range != nil {
+		if len(o.Revisions) > 1 {
+			return fmt.Errorf("a revision range cannot be combined with other revisions")
+		}
+		if o.Filename != "" || o.against() {
+			return fmt.Errorf("a revision range cannot be combined with --filename, --against-context, or --against-namespace")
+		}
+	}
+
+	if o.Filename != "" {
+		if len(o.Revisions) > 1 {
+			return fmt.Errorf("expected at maximum 1 revision when comparing with a manifest, but got %d", len(o.Revisions))
+		}
+		if o.Filename == offline.StdinPath && slices.Contains(o.FromFiles, offline.StdinPath) {
+			return fmt.Errorf("--filename and --from-file cannot both read from stdin")
+		}
+		if o.against() {
+			return fmt.Errorf("--filename cannot be combined with --against-context or --against-namespace")
+		}
+	}
+
+	if o.AgainstContext == "" && o.AgainstNamespace != "" && slices.Contains(o.FromFiles, offline.StdinPath) {
+		return fmt.Errorf("--against-namespace cannot be combined with reading from stdin")
+	}
+
+	if o.Identical {
+		if o.ExitCode {
+			return fmt.Errorf("--identical cannot be combined with --exit-code")
+		}
+		if !o.against() {
+			return fmt.Errorf("--identical requires --against-context or --against-namespace")
+		}
+		if len(o.Revisions) > 1 {
+			return fmt.Errorf("expected at maximum 1 revision when listing identical revisions, but got %d", len(o.Revisions))
 		}
 	}
 
@@ -135,55 +383,72 @@ func (o *Options) Validate() error {
 
 // Run performs the diff operation.
 func (o *Options) Run(ctx context.Context, f util.Factory, args []string) (err error) {
-	r := f.NewBuilder().
-		WithScheme(history.Scheme, history.DecodingVersions...).
-		NamespaceParam(o.Namespace).DefaultNamespace().
-		ResourceTypeOrNameArgs(true, args...).
-		SingleResourceType().
-		Do()
-
-	if err := r.Err(); err != nil {
-		return err
+	if o.ExitCode {
+		o.report = &diff.ChangeReport{}
+		defer func() {
+			if err == nil {
+				err = o.printReport()
+			}
+		}()
 	}
 
-	c, err := f.Client()
-	if err != nil {
-		return err
+	namespace := o.Namespace
+
+	var manifests *offline.Reader
+	if o.Filename != "" {
+		manifests = offline.NewReader(history.Scheme)
+		if err := manifests.LoadFiles([]string{o.Filename}, o.In); err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			// default to the workload resource in the manifest
+			if args, namespace, err = o.manifestArgs(manifests, namespace); err != nil {
+				return err
+			}
+		}
 	}
 
-	infos, err := r.Infos()
+	objectRevisions, err := util.ListObjectRevisions(ctx, f, util.ObjectRevisionsOptions{
+		Namespace:    namespace,
+		FromFiles:    o.FromFiles,
+		In:           o.In,
+		HistoryFlags: o.HistoryFlags,
+		ArchiveFlags: o.ArchiveFlags,
+	}, args)
 	if err != nil {
 		return err
 	}
-	info := infos[0]
-	groupKind := info.Mapping.GroupVersionKind.GroupKind()
-	kindString := fmt.Sprintf("%s.%s", strings.ToLower(groupKind.Kind), groupKind.Group)
 
-	// get all revisions for the given object
-	revs, err := history.ListRevisions(ctx, c, info.Object.(client.Object))
-	if err != nil {
-		return err
+	revs := objectRevisions.Revisions
+
+	if manifests != nil {
+		return o.diffManifest(ctx, objectRevisions, manifests)
+	}
+	if o.against() {
+		return o.diffAgainst(ctx, f, objectRevisions, args)
 	}
-	if len(revs) == 0 {
-		return fmt.Errorf("no revisions found for %s/%s", kindString, info.Name)
+	if o.Range != nil {
+		return o.diffRange(objectRevisions)
 	}
+
 	if len(revs) == 1 {
-		return fmt.Errorf("only 1 revision found for %s/%s", kindString, info.Name)
+		return fmt.Errorf("only 1 revision found for %s", objectRevisions)
 	}
 
 	// get selected revisions
 	var a, b history.Revision
-	if a, err = revs.ByNumber(o.Revisions[0]); err != nil {
+	if a, err = o.Selectors[0].Select(revs); err != nil {
 		return err
 	}
 
 	if len(o.Revisions) > 1 {
-		if b, err = revs.ByNumber(o.Revisions[1]); err != nil {
+		if b, err = o.Selectors[1].Select(revs); err != nil {
 			return err
 		}
 	} else {
 		// if only one revision is given, compare it with its predecessor
-		if b, err = revs.Predecessor(o.Revisions[0]); err != nil {
+		if b, err = revs.Predecessor(a.Number()); err != nil {
 			return err
 		}
 	}
@@ -193,52 +458,320 @@ func (o *Options) Run(ctx context.Context, f util.Factory, args []string) (err e
 		a, b = b, a
 	}
 
-	_, err = fmt.Fprintf(o.ErrOut, "comparing revisions %d and %d of %s/%s\n", a.Number(), b.Number(), kindString, info.Name)
+	_, err = fmt.Fprintf(o.ErrOut, "comparing revisions %d and %d of %s\n", a.Number(), b.Number(), objectRevisions)
 	if err != nil {
 		return err
 	}
 
-	// prepare files for diff program
-	fileName := kindString + "." + info.Namespace + "." + info.Name
-	files, err := diff.NewFiles(ToDirName(a), ToDirName(b))
+	return o.diff(objectRevisions, a, b, ToDirName(a), ToDirName(b))
+}
+
+// diffManifest compares the selected revision with the pod template of the workload resource in the loaded manifests.
+func (o *Options) diffManifest(ctx context.Context, objectRevisions *util.ObjectRevisions, manifests *offline.Reader) error {
+	var obj client.Object
+	for _, m := range manifests.Objects(objectRevisions.GroupKind()) {
+		if m.GetName() == objectRevisions.Info.Name {
+			obj = m
+			break
+		}
+	}
+	if obj == nil {
+		return fmt.Errorf("%s not found in %s", objectRevisions, o.manifestSource())
+	}
+
+	// hand-written manifests typically omit fields that are defaulted by the API server, default them the same way as
+	// the revisions to prevent them from showing up as removed
+	if c, ok := objectRevisions.Client.(client.Writer); ok {
+		defaulted, err := defaultManifest(ctx, c, obj, objectRevisions.Info.Namespace)
+		if err != nil {
+			_, _ = fmt.Fprintf(o.ErrOut, "Warning: could not default %s using a dry-run request, fields defaulted by the API "+
+				"server might show up as removed: %v\n", o.manifestSource(), err)
+		} else {
+			obj = defaulted
+		}
+	}
+
+	b, err := history.NewManifest(obj)
 	if err != nil {
 		return err
 	}
-	defer runutil.CaptureError(&err, files.TearDown)
 
-	p, err := o.PrintFlags.ToPrinter()
+	a, err := o.Selectors[0].Select(objectRevisions.Revisions)
 	if err != nil {
 		return err
 	}
 
-	// the yaml printer adds a `---` separator starting from the second call to PrintObj
-	// call it once to /dev/null to have the separator in both files to compare
-	if err = p.PrintObj(&corev1.Namespace{}, io.Discard); err != nil {
+	_, err = fmt.Fprintf(o.ErrOut, "comparing revision %d of %s with %s\n", a.Number(), objectRevisions, o.manifestSource())
+	if err != nil {
 		return err
 	}
 
-	if err := files.From.Print(fileName, a, p); err != nil {
+	return o.diff(objectRevisions, a, b, ToDirName(a), ToDirName(b))
+}
+
+// defaultManifest returns a copy of the given manifest object that was defaulted by the API server using a dry-run create
+// request. A generated name is used so that the request doesn't conflict with the existing workload resource.
+func defaultManifest(ctx context.Context, c client.Writer, obj client.Object, namespace string) (client.Object, error) {
+	defaulted := obj.DeepCopyObject().(client.Object)
+	defaulted.SetNamespace(namespace)
+	defaulted.SetName("")
+	defaulted.SetGenerateName(obj.GetName() + "-")
+	defaulted.SetResourceVersion("")
+	defaulted.SetUID("")
+
+	if err := c.Create(ctx, defaulted, client.DryRunAll); err != nil {
+		return nil, err
+	}
+
+	defaulted.SetName(obj.GetName())
+	defaulted.SetGenerateName("")
+	return defaulted, nil
+}
+
+// diffRange compares every pair of consecutive revisions in the selected revision range, i.e., it prints the range as a
+// series of diffs. Each diff is preceded by a header line naming the compared revisions.
+func (o *Options) diffRange(objectRevisions *util.ObjectRevisions) error {
+	revs, err := o.Range.Select(objectRevisions.Revisions)
+	if err != nil {
 		return err
 	}
-	if err := files.To.Print(fileName, b, p); err != nil {
+	if len(revs) < 2 {
+		return fmt.Errorf("only 1 revision of %s found in range %s", objectRevisions, o.Range)
+	}
+
+	for i := 1; i < len(revs); i++ {
+		a, b := revs[i-1], revs[i]
+
+		// the header is written to stdout so that it is part of the patch series, patch tools ignore it
+		// in exit code mode and for JSON patches, stdout is reserved for machine-readable output
+		out := o.Out
+		if o.report != nil || slices.Contains(diff.PatchTypes, diff.PatchType(o.PrintFlags.CommandFormat())) {
+			out = o.ErrOut
+		}
+		if _, err := fmt.Fprintf(out, "# comparing revisions %d and %d of %s\n", a.Number(), b.Number(), objectRevisions); err != nil {
+			return err
+		}
+
+		if err := o.diff(objectRevisions, a, b, ToDirName(a), ToDirName(b)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) against() bool {
+	return o.AgainstContext != "" || o.AgainstNamespace != ""
+}
+
+// diffAgainst compares the selected revisions with the revisions of the same workload resource in another context or
+// namespace. The first selected revision is looked up in the compared workload resource, the second one (defaulting to
+// the first one) in the other workload resource.
+func (o *Options) diffAgainst(ctx context.Context, f util.Factory, objectRevisions *util.ObjectRevisions, args []string) error {
+	againstFactory := f
+	if o.AgainstContext != "" {
+		var err error
+		if againstFactory, err = f.ForContext(o.AgainstContext); err != nil {
+			return err
+		}
+	}
+
+	againstNamespace := o.AgainstNamespace
+	if againstNamespace == "" {
+		againstNamespace = objectRevisions.Info.Namespace
+	}
+
+	// when comparing namespaces only, read the other side from the same source
+	var fromFiles []string
+	if o.AgainstContext == "" {
+		fromFiles = o.FromFiles
+	}
+
+	// the local archive is not merged into the other side's revisions, as it doesn't distinguish between clusters
+	againstRevisions, err := util.ListObjectRevisions(ctx, againstFactory, util.ObjectRevisionsOptions{
+		Namespace:    againstNamespace,
+		FromFiles:    fromFiles,
+		HistoryFlags: o.HistoryFlags,
+		ArchiveFlags: util.NewArchiveFlags(),
+	}, args)
+	if err != nil {
 		return err
 	}
 
-	// run diff program against prepared files
-	if err := o.Diff.Run(files.From.Dir, files.To.Dir); err != nil {
-		// don't propagate exit status 1 (signaling a diff) upwards and exit cleanly instead
-		// there will always be a diff between revisions, there is no point in checking that
-		var exitError exec.ExitError
-		if errors.As(err, &exitError) && exitError.ExitStatus() <= 1 {
-			return nil
+	if o.Identical {
+		return o.printIdentical(objectRevisions, againstRevisions)
+	}
+
+	a, err := o.Selectors[0].Select(objectRevisions.Revisions)
+	if err != nil {
+		return err
+	}
+
+	selector := o.Selectors[len(o.Selectors)-1]
+	b, err := selector.Select(againstRevisions.Revisions)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", againstRevisions, o.againstDescription(againstNamespace), err)
+	}
+
+	_, err = fmt.Fprintf(o.ErrOut, "comparing revision %d of %s in namespace %s with revision %d of %s %s\n",
+		a.Number(), objectRevisions, objectRevisions.Info.Namespace, b.Number(), againstRevisions, o.againstDescription(againstNamespace))
+	if err != nil {
+		return err
+	}
+
+	// prefix the other side's directory to distinguish it from the compared revision with the same name
+	return o.diff(objectRevisions, a, b, ToDirName(a), "against-"+ToDirName(b))
+}
+
+func (o *Options) againstDescription(namespace string) string {
+	if o.AgainstContext == "" {
+		return "in namespace " + namespace
+	}
+	return fmt.Sprintf("in namespace %s of context %s", namespace, o.AgainstContext)
+}
+
+// printIdentical prints a table of the revisions in objectRevisions and againstRevisions with identical pod templates.
+func (o *Options) printIdentical(objectRevisions, againstRevisions *util.ObjectRevisions) error {
+	revs := objectRevisions.Revisions
+	if len(o.Selectors) > 0 {
+		rev, err := o.Selectors[0].Select(revs)
+		if err != nil {
+			return err
+		}
+		revs = history.Revisions{rev}
+	}
+
+	w := printers.GetNewTabWriter(o.Out)
+	found := false
+	for _, rev := range revs {
+		for _, againstRev := range againstRevisions.Revisions {
+			if !history.IdenticalPodTemplates(rev, againstRev) {
+				continue
+			}
+
+			if !found {
+				found = true
+				if _, err := fmt.Fprintln(w, "REVISION\tNAME\tROLE\tAGAINST-REVISION\tAGAINST-NAME\tAGAINST-ROLE"); err != nil {
+					return err
+				}
+			}
+
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\n",
+				rev.Number(), rev.Name(), history.RoleOf(rev), againstRev.Number(), againstRev.Name(), history.RoleOf(againstRev)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !found {
+		_, err := fmt.Fprintf(o.ErrOut, "no revisions of %s with identical pod templates found\n", objectRevisions)
+		return err
+	}
+	return w.Flush()
+}
+
+// manifestArgs returns the resource arguments and namespace for the single workload resource in the loaded manifests.
+func (o *Options) manifestArgs(manifests *offline.Reader, namespace string) ([]string, string, error) {
+	var objs []client.Object
+	for _, kind := range []string{"Deployment", "StatefulSet", "DaemonSet"} {
+		objs = append(objs, manifests.Objects(appsv1.SchemeGroupVersion.WithKind(kind).GroupKind())...)
+	}
+
+	if len(objs) != 1 {
+		return nil, "", fmt.Errorf("expected exactly 1 workload resource in %s, but found %d: specify the workload resource to compare", o.manifestSource(), len(objs))
+	}
+
+	obj := objs[0]
+	if obj.GetNamespace() != "" {
+		namespace = obj.GetNamespace()
+	}
+
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return []string{fmt.Sprintf("%s.%s/%s", strings.ToLower(gvk.Kind), gvk.Group, obj.GetName())}, namespace, nil
+}
+
+func (o *Options) manifestSource() string {
+	if o.Filename == offline.StdinPath {
+		return "stdin"
+	}
+	return o.Filename
+}
+
+// diff prints the differences between the given revisions of the given workload object. The revisions are written to
+// temporary directories with the given names for the diff program.
+func (o *Options) diff(objectRevisions *util.ObjectRevisions, a, b history.Revision, fromDir, toDir string) (err error) {
+	from, to := printer.Printable(a, o.PrintFlags.TemplateOnly), printer.Printable(b, o.PrintFlags.TemplateOnly)
+	if !o.Ignore.IsEmpty() {
+		if from, err = o.Ignore.Apply(from); err != nil {
+			return err
+		}
+		if to, err = o.Ignore.Apply(to); err != nil {
+			return err
+		}
+	}
+
+	if o.report != nil {
+		changes, err := diff.FieldChanges(from, to)
+		if err != nil {
+			return err
 		}
+
+		o.report.Add(diff.Comparison{
+			Object:  objectRevisions.String(),
+			From:    diff.ComparedRevision{Revision: a.Number(), Name: a.Name()},
+			To:      diff.ComparedRevision{Revision: b.Number(), Name: b.Name()},
+			Changes: changes,
+		}, o.failOn)
+		return nil
+	}
+
+	if o.PrintFlags.CommandFormat() == FormatFieldPath {
+		changes, err := diff.FieldChanges(from, to)
+		if err != nil {
+			return err
+		}
+		return diff.PrintFieldChanges(o.Out, changes)
+	}
+
+	if format := o.PrintFlags.CommandFormat(); slices.Contains(diff.PatchTypes, diff.PatchType(format)) {
+		patch, err := diff.CreatePatch(diff.PatchType(format), from, to)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.Out, string(patch))
 		return err
 	}
 
+	// prepare files for diff program
+	groupKind, info := objectRevisions.GroupKind(), objectRevisions.Info
+	fileName := fmt.Sprintf("%s.%s.%s.%s", strings.ToLower(groupKind.Kind), groupKind.Group, info.Namespace, info.Name)
+
+	p, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	// run diff program against prepared files
+	// there will always be a diff between revisions, there is no point in checking that
+	return diff.Compare(o.Diff, p, fromDir, toDir, fileName, from, to)
+}
+
+// printReport prints the report of exit code mode and returns ErrDifferencesFound if it contains relevant changes.
+func (o *Options) printReport() error {
+	if err := o.report.Print(o.Out); err != nil {
+		return err
+	}
+
+	if o.report.Failed {
+		return ErrDifferencesFound
+	}
 	return nil
 }
 
 // ToDirName returns a name for a directory which the given revision should be written to.
 func ToDirName(rev history.Revision) string {
+	if _, ok := rev.(*history.Manifest); ok {
+		return "manifest-" + rev.Name()
+	}
 	return fmt.Sprintf("%d-%s", rev.Number(), rev.Name())
 }
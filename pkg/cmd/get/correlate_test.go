@@ -0,0 +1,130 @@
+package get
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/timebertt/kubectl-revisions/pkg/attribution"
+	"github.com/timebertt/kubectl-revisions/pkg/helm"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+)
+
+var _ = Describe("revisionLister#correlate", func() {
+	var (
+		ctx    context.Context
+		lister *revisionLister
+		lists  int
+
+		deployment *appsv1.Deployment
+		revs       history.Revisions
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		lists = 0
+
+		helmReader := fakeclient.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				lists++
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+
+		lister = &revisionLister{
+			Options:    &Options{IOStreams: genericiooptions.IOStreams{ErrOut: io.Discard}},
+			helmReader: helmReader,
+		}
+
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:            "nginx",
+			Namespace:       "default",
+			UID:             "uid",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{helm.ReleaseNameAnnotation: "nginx"},
+		}}
+		revs = history.Revisions{&fake.Revision{Num: 1, Obj: &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-1"}}}}
+	})
+
+	list := func() {
+		lister.releases = helm.Correlation{}
+		lister.attributions = attribution.Attributions{}
+		lister.correlate(ctx, deployment, revs)
+	}
+
+	It("should only read the Helm releases again if the object changed", func() {
+		list()
+		list()
+		Expect(lists).To(Equal(1))
+
+		deployment.ResourceVersion = "2"
+		list()
+		Expect(lists).To(Equal(2))
+	})
+
+	It("should read the Helm releases again if the revisions changed", func() {
+		list()
+		revs = append(revs, &fake.Revision{Num: 2, Obj: &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-2"}}})
+		list()
+		Expect(lists).To(Equal(2))
+	})
+})
+
+var _ = Describe("revisionLister#correlateHelmReleases", func() {
+	var (
+		ctx    context.Context
+		errOut *bytes.Buffer
+		lister *revisionLister
+
+		listErr    error
+		deployment *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		errOut = &bytes.Buffer{}
+		listErr = nil
+
+		helmReader := fakeclient.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return listErr
+			},
+		}).Build()
+
+		lister = &revisionLister{
+			Options:    &Options{IOStreams: genericiooptions.IOStreams{ErrOut: errOut}},
+			helmReader: helmReader,
+		}
+
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:        "nginx",
+			Namespace:   "default",
+			Annotations: map[string]string{helm.ReleaseNameAnnotation: "nginx"},
+		}}
+	})
+
+	It("should skip the correlation silently if reading the release Secrets is forbidden", func() {
+		listErr = apierrors.NewForbidden(corev1.Resource("secrets"), "", errors.New("no permission"))
+		Expect(lister.correlateHelmReleases(ctx, deployment, nil)).To(BeEmpty())
+		Expect(errOut.String()).To(BeEmpty())
+	})
+
+	It("should warn about other errors", func() {
+		listErr = errors.New("fake")
+		Expect(lister.correlateHelmReleases(ctx, deployment, nil)).To(BeEmpty())
+		Expect(errOut.String()).To(ContainSubstring("Warning: cannot correlate revisions with Helm release default/nginx"))
+	})
+})
@@ -3,16 +3,27 @@ package get
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	utilcomp "k8s.io/kubectl/pkg/util/completion"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/timebertt/kubectl-revisions/pkg/attribution"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/helm"
 	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/offline"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
 )
 
 type Options struct {
@@ -24,7 +35,16 @@ type Options struct {
 	ChunkSize     int64
 	LabelSelector string
 
-	Revision   int64
+	FromFiles    []string
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+
+	Watch     bool
+	WatchOnly bool
+	Helm      bool
+
+	Revision   string
+	Selector   history.Selector
 	PrintFlags *util.PrintFlags
 }
 
@@ -32,8 +52,11 @@ func NewOptions(streams genericiooptions.IOStreams) *Options {
 	printFlags := util.NewPrintFlags()
 
 	return &Options{
-		IOStreams:  streams,
-		PrintFlags: printFlags,
+		IOStreams:    streams,
+		PrintFlags:   printFlags,
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+		Helm:         true,
 	}
 }
 
@@ -45,13 +68,53 @@ func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Comma
 		Aliases: []string{"list", "ls"},
 
 		Short: "Get the revision history of a workload resource",
-		Long: `Get the revision history of a workload resource (Deployment, StatefulSet, or DaemonSet).
+		Long: `Get the revision history of a workload resource (Deployment, StatefulSet, DaemonSet, or Argo Rollout).
 
 The history is based on the ReplicaSets/ControllerRevisions still in the system. I.e., the history is limited by the
 configured revisionHistoryLimit.
+If the --archive flag is given, revisions recorded in the local archive using "kubectl revisions record" are merged with
+the revisions still in the system.
+
+For Argo Rollouts, the stable and canary (or active and preview) revisions are marked in the MARKERS column.
+
+If any revision has a kubernetes.io/change-cause annotation, it is printed in the CHANGE-CAUSE column. With -o wide, the
+TRIGGER column shows what caused each revision compared to its predecessor (image, env, restart, config, or other).
+
+For objects managed by Helm, the revisions are correlated with the versions of the Helm release by reading the release
+Secrets. A release version matches a revision if the pod template rendered in the release's manifest equals the
+revision's pod template (ignoring fields defaulted by the API server). The latest matching release version and its chart
+are printed in the HELM-REVISION and CHART columns. Only the Secrets of the object's release are read (selected by the
+owner=helm and name=<release> labels), and they are skipped silently if reading them is forbidden. Use --helm=false to
+skip reading the release Secrets.
+
+With -o wide, the MANAGER column shows who caused each revision, i.e., the field manager (e.g., kubectl, helm, or
+argocd-controller) whose last write recorded in the managedFields of the workload resource or the revision object
+matches the revision's creation time. Controllers that only create the revision objects are not considered. If no write
+matches, the only manager owning fields of the pod template is shown with a trailing "?". With -o yaml or -o json, the
+attribution including the manager's operation (Apply or Update) is added to each revision object in the attribution
+field.
+
+Custom resources that store their history in ControllerRevisions (like StatefulSets and DaemonSets) are supported if
+configured via the --selector-path, --template-path, and --revision-data-format flags or the kinds section of the config
+file.
 
 By default, all revisions are printed as a list. If the --revision flag is given, the selected revision is printed
 instead.
+
+The ROLE column shows which revision the workload is currently running (current) and which revision is being rolled
+out (update). Use --revision=current or --revision=update to select these revisions.
+
+Besides revision numbers and roles, the --revision flag accepts revision names or pod-template-hashes (e.g.,
+nginx-5d8f7c or 5d8f7c), latest, @{2h} for the revision that was live 2 hours ago (based on creation timestamps),
+image=nginx:1.25 for the newest revision running the given image, and commit:3f2a9c1 for the newest revision deployed
+from the given commit.
+
+If the --watch flag is given, the command watches the revisions after printing them and prints revisions again whenever
+they change, e.g., when their READY count changes during a rollout or when a new revision is created. Use --watch-only
+to only print changes.
+
+If the --from-file flag is given, the workload resource and its revisions are read from the given files or directories
+(e.g., dumps created with "kubectl get -o yaml" or must-gather archives) instead of from a live cluster.
 `,
 
 		Example: `# Get all revisions of the nginx Deployment
@@ -62,6 +125,15 @@ kubectl revisions get deploy nginx -o wide
 
 # Get the latest revision in YAML
 kubectl revisions get deploy nginx --revision=-1 -o yaml
+
+# Watch the revisions of the nginx Deployment during a rollout
+kubectl revisions get deploy nginx --watch
+
+# Get all revisions of the nginx Deployment from a directory of YAML dumps instead of a live cluster
+kubectl revisions get deploy nginx --from-file=dump/
+
+# Get all revisions of a custom resource that stores its history in ControllerRevisions
+kubectl revisions get clonesets.apps.kruise.io foo --selector-path=spec.selector --template-path=spec.template
 `,
 
 		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
@@ -74,12 +146,20 @@ kubectl revisions get deploy nginx --revision=-1 -o yaml
 
 	o.PrintFlags.AddFlags(cmd)
 
-	cmd.Flags().Int64VarP(&o.Revision, "revision", "r", 0, "Print the specified revision instead of getting the entire history. "+
-		"Specify -1 for the latest revision, -2 for the one before the latest, etc.")
+	cmd.Flags().StringVarP(&o.Revision, "revision", "r", o.Revision, "Print the specified revision instead of getting the entire history. "+
+		"Specify -1 for the latest revision, -2 for the one before the latest, etc. "+
+		util.RevisionSelectorHelp)
 
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After listing/getting the requested revisions, watch for changes and print revisions again when they change (e.g., their READY count).")
+	cmd.Flags().BoolVar(&o.WatchOnly, "watch-only", o.WatchOnly, "Watch for changes to the requested revisions, without listing/getting first.")
+	cmd.Flags().BoolVar(&o.Helm, "helm", o.Helm, "Correlate the revisions of Helm-managed objects with the versions of their Helm release by reading the release Secrets. "+
+		"The matching release versions are printed in the HELM-REVISION and CHART columns.")
 	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
 	cmdutil.AddChunkSizeFlag(cmd, &o.ChunkSize)
 	cmdutil.AddLabelSelectorFlagVar(cmd, &o.LabelSelector)
+	util.AddFromFileFlag(cmd, &o.FromFiles)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddFlags(cmd)
 
 	return cmd
 }
@@ -88,44 +168,92 @@ kubectl revisions get deploy nginx --revision=-1 -o yaml
 func (o *Options) Complete(f util.Factory) error {
 	var err error
 	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if o.Revision != "" {
+		o.Selector, err = history.ParseSelector(o.Revision)
+	}
 	return err
 }
 
 // Validate checks the set of flags provided by the user.
 func (o *Options) Validate() error {
+	if (o.Watch || o.WatchOnly) && len(o.FromFiles) > 0 {
+		return fmt.Errorf("--watch and --watch-only cannot be used together with --from-file")
+	}
 	return nil
 }
 
 // Run performs the get operation.
 func (o *Options) Run(ctx context.Context, f util.Factory, args []string) (err error) {
-	r := f.NewBuilder().
-		WithScheme(history.Scheme, history.DecodingVersions...).
-		NamespaceParam(o.Namespace).DefaultNamespace().AllNamespaces(o.AllNamespaces).
-		LabelSelectorParam(o.LabelSelector).
-		RequestChunksOf(o.ChunkSize).
-		ResourceTypeOrNameArgs(true, args...).
-		SingleResourceType().
-		Flatten().
-		Do()
-
-	if err := r.Err(); err != nil {
-		return err
-	}
+	var (
+		c, helmReader     client.Reader
+		infos             []*resource.Info
+		singleItemImplied bool
+		watcher           *watchingReader
+	)
+
+	if len(o.FromFiles) > 0 {
+		r := offline.NewReader(history.Scheme)
+		if err := r.LoadFiles(o.FromFiles, o.In); err != nil {
+			return err
+		}
+		c, helmReader = r, r
 
-	var singleItemImplied bool
-	r.IntoSingleItemImplied(&singleItemImplied)
-	if o.Revision != 0 && !singleItemImplied {
-		return fmt.Errorf("a revision can only be selected when targeting a single resource")
-	}
+		infos, singleItemImplied, err = util.OfflineInfos(ctx, r, args, o.Namespace, o.AllNamespaces, o.LabelSelector)
+		if err != nil {
+			return err
+		}
+	} else {
+		r := f.NewBuilder().
+			Unstructured().
+			NamespaceParam(o.Namespace).DefaultNamespace().AllNamespaces(o.AllNamespaces).
+			LabelSelectorParam(o.LabelSelector).
+			RequestChunksOf(o.ChunkSize).
+			ResourceTypeOrNameArgs(true, args...).
+			SingleResourceType().
+			Flatten().
+			Do()
+
+		if err := r.Err(); err != nil {
+			return err
+		}
 
-	c, err := f.Client()
-	if err != nil {
-		return err
+		r.IntoSingleItemImplied(&singleItemImplied)
+
+		if o.Watch || o.WatchOnly {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			defer cancel()
+
+			if watcher, err = o.startWatching(ctx, f); err != nil {
+				return err
+			}
+			c = watcher
+
+			// don't watch Secrets for reading Helm releases
+			if helmReader, err = f.Client(); err != nil {
+				return err
+			}
+		} else {
+			if c, err = f.Client(); err != nil {
+				return err
+			}
+			helmReader = c
+		}
+
+		if infos, err = r.Infos(); err != nil {
+			return err
+		}
+		if err = util.ToTypedInfos(infos); err != nil {
+			return err
+		}
 	}
 
-	infos, err := r.Infos()
-	if err != nil {
-		return err
+	if o.Selector != nil && !singleItemImplied {
+		return fmt.Errorf("a revision can only be selected when targeting a single resource")
 	}
 
 	if len(infos) == 0 {
@@ -138,9 +266,13 @@ func (o *Options) Run(ctx context.Context, f util.Factory, args []string) (err e
 	}
 
 	groupKind := infos[0].Mapping.GroupVersionKind.GroupKind()
-	kindString := fmt.Sprintf("%s.%s", strings.ToLower(groupKind.Kind), groupKind.Group)
 
-	hist, err := history.ForGroupKind(c, groupKind)
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+
+	hist, err := o.HistoryFlags.ForGroupKind(c, groupKind, cfg)
 	if err != nil {
 		return err
 	}
@@ -153,34 +285,237 @@ func (o *Options) Run(ctx context.Context, f util.Factory, args []string) (err e
 		return err
 	}
 
+	lister := &revisionLister{
+		Options:           o,
+		history:           hist,
+		watcher:           watcher,
+		groupKind:         groupKind,
+		infos:             infos,
+		singleItemImplied: singleItemImplied,
+	}
+	if o.Helm {
+		lister.helmReader = helmReader
+	}
+
+	revs, all, err := lister.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if tablePrinter, ok := p.(printer.RevisionsToTablePrinter); ok {
+		columns := managerColumns(lister)
+		if o.Helm {
+			columns = append(columns, helmColumns(lister)...)
+		}
+		p = tablePrinter.WithColumns(columns...)
+	}
+	if revisionPrinter, ok := p.(printer.RevisionPrinter); ok {
+		p = revisionPrinter.WithSections(managerSection(lister))
+	}
+
+	if watcher == nil {
+		if tablePrinter, ok := p.(printer.RevisionsToTablePrinter); ok {
+			// consider the full history for determining the predecessors of the printed revisions
+			p = tablePrinter.WithHistory(all)
+		}
+
+		if o.Selector != nil {
+			return p.PrintObj(revs[0], o.Out)
+		}
+		return p.PrintObj(revs, o.Out)
+	}
+
+	return o.watch(ctx, p, watcher, lister, revs, all)
+}
+
+// watch prints the given revisions (unless --watch-only is set) and prints all revisions again that changed whenever a
+// watched object changes until the context is canceled.
+func (o *Options) watch(ctx context.Context, p printers.ResourcePrinter, watcher *watchingReader, lister *revisionLister, revs, all history.Revisions) error {
+	states := revisionStates{}
+	changed := states.Update(revs)
+	if o.WatchOnly {
+		// skip the initial revisions
+		changed = nil
+	}
+
+	for {
+		// keep the table columns stable when only printing the changed revisions
+		revisionPrinter := p
+		if tablePrinter, ok := p.(printer.RevisionsToTablePrinter); ok {
+			revisionPrinter = tablePrinter.ForRevisions(revs).WithHistory(all)
+		}
+
+		for _, rev := range changed {
+			if err := revisionPrinter.PrintObj(rev, o.Out); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-watcher.Changed:
+		}
+
+		var err error
+		if revs, all, err = lister.List(ctx); err != nil {
+			return err
+		}
+		changed = states.Update(revs)
+	}
+}
+
+// startWatching creates and starts a cache for reading the workload objects and their revisions. Objects of the kinds
+// read via the returned reader are watched for changes.
+func (o *Options) startWatching(ctx context.Context, f util.Factory) (*watchingReader, error) {
+	opts := cache.Options{Scheme: history.Scheme}
+	if !o.AllNamespaces {
+		opts.DefaultNamespaces = map[string]cache.Config{o.Namespace: {}}
+	}
+
+	c, err := f.Cache(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := c.Start(ctx); err != nil {
+			_, _ = fmt.Fprintf(o.ErrOut, "Error watching objects: %v\n", err)
+		}
+	}()
+	if !c.WaitForCacheSync(ctx) {
+		return nil, fmt.Errorf("error waiting for cache to sync")
+	}
+
+	return newWatchingReader(c), nil
+}
+
+type revisionLister struct {
+	*Options
+
+	history           history.History
+	watcher           *watchingReader
+	groupKind         schema.GroupKind
+	infos             []*resource.Info
+	singleItemImplied bool
+
+	// helmReader is used for reading Helm releases, it is nil if revisions are not correlated with Helm releases.
+	helmReader client.Reader
+	// releases contains the Helm release versions of the listed revisions.
+	releases   helm.Correlation
+	helmWarned bool
+	// attributions contains the field managers that caused the listed revisions.
+	attributions attribution.Attributions
+	// correlations caches the Helm release versions and attributions per object, see correlate.
+	correlations map[client.ObjectKey]*objectCorrelation
+}
+
+// objectCorrelation holds the Helm release versions and attributions of the revisions of an object by revision number.
+// It is valid as long as the object and its list of revisions don't change.
+type objectCorrelation struct {
+	uid             types.UID
+	resourceVersion string
+	revisions       []string
+
+	releases     map[int64]*helm.Release
+	attributions map[int64]*attribution.Attribution
+}
+
+// List returns the revisions to print and the revisions of all objects. If a revision is selected, only the selected
+// revision is printed. When watching, the objects are read again to consider their latest state, and objects without revisions are not
+// considered an error.
+func (l *revisionLister) List(ctx context.Context) (printed, all history.Revisions, err error) {
+	watching := l.watcher != nil
+	kindString := fmt.Sprintf("%s.%s", strings.ToLower(l.groupKind.Kind), l.groupKind.Group)
+
+	l.releases = helm.Correlation{}
+	l.attributions = attribution.Attributions{}
+
 	var allRevisions history.Revisions
-	for _, info := range infos {
+	for _, info := range l.infos {
+		obj := info.Object.(client.Object)
+		if watching {
+			// read the latest state of the object, e.g., for the markers of Argo Rollouts
+			if err := l.watcher.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, nil, err
+			}
+		}
+
 		// get all revisions for the given object
-		revs, err := hist.ListRevisions(ctx, info.Object.(client.Object))
+		revs, err := l.history.ListRevisions(ctx, obj)
 		if err != nil {
-			return err
+			return nil, nil, err
+		}
+		if revs, err = l.ArchiveFlags.Merge(l.groupKind, obj, revs); err != nil {
+			return nil, nil, err
 		}
-		if len(revs) == 0 && singleItemImplied {
+		if len(revs) == 0 && l.singleItemImplied && !watching {
 			// if targeting multiple items, we don't complain about individual items not having any revisions
-			return fmt.Errorf("no revisions found for %s/%s", kindString, info.Name)
+			return nil, nil, fmt.Errorf("no revisions found for %s/%s", kindString, info.Name)
 		}
+		l.correlate(ctx, obj, revs)
 
-		if o.Revision != 0 {
+		if l.Selector != nil {
 			// select a single revision
-			rev, err := revs.ByNumber(o.Revision)
+			rev, err := l.Selector.Select(revs)
 			if err != nil {
-				return fmt.Errorf("error for %s/%s: %w", kindString, info.Name, err)
+				return nil, nil, fmt.Errorf("error for %s/%s: %w", kindString, info.Name, err)
 			}
 
-			return p.PrintObj(rev, o.Out)
-		} else {
-			allRevisions = append(allRevisions, revs...)
+			return history.Revisions{rev}, revs, nil
 		}
+
+		allRevisions = append(allRevisions, revs...)
+	}
+
+	if len(allRevisions) == 0 && !watching {
+		return nil, nil, fmt.Errorf("no revisions found for %s", kindString)
 	}
 
-	if len(allRevisions) == 0 {
-		return fmt.Errorf("no revisions found for %s", kindString)
+	return allRevisions, allRevisions, nil
+}
+
+// correlate adds the Helm release versions and attributions of the given revisions of the given object to the lister.
+// The results are cached and only computed again if the object or its list of revisions changed. This prevents reading
+// the Helm release Secrets again on every event when watching.
+func (l *revisionLister) correlate(ctx context.Context, obj client.Object, revs history.Revisions) {
+	key := client.ObjectKeyFromObject(obj)
+	revisions := make([]string, 0, len(revs))
+	for _, rev := range revs {
+		revisions = append(revisions, rev.Name())
 	}
 
-	return p.PrintObj(allRevisions, o.Out)
+	cached, ok := l.correlations[key]
+	if !ok || cached.uid != obj.GetUID() || cached.resourceVersion != obj.GetResourceVersion() || !slices.Equal(cached.revisions, revisions) {
+		cached = &objectCorrelation{
+			uid:             obj.GetUID(),
+			resourceVersion: obj.GetResourceVersion(),
+			revisions:       revisions,
+			releases:        make(map[int64]*helm.Release),
+			attributions:    make(map[int64]*attribution.Attribution),
+		}
+		for rev, release := range l.correlateHelmReleases(ctx, obj, revs) {
+			cached.releases[rev.Number()] = release
+		}
+		for rev, a := range attribution.Attribute(obj, revs) {
+			cached.attributions[rev.Number()] = a
+		}
+
+		if l.correlations == nil {
+			l.correlations = make(map[client.ObjectKey]*objectCorrelation)
+		}
+		l.correlations[key] = cached
+	}
+
+	for _, rev := range revs {
+		if release, ok := cached.releases[rev.Number()]; ok {
+			l.releases[rev] = release
+		}
+		if a, ok := cached.attributions[rev.Number()]; ok {
+			l.attributions[rev] = a
+		}
+	}
 }
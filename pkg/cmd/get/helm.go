@@ -0,0 +1,72 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/helm"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+// correlateHelmReleases finds the Helm release versions that rendered the given revisions if the given object is
+// managed by Helm. Failures are printed as a warning only, as the Helm release versions are additional information. If
+// the user is not allowed to read the release Secrets, the revisions are not correlated without a warning.
+func (l *revisionLister) correlateHelmReleases(ctx context.Context, obj client.Object, revs history.Revisions) helm.Correlation {
+	name, namespace, ok := helm.ReleaseOf(obj)
+	if !ok || l.helmReader == nil {
+		return nil
+	}
+
+	releases, err := helm.ListReleases(ctx, l.helmReader, namespace, name)
+	if err == nil {
+		var correlation helm.Correlation
+		if correlation, err = helm.Correlate(releases, obj, l.groupKind, revs); err == nil {
+			return correlation
+		}
+	}
+
+	if !apierrors.IsForbidden(err) && !l.helmWarned {
+		_, _ = fmt.Fprintf(l.ErrOut, "Warning: cannot correlate revisions with Helm release %s/%s: %v\n", namespace, name, err)
+		l.helmWarned = true
+	}
+	return nil
+}
+
+// helmColumns returns the table columns for the Helm release versions correlated by the given lister.
+func helmColumns(l *revisionLister) []printer.TableColumn {
+	return []printer.TableColumn{
+		{
+			TableColumnDefinition: metav1.TableColumnDefinition{
+				Name: "Helm-Revision",
+				Type: "string",
+			},
+			Extract: func(rev history.Revision) any {
+				if release, ok := l.releases[rev]; ok {
+					return strconv.Itoa(release.Version)
+				}
+				return ""
+			},
+			// only relevant for Helm-managed objects
+			OmitEmpty: true,
+		},
+		{
+			TableColumnDefinition: metav1.TableColumnDefinition{
+				Name: "Chart",
+				Type: "string",
+			},
+			Extract: func(rev history.Revision) any {
+				if release, ok := l.releases[rev]; ok {
+					return release.ChartString()
+				}
+				return ""
+			},
+			OmitEmpty: true,
+		},
+	}
+}
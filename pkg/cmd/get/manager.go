@@ -0,0 +1,48 @@
+package get
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+// managerColumns returns the table columns for the field managers attributed by the given lister.
+func managerColumns(l *revisionLister) []printer.TableColumn {
+	return []printer.TableColumn{
+		{
+			TableColumnDefinition: metav1.TableColumnDefinition{
+				Name:     "Manager",
+				Type:     "string",
+				Priority: 1,
+			},
+			Extract: func(rev history.Revision) any {
+				a, ok := l.attributions[rev]
+				if !ok {
+					return ""
+				}
+				if !a.Exact {
+					// mark guesses based on field ownership only
+					return a.Manager + "?"
+				}
+				return a.Manager
+			},
+			// managedFields are not available for archived revisions or stripped dumps
+			OmitEmpty: true,
+		},
+	}
+}
+
+// managerSection returns the section for the field managers attributed by the given lister in the printed revision
+// objects.
+func managerSection(l *revisionLister) printer.ObjectSection {
+	return printer.ObjectSection{
+		Name: "attribution",
+		Extract: func(rev history.Revision) any {
+			if a, ok := l.attributions[rev]; ok {
+				return a
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package get
+
+import (
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/timebertt/kubectl-revisions/pkg/attribution"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+var _ = Describe("managerColumns", func() {
+	var (
+		lister *revisionLister
+		table  *metav1.Table
+		p      printer.RevisionsToTablePrinter
+
+		rev1, rev2 history.Revision
+	)
+
+	BeforeEach(func() {
+		lister = &revisionLister{}
+		table = nil
+
+		p = printer.RevisionsToTablePrinter{
+			Delegate: printers.ResourcePrinterFunc(func(obj runtime.Object, _ io.Writer) error {
+				table = obj.(*metav1.Table)
+				return nil
+			}),
+			Columns: []printer.TableColumn{{
+				TableColumnDefinition: metav1.TableColumnDefinition{Name: "Name"},
+				Extract: func(rev history.Revision) any {
+					return rev.Name()
+				},
+			}},
+		}.WithColumns(managerColumns(lister)...)
+
+		rev1 = &fake.Revision{Num: 1, Obj: &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-1"}}}
+		rev2 = &fake.Revision{Num: 2, Obj: &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "nginx-2"}}}
+	})
+
+	It("should omit the column if no revision could be attributed", func() {
+		// e.g., for dumps without managedFields
+		lister.attributions = attribution.Attribute(&appsv1.Deployment{}, history.Revisions{rev1, rev2})
+
+		Expect(p.PrintObj(history.Revisions{rev1, rev2}, nil)).To(Succeed())
+		Expect(table.ColumnDefinitions).To(HaveExactElements(HaveField("Name", "Name")))
+		Expect(table.Rows).To(HaveExactElements(
+			HaveField("Cells", []any{"nginx-1"}),
+			HaveField("Cells", []any{"nginx-2"}),
+		))
+	})
+
+	It("should print the attributed managers and mark guesses", func() {
+		lister.attributions = attribution.Attributions{
+			rev1: {Manager: "helm"},
+			rev2: {Manager: "kubectl", Exact: true},
+		}
+
+		Expect(p.PrintObj(history.Revisions{rev1, rev2}, nil)).To(Succeed())
+		Expect(table.ColumnDefinitions).To(HaveExactElements(HaveField("Name", "Name"), HaveField("Name", "Manager")))
+		Expect(table.Rows).To(HaveExactElements(
+			HaveField("Cells", []any{"nginx-1", "helm?"}),
+			HaveField("Cells", []any{"nginx-2", "kubectl"}),
+		))
+	})
+})
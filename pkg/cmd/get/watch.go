@@ -0,0 +1,138 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ client.Reader = &watchingReader{}
+
+// watchingReader is a client.Reader that reads objects from a cache. For every kind of object that is read (e.g.,
+// ReplicaSets, ControllerRevisions, or Pods), it registers an event handler on the corresponding informer that notifies
+// the Changed channel. This way, only the kinds of objects relevant for the workload's history are watched.
+type watchingReader struct {
+	cache.Cache
+
+	// Changed receives a value whenever any watched object was added, updated, or deleted.
+	Changed chan struct{}
+
+	lock    sync.Mutex
+	watched map[schema.GroupVersionKind]struct{}
+}
+
+func newWatchingReader(c cache.Cache) *watchingReader {
+	return &watchingReader{
+		Cache:   c,
+		Changed: make(chan struct{}, 1),
+		watched: make(map[schema.GroupVersionKind]struct{}),
+	}
+}
+
+func (r *watchingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := r.watch(ctx, obj, false); err != nil {
+		return err
+	}
+	return r.Cache.Get(ctx, key, obj, opts...)
+}
+
+func (r *watchingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := r.watch(ctx, list, true); err != nil {
+		return err
+	}
+	return r.Cache.List(ctx, list, opts...)
+}
+
+func (r *watchingReader) watch(ctx context.Context, obj runtime.Object, isList bool) error {
+	gvk, err := apiutil.GVKForObject(obj, history.Scheme)
+	if err != nil {
+		return err
+	}
+	if isList {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.watched[gvk]; ok {
+		return nil
+	}
+
+	var informer cache.Informer
+	if _, ok := obj.(runtime.Unstructured); ok {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		informer, err = r.GetInformer(ctx, u)
+	} else {
+		informer, err = r.GetInformerForKind(ctx, gvk)
+	}
+	if err != nil {
+		return fmt.Errorf("error watching %s: %w", gvk.Kind, err)
+	}
+
+	notify := func() {
+		select {
+		case r.Changed <- struct{}{}:
+		default:
+			// a notification is already pending
+		}
+	}
+
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { notify() },
+		UpdateFunc: func(any, any) { notify() },
+		DeleteFunc: func(any) { notify() },
+	}); err != nil {
+		return fmt.Errorf("error watching %s: %w", gvk.Kind, err)
+	}
+
+	r.watched[gvk] = struct{}{}
+	return nil
+}
+
+// revisionStates tracks the printed state of revisions for detecting changes while watching.
+type revisionStates map[string]string
+
+// Update stores the state of the given revisions and returns the revisions that were added or changed since the last
+// call.
+func (s revisionStates) Update(revs history.Revisions) history.Revisions {
+	var (
+		changed history.Revisions
+		seen    = make(map[string]struct{}, len(revs))
+	)
+
+	for _, rev := range revs {
+		key := client.ObjectKeyFromObject(rev.Object()).String()
+		seen[key] = struct{}{}
+
+		state := fmt.Sprintf("%d %d/%d %s", rev.Number(), rev.ReadyReplicas(), rev.CurrentReplicas(), history.RoleOf(rev))
+		if marked, ok := rev.(history.MarkedRevision); ok {
+			state += " " + strings.Join(marked.Markers(), ",")
+		}
+
+		if s[key] != state {
+			s[key] = state
+			changed = append(changed, rev)
+		}
+	}
+
+	for key := range s {
+		if _, ok := seen[key]; !ok {
+			delete(s, key)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,215 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta/table"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	utilcomp "k8s.io/kubectl/pkg/util/completion"
+
+	cmddiff "github.com/timebertt/kubectl-revisions/pkg/cmd/diff"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/diff"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+type Options struct {
+	genericiooptions.IOStreams
+
+	Namespace    string
+	FromFiles    []string
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+	Patch        bool
+
+	DiffEngine diff.Engine
+	Diff       diff.Program
+}
+
+func NewOptions(streams genericiooptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:    streams,
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+		DiffEngine:   diff.EngineAuto,
+	}
+}
+
+func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use: "log (TYPE[.VERSION][.GROUP] NAME | TYPE[.VERSION][.GROUP]/NAME)",
+
+		Short: "Show a summary of every revision change of a workload resource",
+		Long: `Show a summary of every revision change of a workload resource, similar to "git log".
+
+For every revision from the newest to the oldest, the revision number, name, creation time, and change cause (the
+kubernetes.io/change-cause annotation) are printed together with a compact summary of what changed compared to the
+revision's predecessor, e.g., changed images, env vars, resources, and annotations.
+
+If the --patch flag is given, the full diff of every revision compared to its predecessor is printed as well, similar
+to "git log -p". The diff program can be configured like for the diff command, see "kubectl revisions diff --help".
+
+The history is based on the ReplicaSets/ControllerRevisions still in the system. I.e., the history is limited by the
+configured revisionHistoryLimit.
+If the --archive flag is given, revisions recorded in the local archive using "kubectl revisions record" are merged with
+the revisions still in the system.
+
+If the --from-file flag is given, the workload resource and its revisions are read from the given files or directories
+(e.g., dumps created with "kubectl get -o yaml" or must-gather archives) instead of from a live cluster.
+`,
+
+		Example: `# Show a summary of all changes of the nginx Deployment
+kubectl revisions log deploy nginx
+
+# Include the full diff of every revision
+kubectl revisions log deploy nginx -p
+`,
+
+		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.Patch, "patch", "p", o.Patch, "If true, print the full diff of every revision compared to its predecessor.")
+	util.AddDiffEngineFlag(cmd, &o.DiffEngine)
+	util.AddFromFileFlag(cmd, &o.FromFiles)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f util.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.Diff, err = diff.NewProgramForEngine(o.DiffEngine, o.IOStreams)
+	return err
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	return nil
+}
+
+// Run performs the log operation.
+func (o *Options) Run(ctx context.Context, f util.Factory, args []string) error {
+	objectRevisions, err := util.ListObjectRevisions(ctx, f, util.ObjectRevisionsOptions{
+		Namespace:    o.Namespace,
+		FromFiles:    o.FromFiles,
+		In:           o.In,
+		HistoryFlags: o.HistoryFlags,
+		ArchiveFlags: o.ArchiveFlags,
+	}, args)
+	if err != nil {
+		return err
+	}
+
+	groupKind, info := objectRevisions.GroupKind(), objectRevisions.Info
+	fileName := fmt.Sprintf("%s.%s.%s.%s", strings.ToLower(groupKind.Kind), groupKind.Group, info.Namespace, info.Name)
+
+	revs := objectRevisions.Revisions
+	for i := len(revs) - 1; i >= 0; i-- {
+		if i < len(revs)-1 {
+			if _, err := fmt.Fprintln(o.Out); err != nil {
+				return err
+			}
+		}
+
+		var predecessor history.Revision
+		if i > 0 {
+			predecessor = revs[i-1]
+		}
+
+		if err := o.printRevision(revs[i], predecessor); err != nil {
+			return err
+		}
+
+		if o.Patch && predecessor != nil {
+			if _, err := fmt.Fprintln(o.Out); err != nil {
+				return err
+			}
+			if err := o.showDiff(fileName, predecessor, revs[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// printRevision prints the header of the given revision and a summary of the changes compared to its predecessor.
+func (o *Options) printRevision(rev, predecessor history.Revision) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "revision %d (%s)", rev.Number(), rev.Name())
+	if marked, ok := rev.(history.MarkedRevision); ok && len(marked.Markers()) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(marked.Markers(), ", "))
+	}
+	b.WriteString("\n")
+
+	creationTimestamp := rev.Object().GetCreationTimestamp()
+	fmt.Fprintf(&b, "Date:         %s (%s ago)\n", creationTimestamp.UTC().Format("2006-01-02 15:04:05 MST"), table.ConvertToHumanReadableDateType(creationTimestamp))
+	if changeCause := history.ChangeCause(rev); changeCause != "" {
+		fmt.Fprintf(&b, "Change-Cause: %s\n", changeCause)
+	}
+	b.WriteString("\n")
+
+	summary, err := summarize(rev, predecessor)
+	if err != nil {
+		return err
+	}
+	for _, line := range summary {
+		fmt.Fprintf(&b, "    %s\n", line)
+	}
+
+	_, err = io.WriteString(o.Out, b.String())
+	return err
+}
+
+func summarize(rev, predecessor history.Revision) ([]string, error) {
+	if predecessor == nil {
+		if rev.Number() == 1 {
+			return []string{"initial revision"}, nil
+		}
+		return []string{"oldest revision in the history"}, nil
+	}
+
+	changes, err := diff.FieldChanges(predecessor.PodTemplate(), rev.PodTemplate())
+	if err != nil {
+		return nil, fmt.Errorf("error comparing revisions %d and %d: %w", predecessor.Number(), rev.Number(), err)
+	}
+
+	summary := diff.Summarize(changes)
+	if len(summary) == 0 {
+		return []string{"no changes of the pod template"}, nil
+	}
+	return summary, nil
+}
+
+// showDiff runs the diff program to compare the pod templates of the given revisions.
+func (o *Options) showDiff(fileName string, a, b history.Revision) error {
+	printFlags := util.NewPrintFlags()
+	printFlags.WithDefaultOutput("yaml")
+	printFlags.TemplateOnly = true
+	p, err := printFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	return diff.Compare(o.Diff, p, cmddiff.ToDirName(a), cmddiff.ToDirName(b), fileName, a, b)
+}
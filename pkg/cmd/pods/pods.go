@@ -0,0 +1,212 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	utilcomp "k8s.io/kubectl/pkg/util/completion"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+type Options struct {
+	genericiooptions.IOStreams
+
+	Namespace    string
+	FromFiles    []string
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+
+	Revision   string
+	Selector   history.Selector
+	PrintFlags *genericclioptions.PrintFlags
+	NoHeaders  bool
+}
+
+func NewOptions(streams genericiooptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:    streams,
+		PrintFlags:   genericclioptions.NewPrintFlags("").WithTypeSetter(scheme.Scheme),
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+	}
+}
+
+func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use: "pods (TYPE[.VERSION][.GROUP] NAME | TYPE[.VERSION][.GROUP]/NAME)",
+
+		Short: "List the pods of a workload resource together with their revisions",
+		Long: `List the pods of a workload resource together with the revision each pod belongs to.
+
+For every pod, the revision number, readiness, status, restarts, age, and node are printed. During a partial rollout,
+this shows which replicas still run an old revision. The ROLE column shows whether a pod's revision is the one the
+workload is currently running (current) or the one being rolled out (update).
+
+By default, the pods of all revisions are listed. If the --revision flag is given, only the pods of the selected
+revision are listed.
+
+Pods are only matched to revisions still in the system, i.e., to ReplicaSets/ControllerRevisions. Revisions read from
+the local archive don't have pods.
+
+If the --from-file flag is given, the workload resource, its revisions, and its pods are read from the given files or
+directories (e.g., dumps created with "kubectl get -o yaml" or must-gather archives) instead of from a live cluster.
+`,
+
+		Example: `# List the pods of the nginx Deployment with their revisions
+kubectl revisions pods deploy nginx
+
+# List the pods that still run the revision before the latest one
+kubectl revisions pods deploy nginx --revision=-2
+
+# List the pods of the revision that is currently being rolled out including their IPs
+kubectl revisions pods deploy nginx --revision=update -o wide
+`,
+
+		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	cmd.Flags().Lookup("output").Usage = fmt.Sprintf("Output format. One of: (%s).", strings.Join(o.allowedFormats(), ", "))
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When using the default output format, don't print headers (default print headers).")
+
+	cmd.Flags().StringVarP(&o.Revision, "revision", "r", o.Revision, "Only list the pods of the specified revision. "+
+		"Specify -1 for the latest revision, -2 for the one before the latest, etc. "+
+		util.RevisionSelectorHelp)
+	util.AddFromFileFlag(cmd, &o.FromFiles)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+func (o *Options) allowedFormats() []string {
+	return append([]string{"wide"}, o.PrintFlags.AllowedFormats()...)
+}
+
+func (o *Options) outputFormat() string {
+	if o.PrintFlags.OutputFormat == nil {
+		return ""
+	}
+	return *o.PrintFlags.OutputFormat
+}
+
+// tableOutput returns true if the pods should be printed as a table.
+func (o *Options) tableOutput() bool {
+	return o.outputFormat() == "" || o.outputFormat() == "wide"
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f util.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if o.Revision != "" {
+		o.Selector, err = history.ParseSelector(o.Revision)
+	}
+	return err
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	// template formats carry their argument, e.g., jsonpath={.spec}
+	if format, _, _ := strings.Cut(o.outputFormat(), "="); format != "" && !slices.Contains(o.allowedFormats(), format) {
+		return genericclioptions.NoCompatiblePrinterError{OutputFormat: o.PrintFlags.OutputFormat, AllowedFormats: o.allowedFormats()}
+	}
+	return nil
+}
+
+// Run performs the pods operation.
+func (o *Options) Run(ctx context.Context, f util.Factory, args []string) error {
+	objectRevisions, err := util.ListObjectRevisions(ctx, f, util.ObjectRevisionsOptions{
+		Namespace:    o.Namespace,
+		FromFiles:    o.FromFiles,
+		In:           o.In,
+		HistoryFlags: o.HistoryFlags,
+		ArchiveFlags: o.ArchiveFlags,
+	}, args)
+	if err != nil {
+		return err
+	}
+
+	revs := objectRevisions.Revisions
+	if o.Selector != nil {
+		rev, err := o.Selector.Select(revs)
+		if err != nil {
+			return err
+		}
+		revs = history.Revisions{rev}
+	}
+
+	podList, err := history.ListPods(ctx, objectRevisions.Client, objectRevisions.Info.Object.(client.Object), history.SelectorPath(objectRevisions.History))
+	if err != nil {
+		return err
+	}
+
+	pods := history.MatchPods(podList, revs)
+	if len(pods) == 0 {
+		_, _ = fmt.Fprintf(o.ErrOut, "No pods found for %s.\n", objectRevisions)
+		return nil
+	}
+
+	if o.tableOutput() {
+		p := printers.NewTablePrinter(printers.PrintOptions{
+			NoHeaders: o.NoHeaders,
+			Wide:      o.outputFormat() == "wide",
+		})
+		return p.PrintObj(printer.PodTable(pods), o.Out)
+	}
+
+	p, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	// collect all pods in an unstructured list, which is properly handled by all used printers
+	list := &unstructured.UnstructuredList{
+		Object: map[string]interface{}{
+			"kind":       "List",
+			"apiVersion": "v1",
+			"metadata": map[string]interface{}{
+				"resourceVersion": "",
+			},
+		},
+	}
+
+	for _, pod := range pods {
+		item := pod.Pod.DeepCopy()
+		item.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+
+		unstructuredContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return err
+		}
+		list.Items = append(list.Items, unstructured.Unstructured{Object: unstructuredContent})
+	}
+
+	return p.PrintObj(list, o.Out)
+}
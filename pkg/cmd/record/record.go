@@ -0,0 +1,249 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	utilcomp "k8s.io/kubectl/pkg/util/completion"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/archive"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/helper"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+type Options struct {
+	genericiooptions.IOStreams
+
+	Namespace     string
+	AllNamespaces bool
+	LabelSelector string
+
+	Watch        bool
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+
+	store *archive.Store
+}
+
+func NewOptions(streams genericiooptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:    streams,
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+	}
+}
+
+func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use: "record (TYPE[.VERSION][.GROUP] [NAME | -l label] | TYPE[.VERSION][.GROUP]/NAME ...)",
+
+		Short: "Record the revisions of a workload resource in the local archive",
+		Long: `Record the revisions of a workload resource in the local archive.
+
+The history in the cluster is limited by the configured revisionHistoryLimit. Once old ReplicaSets/ControllerRevisions
+are garbage-collected, their pod templates are gone. This command snapshots all revisions it sees into a local on-disk
+archive, so that they can still be inspected later on using the --archive flag of the get and diff commands.
+
+The archive is content-addressed by the hash of the revisions' pod templates, i.e., recording the same revision multiple
+times doesn't create duplicate entries.
+
+If the --watch flag is given, the command keeps watching the workload resources and records new revisions as they
+appear until it is interrupted.
+`,
+
+		Example: `# Record all revisions of the nginx Deployment
+kubectl revisions record deploy nginx
+
+# Keep recording new revisions of all Deployments in the current namespace
+kubectl revisions record deploy --watch
+
+# Get all revisions of the nginx Deployment including archived revisions
+kubectl revisions get deploy nginx --archive
+`,
+
+		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After recording the current revisions, watch the workload resources and record new revisions as they appear.")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, record the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmdutil.AddLabelSelectorFlagVar(cmd, &o.LabelSelector)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddDirFlag(cmd)
+
+	return cmd
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f util.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.store, err = o.ArchiveFlags.ToStore()
+	return err
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	return nil
+}
+
+// Run performs the record operation.
+func (o *Options) Run(ctx context.Context, f util.Factory, args []string) (err error) {
+	r := f.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().AllNamespaces(o.AllNamespaces).
+		LabelSelectorParam(o.LabelSelector).
+		ResourceTypeOrNameArgs(true, args...).
+		SingleResourceType().
+		Latest().
+		Flatten().
+		Do()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	c, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+
+	infos, err := r.Infos()
+	if err != nil {
+		return err
+	}
+	if err := util.ToTypedInfos(infos); err != nil {
+		return err
+	}
+
+	if len(infos) == 0 && !o.Watch {
+		if o.AllNamespaces {
+			_, _ = fmt.Fprintf(o.ErrOut, "No resources found.\n")
+		} else {
+			_, _ = fmt.Fprintf(o.ErrOut, "No resources found in %s namespace.\n", o.Namespace)
+		}
+		return nil
+	}
+
+	mapping, err := r.ResourceMapping()
+	if err != nil {
+		return err
+	}
+
+	hist, err := o.HistoryFlags.ForGroupKind(c, mapping.GroupVersionKind.GroupKind(), cfg)
+	if err != nil {
+		return err
+	}
+
+	rec := &recorder{Options: o, history: hist, mapping: mapping}
+	for _, info := range infos {
+		if err := rec.record(ctx, info.Object.(client.Object), true); err != nil {
+			return err
+		}
+	}
+
+	if !o.Watch {
+		return nil
+	}
+
+	// watch the workload objects starting from the resource version of the initial list (if there is a common one),
+	// every change of the pod template results in a new revision
+	obj, err := r.Object()
+	if err != nil {
+		return err
+	}
+	resourceVersion, err := meta.NewAccessor().ResourceVersion(obj)
+	if err != nil {
+		return err
+	}
+
+	w, err := r.Watch(resourceVersion)
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed unexpectedly")
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				obj, err := history.ToTyped(event.Object)
+				if err != nil {
+					return err
+				}
+				if err := rec.record(ctx, obj.(client.Object), false); err != nil {
+					return err
+				}
+			case watch.Error:
+				return apierrors.FromObject(event.Object)
+			}
+		}
+	}
+}
+
+type recorder struct {
+	*Options
+
+	history history.History
+	mapping *meta.RESTMapping
+}
+
+// record records all revisions of the given object. If verbose is false, the result is only printed if new revisions
+// were recorded.
+func (r *recorder) record(ctx context.Context, obj client.Object, verbose bool) error {
+	gk := r.mapping.GroupVersionKind.GroupKind()
+	kindString := fmt.Sprintf("%s.%s", strings.ToLower(gk.Kind), gk.Group)
+
+	revs, err := r.history.ListRevisions(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("error listing revisions of %s/%s: %w", kindString, obj.GetName(), err)
+	}
+
+	recorded, err := r.store.Record(gk, obj, revs)
+	if err != nil {
+		return fmt.Errorf("error recording revisions of %s/%s: %w", kindString, obj.GetName(), err)
+	}
+
+	if recorded == 0 && !verbose {
+		return nil
+	}
+
+	prefix := ""
+	if r.AllNamespaces {
+		prefix = obj.GetNamespace() + "/"
+	}
+
+	_, err = fmt.Fprintf(r.Out, "%s%s/%s recorded %d new %s\n", prefix, kindString, obj.GetName(), recorded, helper.Pluralize(recorded, "revision"))
+	return err
+}
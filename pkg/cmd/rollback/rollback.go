@@ -0,0 +1,274 @@
+package rollback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	utilcomp "k8s.io/kubectl/pkg/util/completion"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cmddiff "github.com/timebertt/kubectl-revisions/pkg/cmd/diff"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/diff"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+type Options struct {
+	genericiooptions.IOStreams
+
+	Namespace string
+	Revision  int64
+	Yes       bool
+
+	DryRunStrategy cmdutil.DryRunStrategy
+
+	DiffEngine diff.Engine
+	Diff       diff.Program
+}
+
+func NewOptions(streams genericiooptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:  streams,
+		Revision:   -2,
+		DiffEngine: diff.EngineAuto,
+	}
+}
+
+func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:     "rollback (TYPE[.VERSION][.GROUP] NAME | TYPE[.VERSION][.GROUP]/NAME)",
+		Aliases: []string{"undo"},
+
+		Short: "Roll back a workload resource to a selected revision",
+		Long: `Roll back a workload resource (Deployment, StatefulSet, DaemonSet, or Argo Rollout) to a selected revision.
+
+The pod template of the selected revision is written back to the workload resource, which causes the workload
+controller to roll out the selected revision again.
+
+The history is based on the ReplicaSets/ControllerRevisions still in the system. I.e., the history is limited by the
+configured revisionHistoryLimit.
+
+By default, the workload is rolled back to the revision before the latest one. The --revision flag allows selecting
+the revision to roll back to.
+
+Before rolling back, the difference between the current pod template and the pod template of the selected revision is
+shown and confirmation is requested. Use --yes to skip the confirmation prompt.
+The diff program can be configured like for the diff command, see "kubectl revisions diff --help".`,
+
+		Example: `# Roll back the nginx Deployment to the previous revision
+kubectl revisions rollback deploy nginx
+
+# Roll back the nginx Deployment to the revision before the previous one
+kubectl revisions rollback deploy nginx --revision=-3
+
+# Roll back the web StatefulSet to revision 2 without asking for confirmation
+kubectl revisions rollback sts web --revision=2 --yes
+
+# Show the changes that would be applied when rolling back, but don't roll back
+kubectl revisions rollback deploy nginx --dry-run=client
+`,
+
+		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+		},
+	}
+
+	cmd.Flags().Int64VarP(&o.Revision, "revision", "r", o.Revision, "Roll back to the specified revision. "+
+		"Specify -1 for the latest revision, -2 for the one before the latest, etc.")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", o.Yes, "If true, roll back without asking for confirmation.")
+	cmdutil.AddDryRunFlag(cmd)
+	util.AddDiffEngineFlag(cmd, &o.DiffEngine)
+
+	return cmd
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f util.Factory, cmd *cobra.Command) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.DryRunStrategy, err = cmdutil.GetDryRunStrategy(cmd)
+	if err != nil {
+		return err
+	}
+
+	o.Diff, err = diff.NewProgramForEngine(o.DiffEngine, o.IOStreams)
+	return err
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	if o.Revision == 0 {
+		return fmt.Errorf("invalid revision 0")
+	}
+
+	return nil
+}
+
+// Run performs the rollback operation.
+func (o *Options) Run(ctx context.Context, f util.Factory, args []string) error {
+	r := f.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(true, args...).
+		SingleResourceType().
+		Do()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	c, err := f.Client()
+	if err != nil {
+		return err
+	}
+
+	infos, err := r.Infos()
+	if err != nil {
+		return err
+	}
+	if err := util.ToTypedInfos(infos); err != nil {
+		return err
+	}
+	info := infos[0]
+	groupKind := info.Mapping.GroupVersionKind.GroupKind()
+	kindString := fmt.Sprintf("%s.%s", strings.ToLower(groupKind.Kind), groupKind.Group)
+
+	return o.rollback(ctx, c, info.Object.(client.Object), kindString)
+}
+
+// rollback rolls back the given object to the selected revision. kindString is used for output, e.g.,
+// "deployment.apps".
+func (o *Options) rollback(ctx context.Context, c client.Client, obj client.Object, kindString string) error {
+	name := obj.GetName()
+
+	if deployment, ok := obj.(*appsv1.Deployment); ok && deployment.Spec.Paused {
+		return fmt.Errorf("cannot roll back paused %s/%s, resume it first with 'kubectl rollout resume' and try again", kindString, name)
+	}
+
+	// get all revisions for the given object
+	revs, err := history.ListRevisions(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+	if len(revs) == 0 {
+		return fmt.Errorf("no revisions found for %s/%s", kindString, name)
+	}
+
+	rev, err := revs.ByNumber(o.Revision)
+	if err != nil {
+		return err
+	}
+
+	current, err := history.PodTemplateOf(obj)
+	if err != nil {
+		return err
+	}
+	target := rev.PodTemplate()
+
+	if apiequality.Semantic.DeepEqual(current.ObjectMeta, target.ObjectMeta) && apiequality.Semantic.DeepEqual(current.Spec, target.Spec) {
+		_, err = fmt.Fprintf(o.Out, "%s/%s skipped rollback (current template already matches revision %d)\n", kindString, name, rev.Number())
+		return err
+	}
+
+	_, err = fmt.Fprintf(o.ErrOut, "comparing current template and revision %d of %s/%s\n", rev.Number(), kindString, name)
+	if err != nil {
+		return err
+	}
+
+	if err := o.showDiff(kindString+"."+obj.GetNamespace()+"."+name, current, rev); err != nil {
+		return err
+	}
+
+	if o.DryRunStrategy == cmdutil.DryRunClient {
+		_, err = fmt.Fprintf(o.Out, "%s/%s rolled back to revision %d (dry run)\n", kindString, name, rev.Number())
+		return err
+	}
+
+	if !o.Yes {
+		confirmed, err := o.confirm(fmt.Sprintf("Roll back %s/%s to revision %d?", kindString, name, rev.Number()))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			_, err = fmt.Fprintln(o.ErrOut, "rollback aborted")
+			return err
+		}
+	}
+
+	patch, err := json.Marshal([]map[string]any{{
+		"op":    "replace",
+		"path":  "/spec/template",
+		"value": history.PodTemplateSpec(target),
+	}})
+	if err != nil {
+		return err
+	}
+
+	var patchOptions []client.PatchOption
+	if o.DryRunStrategy == cmdutil.DryRunServer {
+		patchOptions = append(patchOptions, client.DryRunAll)
+	}
+
+	if err := c.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch), patchOptions...); err != nil {
+		return fmt.Errorf("failed restoring revision %d: %w", rev.Number(), err)
+	}
+
+	suffix := ""
+	if o.DryRunStrategy == cmdutil.DryRunServer {
+		suffix = " (server dry run)"
+	}
+	_, err = fmt.Fprintf(o.Out, "%s/%s rolled back to revision %d%s\n", kindString, name, rev.Number(), suffix)
+	return err
+}
+
+// showDiff runs the diff program to compare the current pod template with the pod template of the given revision.
+func (o *Options) showDiff(fileName string, current *corev1.Pod, rev history.Revision) error {
+	printFlags := util.NewPrintFlags()
+	printFlags.WithDefaultOutput("yaml")
+	printFlags.TemplateOnly = true
+	p, err := printFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	return diff.Compare(o.Diff, p, "current", cmddiff.ToDirName(rev), fileName, current, rev)
+}
+
+// confirm asks the user for confirmation on the input stream and returns true if the user confirmed.
+func (o *Options) confirm(question string) (bool, error) {
+	if _, err := fmt.Fprintf(o.ErrOut, "%s [y/N]: ", question); err != nil {
+		return false, err
+	}
+
+	answer, err := bufio.NewReader(o.In).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,13 @@
+package rollback
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRollback(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rollback Command Suite")
+}
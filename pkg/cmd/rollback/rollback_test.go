@@ -0,0 +1,200 @@
+package rollback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("Options#rollback", func() {
+	var (
+		ctx         context.Context
+		out, errOut *bytes.Buffer
+		o           *Options
+		diffProgram *fakeProgram
+
+		c            client.Client
+		patches      []client.Patch
+		patchOptions []*client.PatchOptions
+
+		deployment *appsv1.Deployment
+		replicaSet *appsv1.ReplicaSet
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		out, errOut = &bytes.Buffer{}, &bytes.Buffer{}
+		diffProgram = &fakeProgram{}
+		patches, patchOptions = nil, nil
+
+		o = NewOptions(genericiooptions.IOStreams{In: strings.NewReader(""), Out: out, ErrOut: errOut})
+		o.Diff = diffProgram
+
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", UID: "uid"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+				Template: podTemplate(2),
+			},
+		}
+		replicaSet = replicaSetOf(deployment, 1)
+	})
+
+	JustBeforeEach(func() {
+		c = fakeclient.NewClientBuilder().
+			WithObjects(deployment, replicaSet, replicaSetOf(deployment, 2)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					patches = append(patches, patch)
+					patchOptions = append(patchOptions, (&client.PatchOptions{}).ApplyOptions(opts))
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+	})
+
+	rollback := func() error {
+		return o.rollback(ctx, c, deployment, "deployment.apps")
+	}
+
+	currentDeployment := func() *appsv1.Deployment {
+		current := &appsv1.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(deployment), current)).To(Succeed())
+		return current
+	}
+
+	It("should replace the pod template with the pod template of the selected revision", func() {
+		o.Yes = true
+		Expect(rollback()).To(Succeed())
+
+		rev, err := history.NewReplicaSet(replicaSet)
+		Expect(err).NotTo(HaveOccurred())
+		expectedPatch, err := json.Marshal([]map[string]any{{
+			"op":    "replace",
+			"path":  "/spec/template",
+			"value": history.PodTemplateSpec(rev.PodTemplate()),
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(patches).To(HaveLen(1))
+		Expect(patches[0].Type()).To(Equal(types.JSONPatchType))
+		Expect(patches[0].Data(deployment)).To(MatchJSON(expectedPatch))
+		Expect(patchOptions[0].DryRun).To(BeEmpty())
+
+		Expect(currentDeployment().Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1"))
+		Expect(diffProgram.runs).To(Equal(1))
+		Expect(out.String()).To(Equal("deployment.apps/nginx rolled back to revision 1\n"))
+	})
+
+	It("should refuse to roll back a paused Deployment", func() {
+		deployment.Spec.Paused = true
+		o.Yes = true
+
+		Expect(rollback()).To(MatchError(ContainSubstring("cannot roll back paused deployment.apps/nginx")))
+		Expect(patches).To(BeEmpty())
+		Expect(diffProgram.runs).To(BeZero())
+	})
+
+	It("should only show the diff on client dry run", func() {
+		o.DryRunStrategy = cmdutil.DryRunClient
+
+		Expect(rollback()).To(Succeed())
+		Expect(patches).To(BeEmpty())
+		Expect(diffProgram.runs).To(Equal(1))
+		Expect(out.String()).To(Equal("deployment.apps/nginx rolled back to revision 1 (dry run)\n"))
+		Expect(errOut.String()).NotTo(ContainSubstring("[y/N]"))
+	})
+
+	It("should send a dry-run patch on server dry run", func() {
+		o.DryRunStrategy = cmdutil.DryRunServer
+		o.Yes = true
+
+		Expect(rollback()).To(Succeed())
+		Expect(patches).To(HaveLen(1))
+		Expect(patchOptions[0].DryRun).To(ConsistOf(metav1.DryRunAll))
+		Expect(currentDeployment().Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:2"))
+		Expect(out.String()).To(Equal("deployment.apps/nginx rolled back to revision 1 (server dry run)\n"))
+	})
+
+	It("should not roll back if the confirmation prompt is declined", func() {
+		o.In = strings.NewReader("n\n")
+
+		Expect(rollback()).To(Succeed())
+		Expect(patches).To(BeEmpty())
+		Expect(errOut.String()).To(ContainSubstring("Roll back deployment.apps/nginx to revision 1? [y/N]: "))
+		Expect(errOut.String()).To(ContainSubstring("rollback aborted"))
+		Expect(currentDeployment().Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:2"))
+	})
+
+	It("should roll back if the confirmation prompt is accepted", func() {
+		o.In = strings.NewReader("y\n")
+
+		Expect(rollback()).To(Succeed())
+		Expect(patches).To(HaveLen(1))
+		Expect(currentDeployment().Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1"))
+	})
+
+	It("should skip the rollback if the current template already matches the selected revision", func() {
+		o.Revision = -1
+		o.Yes = true
+
+		Expect(rollback()).To(Succeed())
+		Expect(patches).To(BeEmpty())
+		Expect(diffProgram.runs).To(BeZero())
+		Expect(out.String()).To(ContainSubstring("skipped rollback"))
+	})
+})
+
+func podTemplate(number int) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nginx"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nginx", Image: fmt.Sprintf("nginx:%d", number)}},
+		},
+	}
+}
+
+func replicaSetOf(deployment *appsv1.Deployment, number int) *appsv1.ReplicaSet {
+	template := podTemplate(number)
+	template.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = fmt.Sprintf("hash-%d", number)
+
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("nginx-%d", number),
+			Namespace:       deployment.Namespace,
+			Labels:          template.Labels,
+			Annotations:     map[string]string{"deployment.kubernetes.io/revision": fmt.Sprint(number)},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment"))},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: template.Labels},
+			Template: template,
+		},
+	}
+}
+
+// fakeProgram is a diff.Program that only counts its runs.
+type fakeProgram struct {
+	runs int
+}
+
+func (p *fakeProgram) Run(string, string) error {
+	p.runs++
+	return nil
+}
@@ -12,11 +12,18 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	utilcomp "k8s.io/kubectl/pkg/util/completion"
 
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/audit"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/blame"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/completion"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/diff"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/get"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/help"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/log"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/options"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/pods"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/record"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/rollback"
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/timeline"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
 	"github.com/timebertt/kubectl-revisions/pkg/cmd/version"
 )
@@ -25,6 +32,8 @@ type Options struct {
 	genericiooptions.IOStreams
 
 	ConfigFlags *genericclioptions.ConfigFlags
+	// ConfigFile is the path of the plugin's configuration file.
+	ConfigFile string
 }
 
 func NewOptions() *Options {
@@ -68,7 +77,10 @@ func NewCommand() *cobra.Command {
 	flags := cmd.PersistentFlags()
 	o.ConfigFlags.AddFlags(flags)
 	logs.AddFlags(flags)
-	f := util.NewFactory(o.ConfigFlags)
+	flags.StringVar(&o.ConfigFile, "config", o.ConfigFile, "Path to the configuration file of the revisions plugin (defaults to "+
+		"kubectl-revisions/config.yaml in the user's configuration directory, e.g., ~/.config on Linux).")
+	cmdutil.CheckErr(cmd.MarkPersistentFlagFilename("config", "yaml", "yml"))
+	f := util.NewFactory(o.ConfigFlags, &o.ConfigFile)
 
 	cobra.EnableCommandSorting = false
 
@@ -82,6 +94,13 @@ func NewCommand() *cobra.Command {
 	for _, subcommand := range []*cobra.Command{
 		get.NewCommand(f, o.IOStreams),
 		diff.NewCommand(f, o.IOStreams),
+		rollback.NewCommand(f, o.IOStreams),
+		record.NewCommand(f, o.IOStreams),
+		blame.NewCommand(f, o.IOStreams),
+		log.NewCommand(f, o.IOStreams),
+		pods.NewCommand(f, o.IOStreams),
+		audit.NewCommand(f, o.IOStreams),
+		timeline.NewCommand(f, o.IOStreams),
 	} {
 		subcommand.GroupID = defaultGroup.ID
 		cmd.AddCommand(subcommand)
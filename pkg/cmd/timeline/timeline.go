@@ -0,0 +1,208 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	utilcomp "k8s.io/kubectl/pkg/util/completion"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/printer"
+	"github.com/timebertt/kubectl-revisions/pkg/timeline"
+)
+
+type Options struct {
+	genericiooptions.IOStreams
+
+	Namespace    string
+	FromFiles    []string
+	HistoryFlags *util.HistoryFlags
+	ArchiveFlags *util.ArchiveFlags
+
+	Since     time.Duration
+	Output    string
+	NoHeaders bool
+}
+
+func NewOptions(streams genericiooptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:    streams,
+		HistoryFlags: util.NewHistoryFlags(),
+		ArchiveFlags: util.NewArchiveFlags(),
+	}
+}
+
+func NewCommand(f util.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use: "timeline (TYPE[.VERSION][.GROUP] NAME | TYPE[.VERSION][.GROUP]/NAME)",
+
+		Short: "Show a chronological timeline of the revisions, scaling, and pod failures of a workload resource",
+		Long: `Show a chronological timeline of the revisions, scaling, and pod failures of a workload resource.
+
+The timeline merges the following entries into a single view sorted by time:
+- the creation of each revision
+- ScalingReplicaSet and SuccessfulCreate Events of the workload resource and its revision objects
+- the last readiness transition of each pod belonging to a revision
+- the last restart of each container of these pods
+
+Every entry shows the revision it belongs to, if known. This helps to understand how a rollout progressed, e.g., when
+the pods of a new revision failed to become ready and when the old revision was scaled down.
+
+Events are only kept by the API server for a limited time (1 hour by default). Pods only record their last readiness
+transition and the last termination of each container, earlier transitions and restarts are not part of the timeline.
+
+If the --from-file flag is given, the workload resource, its revisions, its pods, and Events are read from the given
+files or directories (e.g., dumps created with "kubectl get -o yaml" or must-gather archives) instead of from a live
+cluster.
+`,
+
+		Example: `# Show the timeline of the nginx Deployment
+kubectl revisions timeline deploy nginx
+
+# Show the timeline of the last 30 minutes
+kubectl revisions timeline deploy nginx --since=30m
+
+# Show the timeline as JSON for processing it with other tools
+kubectl revisions timeline deploy nginx -o json
+`,
+
+		ValidArgsFunction: utilcomp.SpecifiedResourceTypeAndNameNoRepeatCompletionFunc(f, util.Map(history.SupportedKinds, strings.ToLower)),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run(cmd.Context(), f, args))
+		},
+	}
+
+	cmd.Flags().DurationVar(&o.Since, "since", o.Since, "Only show entries newer than a relative duration like 5s, 2m, or 3h. "+
+		"Defaults to all entries.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: (json, yaml).")
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", o.NoHeaders, "When using the default output format, don't print headers (default print headers).")
+	util.AddFromFileFlag(cmd, &o.FromFiles)
+	o.HistoryFlags.AddFlags(cmd)
+	o.ArchiveFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f util.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	return err
+}
+
+// Validate checks the set of flags provided by the user.
+func (o *Options) Validate() error {
+	if o.Output != "" && o.Output != "json" && o.Output != "yaml" {
+		return fmt.Errorf("unsupported output format %q, allowed formats are: json, yaml", o.Output)
+	}
+	if o.Since < 0 {
+		return fmt.Errorf("--since must not be negative")
+	}
+	return nil
+}
+
+// Run performs the timeline operation.
+func (o *Options) Run(ctx context.Context, f util.Factory, args []string) error {
+	objectRevisions, err := util.ListObjectRevisions(ctx, f, util.ObjectRevisionsOptions{
+		Namespace:    o.Namespace,
+		FromFiles:    o.FromFiles,
+		In:           o.In,
+		HistoryFlags: o.HistoryFlags,
+		ArchiveFlags: o.ArchiveFlags,
+	}, args)
+	if err != nil {
+		return err
+	}
+
+	obj := objectRevisions.Info.Object.(client.Object)
+
+	podList, err := history.ListPods(ctx, objectRevisions.Client, obj, history.SelectorPath(objectRevisions.History))
+	if err != nil {
+		return err
+	}
+
+	events, err := o.listEvents(ctx, objectRevisions.Client, obj.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	entries := timeline.Build(obj, objectRevisions.Revisions, events, history.MatchPods(podList, objectRevisions.Revisions))
+	if o.Since > 0 {
+		entries = entries.Since(time.Now().Add(-o.Since))
+	}
+
+	switch o.Output {
+	case "json", "yaml":
+		return o.printEntries(entries)
+	}
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintf(o.ErrOut, "No timeline entries found for %s.\n", objectRevisions)
+		return nil
+	}
+
+	p := printers.NewTablePrinter(printers.PrintOptions{NoHeaders: o.NoHeaders})
+	return p.PrintObj(printer.TimelineTable(entries), o.Out)
+}
+
+// listEvents lists the Events in the given namespace that might be included in the timeline. When reading from a live
+// cluster, only the Events with one of the timeline.EventReasons are listed using a field selector. Field selectors are
+// not supported when reading from files, all Events in the namespace are listed instead.
+func (o *Options) listEvents(ctx context.Context, r client.Reader, namespace string) ([]corev1.Event, error) {
+	if len(o.FromFiles) > 0 {
+		eventList := &corev1.EventList{}
+		if err := r.List(ctx, eventList, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("error listing Events: %w", err)
+		}
+		return eventList.Items, nil
+	}
+
+	// field selectors can't select multiple values of the same field, list the Events of each reason separately
+	var events []corev1.Event
+	for _, reason := range timeline.EventReasons {
+		eventList := &corev1.EventList{}
+		if err := r.List(ctx, eventList, client.InNamespace(namespace), client.MatchingFields{"reason": reason}); err != nil {
+			return nil, fmt.Errorf("error listing %s Events: %w", reason, err)
+		}
+		events = append(events, eventList.Items...)
+	}
+	return events, nil
+}
+
+// printEntries prints the given entries as a JSON or YAML list.
+func (o *Options) printEntries(entries timeline.Entries) error {
+	if entries == nil {
+		entries = timeline.Entries{}
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling timeline: %w", err)
+	}
+
+	if o.Output == "yaml" {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return fmt.Errorf("error marshalling timeline: %w", err)
+		}
+		_, err = o.Out.Write(data)
+		return err
+	}
+
+	_, err = fmt.Fprintln(o.Out, string(data))
+	return err
+}
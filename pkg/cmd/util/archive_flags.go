@@ -0,0 +1,61 @@
+package util
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/archive"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// ArchiveFlags contains flags for working with the local revision archive (see archive.Store).
+type ArchiveFlags struct {
+	// Enabled is true if archived revisions should be merged with live revisions.
+	Enabled bool
+	Dir     string
+}
+
+// NewArchiveFlags returns new ArchiveFlags with default values.
+func NewArchiveFlags() *ArchiveFlags {
+	return &ArchiveFlags{}
+}
+
+// AddFlags adds the --archive and --archive-dir flags to the given command.
+func (a *ArchiveFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&a.Enabled, "archive", a.Enabled, "Merge revisions from the local archive (see 'kubectl revisions record') "+
+		"with the revisions still present in the cluster.")
+	a.AddDirFlag(cmd)
+}
+
+// AddDirFlag adds only the --archive-dir flag to the given command.
+func (a *ArchiveFlags) AddDirFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&a.Dir, "archive-dir", a.Dir, "The directory of the local revision archive (defaults to "+
+		"kubectl-revisions/archive in the user's configuration directory, e.g., ~/.config on Linux).")
+	cmdutil.CheckErr(cmd.MarkFlagDirname("archive-dir"))
+}
+
+// ToStore returns the archive.Store configured by the flags.
+func (a *ArchiveFlags) ToStore() (*archive.Store, error) {
+	return archive.NewStore(a.Dir)
+}
+
+// Merge merges the archived revisions of the given object into the given live revisions if the archive is enabled.
+func (a *ArchiveFlags) Merge(gk schema.GroupKind, obj client.Object, revs history.Revisions) (history.Revisions, error) {
+	if !a.Enabled {
+		return revs, nil
+	}
+
+	store, err := a.ToStore()
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := store.List(gk, obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return archive.Merge(revs, archived)
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+// AddDiffEngineFlag adds the --diff-engine flag to the given command for selecting the diff.Program implementation.
+func AddDiffEngineFlag(cmd *cobra.Command, engine *diff.Engine) {
+	engines := make([]string, 0, len(diff.Engines))
+	for _, e := range diff.Engines {
+		engines = append(engines, string(e))
+	}
+
+	cmd.Flags().StringVar((*string)(engine), "diff-engine", string(*engine), fmt.Sprintf("The diff engine to use. One of: (%s). "+
+		"The external engine runs the external diff program, the builtin engine produces a unified diff without any external "+
+		"dependencies. The auto engine uses the external diff program if it can be found in PATH and falls back to the "+
+		"builtin engine otherwise.", strings.Join(engines, ", ")))
+
+	util.CheckErr(cmd.RegisterFlagCompletionFunc(
+		"diff-engine",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			var comps []string
+			for _, e := range engines {
+				if strings.HasPrefix(e, toComplete) {
+					comps = append(comps, e)
+				}
+			}
+			return comps, cobra.ShellCompDirectiveNoFileComp
+		},
+	))
+}
@@ -1,9 +1,15 @@
 package util
 
 import (
+	"fmt"
+	"sync"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/config"
 )
 
 // Factory augments the Factory interface for creating controller-runtime clients.
@@ -11,20 +17,38 @@ type Factory interface {
 	cmdutil.Factory
 	// Client returns a new controller-runtime client.
 	Client() (client.Client, error)
+	// Cache returns a new controller-runtime cache that reads objects from watch-based informers. The cache needs to be
+	// started before using it.
+	Cache(opts cache.Options) (cache.Cache, error)
+	// Config returns the plugin's configuration loaded from the configuration file.
+	Config() (*config.Config, error)
+	// ForContext returns a new factory for the given kubeconfig context, e.g., for comparing the same workload resource
+	// across clusters.
+	ForContext(context string) (Factory, error)
 }
 
-// NewFactory creates a new factory based on the given configuration.
-func NewFactory(clientGetter genericclioptions.RESTClientGetter) Factory {
-	return factoryImpl{
-		Factory: cmdutil.NewFactory(clientGetter),
+// NewFactory creates a new factory based on the given configuration. configFile points to the path of the plugin's
+// configuration file, see config.Load.
+func NewFactory(clientGetter genericclioptions.RESTClientGetter, configFile *string) Factory {
+	return &factoryImpl{
+		Factory:      cmdutil.NewFactory(clientGetter),
+		clientGetter: clientGetter,
+		configFile:   configFile,
 	}
 }
 
 type factoryImpl struct {
 	cmdutil.Factory
+
+	clientGetter genericclioptions.RESTClientGetter
+
+	configFile *string
+	configOnce sync.Once
+	config     *config.Config
+	configErr  error
 }
 
-func (f factoryImpl) Client() (client.Client, error) {
+func (f *factoryImpl) Client() (client.Client, error) {
 	restConfig, err := f.ToRESTConfig()
 	if err != nil {
 		return nil, err
@@ -38,3 +62,56 @@ func (f factoryImpl) Client() (client.Client, error) {
 
 	return client.New(restConfig, client.Options{Mapper: mapper})
 }
+
+func (f *factoryImpl) Cache(opts cache.Options) (cache.Cache, error) {
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Mapper == nil {
+		if opts.Mapper, err = f.ToRESTMapper(); err != nil {
+			return nil, err
+		}
+	}
+
+	return cache.New(restConfig, opts)
+}
+
+func (f *factoryImpl) Config() (*config.Config, error) {
+	f.configOnce.Do(func() {
+		var path string
+		if f.configFile != nil {
+			path = *f.configFile
+		}
+		f.config, f.configErr = config.Load(path)
+	})
+	return f.config, f.configErr
+}
+
+// ForContext returns a new factory for the given kubeconfig context. The kubeconfig file, cache directory, timeout, and
+// impersonation settings are inherited from the factory's config flags. Flags that override the cluster, user, or
+// namespace of the current context are not inherited, as they would also override the given context.
+func (f *factoryImpl) ForContext(context string) (Factory, error) {
+	flags, ok := f.clientGetter.(*genericclioptions.ConfigFlags)
+	if !ok {
+		return nil, fmt.Errorf("switching to context %q is not supported", context)
+	}
+
+	contextFlags := genericclioptions.NewConfigFlags(true).WithDiscoveryBurst(300).WithDiscoveryQPS(50.0)
+	contextFlags.Context = &context
+	contextFlags.KubeConfig = flags.KubeConfig
+	contextFlags.CacheDir = flags.CacheDir
+	contextFlags.Timeout = flags.Timeout
+	contextFlags.Impersonate = flags.Impersonate
+	contextFlags.ImpersonateUID = flags.ImpersonateUID
+	contextFlags.ImpersonateGroup = flags.ImpersonateGroup
+	contextFlags.DisableCompression = flags.DisableCompression
+	contextFlags.WrapConfigFn = flags.WrapConfigFn
+
+	return &factoryImpl{
+		Factory:      cmdutil.NewFactory(contextFlags),
+		clientGetter: contextFlags,
+		configFile:   f.configFile,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/utils/ptr"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+users:
+- name: user
+  user:
+    token: foo
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    user: user
+    namespace: staging-ns
+- name: prod
+  context:
+    cluster: prod
+    user: user
+    namespace: prod-ns
+current-context: staging
+`
+
+var _ = Describe("Factory", func() {
+	Describe("#ForContext", func() {
+		var (
+			flags *genericclioptions.ConfigFlags
+			f     Factory
+		)
+
+		BeforeEach(func() {
+			path := filepath.Join(GinkgoT().TempDir(), "kubeconfig")
+			Expect(os.WriteFile(path, []byte(kubeconfig), 0600)).To(Succeed())
+
+			flags = genericclioptions.NewConfigFlags(true)
+			flags.KubeConfig = ptr.To(path)
+			f = NewFactory(flags, nil)
+		})
+
+		It("should use the given context", func() {
+			contextFactory, err := f.ForContext("prod")
+			Expect(err).NotTo(HaveOccurred())
+
+			restConfig, err := contextFactory.ToRESTConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restConfig.Host).To(Equal("https://prod.example.com"))
+
+			Expect(contextFactory.ToRawKubeConfigLoader().Namespace()).To(Equal("prod-ns"))
+		})
+
+		It("should not inherit overrides of the current context", func() {
+			flags.Context = ptr.To("staging")
+			flags.Namespace = ptr.To("other")
+			flags.APIServer = ptr.To("https://other.example.com")
+
+			contextFactory, err := f.ForContext("prod")
+			Expect(err).NotTo(HaveOccurred())
+
+			restConfig, err := contextFactory.ToRESTConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restConfig.Host).To(Equal("https://prod.example.com"))
+
+			namespace, _, err := contextFactory.ToRawKubeConfigLoader().Namespace()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(namespace).To(Equal("prod-ns"))
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/config"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// HistoryFlags contains flags for reading the ControllerRevision-based history of arbitrary kinds (e.g., custom
+// resources) via history.GenericHistory.
+type HistoryFlags struct {
+	SelectorPath string
+	TemplatePath string
+	DataFormat   string
+}
+
+// NewHistoryFlags returns new HistoryFlags with default values.
+func NewHistoryFlags() *HistoryFlags {
+	return &HistoryFlags{}
+}
+
+// AddFlags adds the flags to the given command.
+func (h *HistoryFlags) AddFlags(cmd *cobra.Command) {
+	formats := make([]string, 0, len(history.DataFormats))
+	for _, format := range history.DataFormats {
+		formats = append(formats, string(format))
+	}
+
+	cmd.Flags().StringVar(&h.SelectorPath, "selector-path", h.SelectorPath, "For kinds that store their history in "+
+		"ControllerRevisions (e.g., custom resources), the dot-separated path of the label selector in the object. "+
+		"Defaults to spec.selector.")
+	cmd.Flags().StringVar(&h.TemplatePath, "template-path", h.TemplatePath, "For kinds that store their history in "+
+		"ControllerRevisions (e.g., custom resources), the dot-separated path of the pod template in the ControllerRevision "+
+		"data. Defaults to spec.template.")
+	cmd.Flags().StringVar(&h.DataFormat, "revision-data-format", h.DataFormat, fmt.Sprintf("For kinds that store their "+
+		"history in ControllerRevisions (e.g., custom resources), how to decode the ControllerRevision data. One of: (%s). "+
+		"Defaults to patch.", strings.Join(formats, ", ")))
+
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
+		"revision-data-format",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			var comps []string
+			for _, f := range formats {
+				if strings.HasPrefix(f, toComplete) {
+					comps = append(comps, f)
+				}
+			}
+			return comps, cobra.ShellCompDirectiveNoFileComp
+		},
+	))
+}
+
+// IsSet returns true if any of the flags is set.
+func (h *HistoryFlags) IsSet() bool {
+	return h.SelectorPath != "" || h.TemplatePath != "" || h.DataFormat != ""
+}
+
+// ForGroupKind instantiates a new History client for the given GroupKind. If the flags are set or the kind is
+// configured in the given config, a history.GenericHistory is returned, where the flags take precedence over the
+// config. Otherwise, this falls back to history.ForGroupKind.
+func (h *HistoryFlags) ForGroupKind(c client.Reader, gk schema.GroupKind, cfg *config.Config) (history.History, error) {
+	kind, configured := history.GenericKind{}, false
+	if cfg != nil {
+		kind, configured = cfg.GenericKind(gk)
+	}
+
+	if !configured && !h.IsSet() {
+		hist, err := history.ForGroupKind(c, gk)
+		if err != nil {
+			return nil, fmt.Errorf("%w, configure how to read its ControllerRevision-based history via flags (e.g., "+
+				"--selector-path) or the config file", err)
+		}
+		return hist, nil
+	}
+
+	kind.Group, kind.Kind = gk.Group, gk.Kind
+	if h.SelectorPath != "" {
+		kind.SelectorPath = h.SelectorPath
+	}
+	if h.TemplatePath != "" {
+		kind.TemplatePath = h.TemplatePath
+	}
+	if h.DataFormat != "" {
+		kind.DataFormat = history.DataFormat(h.DataFormat)
+	}
+
+	if err := kind.Validate(); err != nil {
+		return nil, err
+	}
+
+	return history.GenericHistory{Client: c, Kind: kind}, nil
+}
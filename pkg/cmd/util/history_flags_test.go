@@ -0,0 +1,86 @@
+package util_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/config"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("HistoryFlags", func() {
+	var (
+		flags    *HistoryFlags
+		cfg      *config.Config
+		cloneSet schema.GroupKind
+	)
+
+	BeforeEach(func() {
+		flags = NewHistoryFlags()
+		cfg = &config.Config{Kinds: []history.GenericKind{{
+			Group:        "apps.kruise.io",
+			Kind:         "CloneSet",
+			SelectorPath: "spec.selector",
+			TemplatePath: "spec.template",
+		}}}
+		cloneSet = schema.GroupKind{Group: "apps.kruise.io", Kind: "CloneSet"}
+	})
+
+	Describe("#ForGroupKind", func() {
+		It("should return the built-in history for supported kinds", func() {
+			hist, err := flags.ForGroupKind(fakeclient.NewFakeClient(), appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind(), cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hist).To(BeAssignableToTypeOf(history.DeploymentHistory{}))
+		})
+
+		It("should fail for unsupported kinds that are not configured", func() {
+			_, err := flags.ForGroupKind(fakeclient.NewFakeClient(), schema.GroupKind{Group: "example.com", Kind: "Foo"}, cfg)
+			Expect(err).To(MatchError(ContainSubstring("Foo.example.com is not supported, configure")))
+		})
+
+		It("should return a generic history for configured kinds", func() {
+			hist, err := flags.ForGroupKind(fakeclient.NewFakeClient(), cloneSet, cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hist).To(BeAssignableToTypeOf(history.GenericHistory{}))
+			Expect(hist.(history.GenericHistory).Kind).To(Equal(cfg.Kinds[0]))
+		})
+
+		It("should prefer flags over the config", func() {
+			flags.TemplatePath = "spec.other"
+			flags.DataFormat = "object"
+
+			hist, err := flags.ForGroupKind(fakeclient.NewFakeClient(), cloneSet, cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hist.(history.GenericHistory).Kind).To(Equal(history.GenericKind{
+				Group:        "apps.kruise.io",
+				Kind:         "CloneSet",
+				SelectorPath: "spec.selector",
+				TemplatePath: "spec.other",
+				DataFormat:   history.DataFormatObject,
+			}))
+		})
+
+		It("should return a generic history for unconfigured kinds if flags are given", func() {
+			flags.SelectorPath = "spec.selector"
+
+			hist, err := flags.ForGroupKind(fakeclient.NewFakeClient(), schema.GroupKind{Group: "example.com", Kind: "Foo"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hist.(history.GenericHistory).Kind).To(Equal(history.GenericKind{
+				Group:        "example.com",
+				Kind:         "Foo",
+				SelectorPath: "spec.selector",
+			}))
+		})
+
+		It("should fail for invalid flags", func() {
+			flags.DataFormat = "foo"
+
+			_, err := flags.ForGroupKind(fakeclient.NewFakeClient(), cloneSet, cfg)
+			Expect(err).To(MatchError(ContainSubstring(`unsupported data format "foo"`)))
+		})
+	})
+})
@@ -0,0 +1,20 @@
+package util
+
+import (
+	"k8s.io/cli-runtime/pkg/resource"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// ToTypedInfos converts the unstructured objects of the given infos to typed objects if their kinds are registered in
+// history.Scheme (see history.ToTyped). The resource.Builder needs to work on unstructured objects for supporting kinds
+// without typed API objects like Argo Rollouts.
+func ToTypedInfos(infos []*resource.Info) error {
+	for _, info := range infos {
+		var err error
+		if info.Object, err = history.ToTyped(info.Object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
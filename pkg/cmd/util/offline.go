@@ -0,0 +1,172 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/offline"
+)
+
+// offlineShortNames maps the short names of the supported kinds to their resource names. When reading objects from
+// files, there is no discovery information for expanding short names.
+var offlineShortNames = map[string]string{
+	"deploy": "deployments",
+	"sts":    "statefulsets",
+	"ds":     "daemonsets",
+}
+
+// OfflineInfos resolves the given resource arguments against the objects loaded into the given offline.Reader. It
+// supports the same argument formats as resource.Builder.ResourceTypeOrNameArgs, i.e., `TYPE [NAME...]` and
+// `TYPE/NAME...`, but only a single resource type. The returned bool is true if the arguments imply that a single item
+// was targeted.
+func OfflineInfos(ctx context.Context, r *offline.Reader, args []string, namespace string, allNamespaces bool, labelSelector string) ([]*resource.Info, bool, error) {
+	if len(args) == 0 {
+		return nil, false, fmt.Errorf("you must specify the type of resource to get")
+	}
+
+	if allNamespaces {
+		namespace = ""
+	}
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		mapping *meta.RESTMapping
+		names   []string
+	)
+
+	if strings.Contains(args[0], "/") {
+		// TYPE/NAME...
+		for _, arg := range args {
+			resourceArg, name, ok := strings.Cut(arg, "/")
+			if !ok || name == "" {
+				return nil, false, fmt.Errorf("there is no need to specify a resource type as a separate argument when passing arguments in resource/name form")
+			}
+
+			m, err := OfflineMapping(r, resourceArg)
+			if err != nil {
+				return nil, false, err
+			}
+			if mapping != nil && m.GroupVersionKind != mapping.GroupVersionKind {
+				return nil, false, fmt.Errorf("you may only specify a single resource type")
+			}
+
+			mapping = m
+			names = append(names, name)
+		}
+	} else {
+		// TYPE [NAME...]
+		if mapping, err = OfflineMapping(r, args[0]); err != nil {
+			return nil, false, err
+		}
+		names = args[1:]
+	}
+
+	if len(names) > 0 && !selector.Empty() {
+		return nil, false, fmt.Errorf("name cannot be provided when a selector is specified")
+	}
+
+	var infos []*resource.Info
+
+	if len(names) > 0 {
+		for _, name := range names {
+			clientObj, err := r.New(mapping.GroupVersionKind)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, clientObj); err != nil {
+				return nil, false, err
+			}
+
+			infos = append(infos, &resource.Info{Namespace: namespace, Name: name, Object: clientObj, Mapping: mapping})
+		}
+
+		return infos, len(names) == 1, nil
+	}
+
+	list, err := r.NewList(mapping.GroupVersionKind)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, false, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, item := range items {
+		obj := item.(client.Object)
+		infos = append(infos, &resource.Info{Namespace: obj.GetNamespace(), Name: obj.GetName(), Object: obj, Mapping: mapping})
+	}
+
+	return infos, false, nil
+}
+
+// OfflineMapping resolves the given resource argument (e.g., `deploy`, `deployments.apps`, or `Deployment`) to a
+// RESTMapping without using discovery. It supports the supported kinds registered in the given reader's scheme and all
+// loaded kinds that are not registered in the scheme (e.g., Argo Rollouts or custom resources with a
+// ControllerRevision-based history). The resource names of the latter are guessed from their kinds.
+func OfflineMapping(r *offline.Reader, resourceArg string) (*meta.RESTMapping, error) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for gvk := range r.Scheme().AllKnownTypes() {
+		if slices.Contains(history.SupportedKinds, gvk.Kind) {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+	}
+	for _, gvk := range r.Kinds() {
+		if !r.Scheme().Recognizes(gvk) {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+	}
+
+	resourceName, rest, hasGroup := strings.Cut(resourceArg, ".")
+	if expanded, ok := offlineShortNames[strings.ToLower(resourceName)]; ok {
+		resourceArg = expanded
+		if hasGroup {
+			resourceArg += "." + rest
+		}
+	}
+
+	var gvk schema.GroupVersionKind
+	fullySpecifiedGVR, groupResource := schema.ParseResourceArg(resourceArg)
+	if fullySpecifiedGVR != nil {
+		gvk, _ = mapper.KindFor(*fullySpecifiedGVR)
+	}
+	if gvk.Empty() {
+		var err error
+		if gvk, err = mapper.KindFor(groupResource.WithVersion("")); err != nil {
+			return nil, fmt.Errorf("resource type %q is not supported when reading objects from files", resourceArg)
+		}
+	}
+
+	return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// AddFromFileFlag adds the --from-file flag to the given command for reading objects from files instead of from a live
+// cluster.
+func AddFromFileFlag(cmd *cobra.Command, fromFiles *[]string) {
+	cmd.Flags().StringSliceVar(fromFiles, "from-file", *fromFiles, "Read the workload resource and its revisions from the given files or directories "+
+		"(e.g., dumps created with `kubectl get -o yaml`) instead of from a live cluster. Directories are read recursively, "+
+		"- reads from stdin. Can be specified multiple times. Objects of kinds that are not built into Kubernetes (e.g., "+
+		"Argo Rollouts) are addressed by their plural resource name derived from the kind, e.g., rollouts.argoproj.io.")
+	cmdutil.CheckErr(cmd.MarkFlagFilename("from-file", "yaml", "yml", "json"))
+}
@@ -0,0 +1,155 @@
+package util_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/cmd/util"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/offline"
+	. "github.com/timebertt/kubectl-revisions/pkg/test/matcher"
+)
+
+var _ = Describe("OfflineMapping", func() {
+	var r *offline.Reader
+
+	BeforeEach(func() {
+		r = offline.NewReader(history.Scheme)
+	})
+
+	DescribeTable("should resolve supported resource arguments",
+		func(arg, expectedKind string) {
+			mapping, err := OfflineMapping(r, arg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mapping.GroupVersionKind).To(Equal(appsv1.SchemeGroupVersion.WithKind(expectedKind)))
+		},
+		Entry("short name", "deploy", "Deployment"),
+		Entry("short name with group", "sts.apps", "StatefulSet"),
+		Entry("singular", "daemonset", "DaemonSet"),
+		Entry("plural", "deployments", "Deployment"),
+		Entry("kind", "Deployment", "Deployment"),
+		Entry("grouped", "statefulsets.apps", "StatefulSet"),
+		Entry("fully-qualified", "daemonsets.v1.apps", "DaemonSet"),
+	)
+
+	It("should resolve loaded kinds that are not registered in the scheme", func() {
+		Expect(r.Load(strings.NewReader(rolloutManifest))).To(Succeed())
+
+		mapping, err := OfflineMapping(r, "rollouts.argoproj.io")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mapping.GroupVersionKind).To(Equal(schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}))
+		Expect(mapping.Resource.Resource).To(Equal("rollouts"))
+	})
+
+	It("should fail for unsupported resource arguments", func() {
+		_, err := OfflineMapping(r, "replicasets")
+		Expect(err).To(MatchError(ContainSubstring(`resource type "replicasets" is not supported`)))
+	})
+})
+
+var _ = Describe("OfflineInfos", func() {
+	var (
+		ctx context.Context
+		r   *offline.Reader
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		r = offline.NewReader(history.Scheme)
+
+		Expect(r.Add(
+			deployment("test", "app1", "app"),
+			deployment("test", "app2", "app"),
+			deployment("test", "other", "other"),
+			deployment("other", "app3", "app"),
+		)).To(Succeed())
+	})
+
+	It("should get a single object by type and name", func() {
+		infos, singleItemImplied, err := OfflineInfos(ctx, r, []string{"deploy", "app1"}, "test", false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(singleItemImplied).To(BeTrue())
+		Expect(infos).To(HaveExactElements(
+			And(HaveField("Namespace", "test"), HaveField("Name", "app1"), HaveField("Object", BeAssignableToTypeOf(&appsv1.Deployment{}))),
+		))
+		Expect(infos[0].Mapping.GroupVersionKind.Kind).To(Equal("Deployment"))
+	})
+
+	It("should get multiple objects in slash form", func() {
+		infos, singleItemImplied, err := OfflineInfos(ctx, r, []string{"deploy/app1", "deployment/app2"}, "test", false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(singleItemImplied).To(BeFalse())
+		Expect(infos).To(HaveExactElements(HaveField("Name", "app1"), HaveField("Name", "app2")))
+	})
+
+	It("should list all objects in the namespace", func() {
+		infos, singleItemImplied, err := OfflineInfos(ctx, r, []string{"deploy"}, "test", false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(singleItemImplied).To(BeFalse())
+		Expect(infos).To(ConsistOf(HaveField("Name", "app1"), HaveField("Name", "app2"), HaveField("Name", "other")))
+	})
+
+	It("should list label-selected objects in all namespaces", func() {
+		infos, _, err := OfflineInfos(ctx, r, []string{"deploy"}, "test", true, "app=app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infos).To(ConsistOf(HaveField("Name", "app1"), HaveField("Name", "app2"), HaveField("Name", "app3")))
+	})
+
+	It("should get unstructured objects of kinds that are not registered in the scheme", func() {
+		Expect(r.Load(strings.NewReader(rolloutManifest))).To(Succeed())
+
+		infos, _, err := OfflineInfos(ctx, r, []string{"rollout", "app"}, "test", false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infos).To(HaveExactElements(
+			And(HaveField("Name", "app"), HaveField("Object", BeAssignableToTypeOf(&unstructured.Unstructured{}))),
+		))
+
+		infos, _, err = OfflineInfos(ctx, r, []string{"rollouts"}, "test", false, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(infos).To(HaveExactElements(HaveField("Name", "app")))
+	})
+
+	It("should fail if the object doesn't exist", func() {
+		_, _, err := OfflineInfos(ctx, r, []string{"deploy", "app3"}, "test", false, "")
+		Expect(err).To(BeNotFoundError())
+	})
+
+	It("should fail for multiple resource types", func() {
+		_, _, err := OfflineInfos(ctx, r, []string{"deploy/app1", "sts/app2"}, "test", false, "")
+		Expect(err).To(MatchError("you may only specify a single resource type"))
+	})
+
+	It("should fail for names combined with a selector", func() {
+		_, _, err := OfflineInfos(ctx, r, []string{"deploy", "app1"}, "test", false, "app=app")
+		Expect(err).To(MatchError("name cannot be provided when a selector is specified"))
+	})
+
+	It("should fail without args", func() {
+		_, _, err := OfflineInfos(ctx, r, nil, "test", false, "")
+		Expect(err).To(MatchError(ContainSubstring("you must specify the type of resource")))
+	})
+})
+
+const rolloutManifest = `apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: app
+  namespace: test
+`
+
+func deployment(namespace, name, app string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": app},
+		},
+	}
+}
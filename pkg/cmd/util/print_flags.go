@@ -21,6 +21,10 @@ type PrintFlags struct {
 	TableFlags         *TablePrintFlags
 
 	TemplateOnly bool
+
+	// CommandFormats is a list of additional output formats that are handled by the command itself instead of a
+	// printer returned by ToPrinter.
+	CommandFormats []string
 }
 
 func NewPrintFlags() *PrintFlags {
@@ -39,9 +43,25 @@ func (f *PrintFlags) AllowedFormats() []string {
 	if f.TableFlags != nil {
 		formats = append(formats, f.TableFlags.AllowedFormats()...)
 	}
+	formats = append(formats, f.CommandFormats...)
 	return formats
 }
 
+// CommandFormat returns the selected output format if it is one of CommandFormats. Otherwise, it returns an empty
+// string.
+func (f *PrintFlags) CommandFormat() string {
+	if f.OutputFormat == nil {
+		return ""
+	}
+
+	for _, format := range f.CommandFormats {
+		if *f.OutputFormat == format {
+			return format
+		}
+	}
+	return ""
+}
+
 func (f *PrintFlags) AddFlags(cmd *cobra.Command) {
 	f.PrintFlags.AddFlags(cmd)
 	f.TableFlags.AddFlags(cmd)
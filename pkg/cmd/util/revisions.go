@@ -0,0 +1,128 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/offline"
+)
+
+// RevisionSelectorHelp describes the syntax of history.ParseSelector for the help text of --revision flags.
+const RevisionSelectorHelp = "Revisions can also be selected by name or pod-template-hash, " +
+	"latest, current/update for the revision that is currently running/being rolled out, " +
+	"@{2h} for the revision that was live 2 hours ago, image=nginx:1.25 for the newest revision running the given image, " +
+	"or commit:3f2a9c1 for the newest revision deployed from the given GitOps source commit."
+
+// ObjectRevisionsOptions configures how the revisions of a single workload object are read, see ListObjectRevisions.
+type ObjectRevisionsOptions struct {
+	Namespace string
+	// FromFiles is the list of files to read the object and its revisions from instead of from a live cluster.
+	FromFiles []string
+	// In is used for reading from stdin if FromFiles contains "-".
+	In io.Reader
+
+	HistoryFlags *HistoryFlags
+	ArchiveFlags *ArchiveFlags
+}
+
+// ObjectRevisions is the revision history of a single workload object.
+type ObjectRevisions struct {
+	Info      *resource.Info
+	Revisions history.Revisions
+	// History is the History that was used for listing the revisions.
+	History history.History
+
+	// Client is the reader that was used for reading the object and its revisions, i.e., a client for the live cluster
+	// or the offline reader if --from-file was given.
+	Client client.Reader
+}
+
+// GroupKind returns the GroupKind of the workload object.
+func (o *ObjectRevisions) GroupKind() schema.GroupKind {
+	return o.Info.Mapping.GroupVersionKind.GroupKind()
+}
+
+// String returns a human-readable reference to the workload object, e.g., deployment.apps/nginx.
+func (o *ObjectRevisions) String() string {
+	gk := o.GroupKind()
+	return fmt.Sprintf("%s.%s/%s", strings.ToLower(gk.Kind), gk.Group, o.Info.Name)
+}
+
+// ListObjectRevisions reads the single workload object specified by the given args and returns its revisions. It
+// fails if the object has no revisions.
+func ListObjectRevisions(ctx context.Context, f Factory, opts ObjectRevisionsOptions, args []string) (*ObjectRevisions, error) {
+	var (
+		c     client.Reader
+		infos []*resource.Info
+		err   error
+	)
+
+	if len(opts.FromFiles) > 0 {
+		r := offline.NewReader(history.Scheme)
+		if err := r.LoadFiles(opts.FromFiles, opts.In); err != nil {
+			return nil, err
+		}
+		c = r
+
+		if infos, _, err = OfflineInfos(ctx, r, args, opts.Namespace, false, ""); err != nil {
+			return nil, err
+		}
+		if len(infos) != 1 {
+			return nil, fmt.Errorf("expected a single resource, but got %d", len(infos))
+		}
+	} else {
+		r := f.NewBuilder().
+			Unstructured().
+			NamespaceParam(opts.Namespace).DefaultNamespace().
+			ResourceTypeOrNameArgs(true, args...).
+			SingleResourceType().
+			Do()
+
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+
+		if c, err = f.Client(); err != nil {
+			return nil, err
+		}
+
+		if infos, err = r.Infos(); err != nil {
+			return nil, err
+		}
+		if err = ToTypedInfos(infos); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ObjectRevisions{Info: infos[0], Client: c}
+	obj := result.Info.Object.(client.Object)
+
+	cfg, err := f.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	if result.History, err = opts.HistoryFlags.ForGroupKind(c, result.GroupKind(), cfg); err != nil {
+		return nil, err
+	}
+
+	// get all revisions for the given object
+	if result.Revisions, err = result.History.ListRevisions(ctx, obj); err != nil {
+		return nil, err
+	}
+	if result.Revisions, err = opts.ArchiveFlags.Merge(result.GroupKind(), obj, result.Revisions); err != nil {
+		return nil, err
+	}
+	if len(result.Revisions) == 0 {
+		return nil, fmt.Errorf("no revisions found for %s", result)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/timebertt/kubectl-revisions/pkg/diff"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// Config is the configuration file of the revisions plugin.
+type Config struct {
+	// Kinds configures additional kinds that store their history in ControllerRevisions, e.g., custom resources of
+	// operators like OpenKruise's CloneSet.
+	Kinds []history.GenericKind `json:"kinds,omitempty"`
+	// Diff configures the defaults of the diff command.
+	Diff Diff `json:"diff,omitempty"`
+}
+
+// Diff configures the defaults of the diff command.
+type Diff struct {
+	// Ignore configures fields that are ignored when comparing revisions. The corresponding --ignore-* flags of the diff
+	// command replace the configured values.
+	Ignore diff.Ignore `json:"ignore,omitempty"`
+}
+
+// DefaultPath returns the default path of the configuration file, i.e., kubectl-revisions/config.yaml in the user's
+// configuration directory (e.g., ~/.config on Linux).
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubectl-revisions", "config.yaml")
+}
+
+// Load loads the configuration file from the given path. If path is empty, the configuration is loaded from
+// DefaultPath if the file exists. Otherwise, an empty configuration is returned.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	config := &Config{}
+	if path == "" {
+		return config, nil
+	}
+
+	// nolint:gosec // the file is explicitly specified by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Validate validates the configuration.
+func (c *Config) Validate() error {
+	for i, kind := range c.Kinds {
+		if err := kind.Validate(); err != nil {
+			return fmt.Errorf("kinds[%d]: %w", i, err)
+		}
+	}
+	if err := c.Diff.Ignore.Validate(); err != nil {
+		return fmt.Errorf("diff.ignore: %w", err)
+	}
+	return nil
+}
+
+// GenericKind returns the configuration for the given GroupKind if it is configured.
+func (c *Config) GenericKind(gk schema.GroupKind) (history.GenericKind, bool) {
+	for _, kind := range c.Kinds {
+		if kind.GroupKind() == gk {
+			return kind, true
+		}
+	}
+	return history.GenericKind{}, false
+}
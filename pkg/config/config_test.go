@@ -0,0 +1,116 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/config"
+	"github.com/timebertt/kubectl-revisions/pkg/diff"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("Config", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", dir)
+		GinkgoT().Setenv("HOME", dir)
+	})
+
+	Describe("#Load", func() {
+		It("should load the given file", func() {
+			path := filepath.Join(dir, "config.yaml")
+			Expect(os.WriteFile(path, []byte(`kinds:
+- group: apps.kruise.io
+  kind: CloneSet
+  dataFormat: patch
+`), 0600)).To(Succeed())
+
+			config, err := Load(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Kinds).To(HaveExactElements(history.GenericKind{
+				Group:      "apps.kruise.io",
+				Kind:       "CloneSet",
+				DataFormat: history.DataFormatPatch,
+			}))
+		})
+
+		It("should load the default file", func() {
+			Expect(os.MkdirAll(filepath.Dir(DefaultPath()), 0700)).To(Succeed())
+			Expect(os.WriteFile(DefaultPath(), []byte(`kinds: [{kind: CloneSet}]`), 0600)).To(Succeed())
+
+			config, err := Load("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Kinds).To(HaveLen(1))
+		})
+
+		It("should return an empty config if the default file doesn't exist", func() {
+			Expect(Load("")).To(Equal(&Config{}))
+		})
+
+		It("should fail if the given file doesn't exist", func() {
+			_, err := Load(filepath.Join(dir, "non-existing"))
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+
+		It("should fail for unknown fields", func() {
+			path := filepath.Join(dir, "config.yaml")
+			Expect(os.WriteFile(path, []byte(`foo: bar`), 0600)).To(Succeed())
+
+			_, err := Load(path)
+			Expect(err).To(MatchError(ContainSubstring(`unknown field "foo"`)))
+		})
+
+		It("should fail for invalid kinds", func() {
+			path := filepath.Join(dir, "config.yaml")
+			Expect(os.WriteFile(path, []byte(`kinds: [{kind: CloneSet, dataFormat: foo}]`), 0600)).To(Succeed())
+
+			_, err := Load(path)
+			Expect(err).To(MatchError(ContainSubstring(`kinds[0]: unsupported data format "foo"`)))
+		})
+
+		It("should load the diff configuration", func() {
+			path := filepath.Join(dir, "config.yaml")
+			Expect(os.WriteFile(path, []byte(`diff:
+  ignore:
+    paths: ["spec.containers[*].env[name=BUILD_ID]"]
+    annotations: [kubectl.kubernetes.io/restartedAt]
+    labels: [build]
+`), 0600)).To(Succeed())
+
+			config, err := Load(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Diff.Ignore).To(Equal(diff.Ignore{
+				Paths:       []string{"spec.containers[*].env[name=BUILD_ID]"},
+				Annotations: []string{"kubectl.kubernetes.io/restartedAt"},
+				Labels:      []string{"build"},
+			}))
+		})
+
+		It("should fail for invalid ignored field paths", func() {
+			path := filepath.Join(dir, "config.yaml")
+			Expect(os.WriteFile(path, []byte(`diff: {ignore: {paths: ["spec[foo"]}}`), 0600)).To(Succeed())
+
+			_, err := Load(path)
+			Expect(err).To(MatchError(ContainSubstring(`diff.ignore: invalid field path "spec[foo"`)))
+		})
+	})
+
+	Describe("#GenericKind", func() {
+		It("should find the configured kind", func() {
+			config := &Config{Kinds: []history.GenericKind{{Group: "apps.kruise.io", Kind: "CloneSet"}}}
+
+			kind, ok := config.GenericKind(schema.GroupKind{Group: "apps.kruise.io", Kind: "CloneSet"})
+			Expect(ok).To(BeTrue())
+			Expect(kind.Kind).To(Equal("CloneSet"))
+
+			_, ok = config.GenericKind(schema.GroupKind{Group: "apps", Kind: "CloneSet"})
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,41 @@
+package diff
+
+import (
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// BlameLine is a single line of the last version passed to Blame.
+type BlameLine struct {
+	// Line is the content of the line.
+	Line string
+	// Origin is the index of the version that introduced the line.
+	Origin int
+}
+
+// Blame determines which of the given versions introduced the lines of the last version. The versions are given as
+// lists of lines and must be ordered from oldest to newest. Like `git blame`, every pair of consecutive versions is
+// compared and lines that are unchanged between the versions keep the origin they had in the older version.
+func Blame(versions [][]string) []BlameLine {
+	var blamed []BlameLine
+
+	for i, lines := range versions {
+		next := make([]BlameLine, len(lines))
+		for j, line := range lines {
+			next[j] = BlameLine{Line: line, Origin: i}
+		}
+
+		if i > 0 {
+			// disable the junk heuristic, otherwise frequent lines (e.g., closing brackets) are never matched
+			matcher := difflib.NewMatcherWithJunk(versions[i-1], lines, false, nil)
+			for _, block := range matcher.GetMatchingBlocks() {
+				for k := 0; k < block.Size; k++ {
+					next[block.B+k].Origin = blamed[block.A+k].Origin
+				}
+			}
+		}
+
+		blamed = next
+	}
+
+	return blamed
+}
@@ -0,0 +1,59 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("Blame", func() {
+	It("should return nothing for no versions", func() {
+		Expect(Blame(nil)).To(BeEmpty())
+	})
+
+	It("should attribute all lines to the only version", func() {
+		Expect(Blame([][]string{{"a", "b"}})).To(HaveExactElements(
+			BlameLine{Line: "a", Origin: 0},
+			BlameLine{Line: "b", Origin: 0},
+		))
+	})
+
+	It("should attribute lines to the version that introduced them", func() {
+		Expect(Blame([][]string{
+			{"a", "b", "c"},
+			{"a", "B", "c"},
+			{"a", "B", "c", "d"},
+		})).To(HaveExactElements(
+			BlameLine{Line: "a", Origin: 0},
+			BlameLine{Line: "B", Origin: 1},
+			BlameLine{Line: "c", Origin: 0},
+			BlameLine{Line: "d", Origin: 2},
+		))
+	})
+
+	It("should attribute lines that were reverted to the reverting version", func() {
+		Expect(Blame([][]string{
+			{"a", "b"},
+			{"a", "B"},
+			{"a", "b"},
+		})).To(HaveExactElements(
+			BlameLine{Line: "a", Origin: 0},
+			BlameLine{Line: "b", Origin: 2},
+		))
+	})
+
+	It("should match frequent lines", func() {
+		var from, to []string
+		for i := 0; i < 300; i++ {
+			from = append(from, "-")
+			to = append(to, "-")
+		}
+		to = append(to, "new")
+
+		blamed := Blame([][]string{from, to})
+		Expect(blamed).To(HaveLen(301))
+		Expect(blamed[:300]).To(HaveEach(HaveField("Origin", 0)))
+		Expect(blamed[300]).To(Equal(BlameLine{Line: "new", Origin: 1}))
+	})
+})
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/utils/exec"
+)
+
+var _ Program = &BuiltinProgram{}
+
+// BuiltinProgram is a pure-Go Program implementation that doesn't depend on an external diff program.
+// It writes a unified diff of the given files to Out. If directories are given, all files contained in them are compared
+// pair-wise by name. Similar to `diff -u -N`, files that are only present in one of the directories are compared with
+// an empty file. Like `diff`, Run returns an exec.ExitError with exit status 1 if the files differ.
+type BuiltinProgram struct {
+	Out io.Writer
+
+	// Context is the number of context lines to print around each change.
+	Context int
+}
+
+// NewBuiltinProgram returns a BuiltinProgram writing to the given writer using 3 lines of context like `diff -u`.
+func NewBuiltinProgram(out io.Writer) *BuiltinProgram {
+	return &BuiltinProgram{
+		Out:     out,
+		Context: 3,
+	}
+}
+
+// Run compares the given files or directories and writes a unified diff to Out. It returns an exec.ExitError with exit
+// status 1 if any of the files differ.
+func (p *BuiltinProgram) Run(a, b string) error {
+	info, err := os.Stat(a)
+	if err != nil {
+		return err
+	}
+
+	var differ bool
+	if !info.IsDir() {
+		if differ, err = p.compareFiles(a, b); err != nil {
+			return err
+		}
+	} else {
+		names, err := fileNames(a, b)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			fileDiffers, err := p.compareFiles(filepath.Join(a, name), filepath.Join(b, name))
+			if err != nil {
+				return err
+			}
+			differ = differ || fileDiffers
+		}
+	}
+
+	if differ {
+		return exec.CodeExitError{Err: errors.New("exit status 1"), Code: 1}
+	}
+	return nil
+}
+
+// compareFiles writes a unified diff of the given files to Out and returns true if they differ.
+func (p *BuiltinProgram) compareFiles(a, b string) (bool, error) {
+	from, err := readLines(a)
+	if err != nil {
+		return false, err
+	}
+	to, err := readLines(b)
+	if err != nil {
+		return false, err
+	}
+
+	if slices.Equal(from, to) {
+		return false, nil
+	}
+
+	return true, difflib.WriteUnifiedDiff(p.Out, difflib.UnifiedDiff{
+		A:        from,
+		B:        to,
+		FromFile: a,
+		ToFile:   b,
+		Context:  p.Context,
+	})
+}
+
+// fileNames returns the sorted union of the names of all regular files in the given directories.
+func fileNames(dirs ...string) ([]string, error) {
+	set := make(map[string]struct{})
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				set[entry.Name()] = struct{}{}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// readLines returns the lines of the given file including line endings. A missing file is treated as an empty file.
+func readLines(name string) ([]string, error) {
+	// nolint:gosec // the file names are controlled by the caller
+	content, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+
+	return lines, nil
+}
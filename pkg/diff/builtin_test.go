@@ -0,0 +1,109 @@
+package diff_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"k8s.io/utils/exec"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("BuiltinProgram", func() {
+	var (
+		out *Buffer
+		p   *BuiltinProgram
+
+		f *Files
+	)
+
+	BeforeEach(func() {
+		out = NewBuffer()
+		p = NewBuiltinProgram(out)
+
+		var err error
+		f, err = NewFiles("a", "b")
+		Expect(err).NotTo(HaveOccurred())
+
+		DeferCleanup(func() {
+			Expect(f.TearDown()).To(Succeed())
+		})
+	})
+
+	expectExitStatus1 := func(err error) {
+		GinkgoHelper()
+		var exitError exec.ExitError
+		Expect(errors.As(err, &exitError)).To(BeTrue(), "expected an exec.ExitError, got %v", err)
+		Expect(exitError.ExitStatus()).To(Equal(1))
+	}
+
+	writeFile := func(dir, name, content string) {
+		GinkgoHelper()
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)).To(Succeed())
+	}
+
+	It("should print a unified diff of changed files", func() {
+		writeFile(f.From.Dir, "foo", "a: 1\nb: 2\nc: 3\n")
+		writeFile(f.To.Dir, "foo", "a: 1\nb: 3\nc: 3\n")
+
+		expectExitStatus1(p.Run(f.From.Dir, f.To.Dir))
+		Expect(string(out.Contents())).To(Equal(
+			"--- " + filepath.Join(f.From.Dir, "foo") + "\n" +
+				"+++ " + filepath.Join(f.To.Dir, "foo") + "\n" +
+				"@@ -1,3 +1,3 @@\n" +
+				" a: 1\n" +
+				"-b: 2\n" +
+				"+b: 3\n" +
+				" c: 3\n",
+		))
+	})
+
+	It("should not print anything for equal files", func() {
+		writeFile(f.From.Dir, "foo", "a: 1\n")
+		writeFile(f.To.Dir, "foo", "a: 1\n")
+
+		Expect(p.Run(f.From.Dir, f.To.Dir)).To(Succeed())
+		Expect(out.Contents()).To(BeEmpty())
+	})
+
+	It("should treat absent files as empty", func() {
+		writeFile(f.From.Dir, "bar", "a: 1\n")
+		writeFile(f.To.Dir, "foo", "b: 1")
+
+		expectExitStatus1(p.Run(f.From.Dir, f.To.Dir))
+		Expect(out).To(Say(`--- .+/bar\n`))
+		Expect(out).To(Say(`\+\+\+ .+/bar\n`))
+		Expect(out).To(Say(`@@ -1 \+0,0 @@\n`))
+		Expect(out).To(Say(`-a: 1\n`))
+		Expect(out).To(Say(`--- .+/foo\n`))
+		Expect(out).To(Say(`\+\+\+ .+/foo\n`))
+		Expect(out).To(Say(`@@ -0,0 \+1 @@\n`))
+		Expect(out).To(Say(`\+b: 1\n`))
+	})
+
+	It("should compare files directly", func() {
+		writeFile(f.From.Dir, "foo", "a: 1\n")
+		writeFile(f.To.Dir, "foo", "a: 2\n")
+
+		expectExitStatus1(p.Run(filepath.Join(f.From.Dir, "foo"), filepath.Join(f.To.Dir, "foo")))
+		Expect(out).To(Say(`-a: 1\n\+a: 2\n`))
+	})
+
+	It("should report a difference if only some files differ", func() {
+		writeFile(f.From.Dir, "bar", "a: 1\n")
+		writeFile(f.To.Dir, "bar", "a: 2\n")
+		writeFile(f.From.Dir, "foo", "a: 1\n")
+		writeFile(f.To.Dir, "foo", "a: 1\n")
+
+		expectExitStatus1(p.Run(f.From.Dir, f.To.Dir))
+		Expect(out).NotTo(Say(`foo`))
+	})
+
+	It("should fail if the first directory doesn't exist", func() {
+		Expect(p.Run(filepath.Join(f.From.Dir, "non-existing"), f.To.Dir)).To(MatchError(os.ErrNotExist))
+	})
+})
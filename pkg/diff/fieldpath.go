@@ -0,0 +1,223 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// FieldChange is a single change between two objects identified by a field path.
+type FieldChange struct {
+	// Path is the field path of the changed field, e.g., `spec.containers[name=app].image`.
+	Path string `json:"path"`
+	// From is the old value of the field. It is nil if the field was added.
+	From any `json:"from"`
+	// To is the new value of the field. It is nil if the field was removed.
+	To any `json:"to"`
+}
+
+// String returns a human-readable representation of the change, e.g.,
+// `spec.containers[name=app].image: nginx:1.25 -> nginx:1.26`.
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Path, FormatValue(c.From), FormatValue(c.To))
+}
+
+// FormatValue returns a compact string representation of a field value as contained in a FieldChange.
+func FormatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return val
+	case map[string]any, []any:
+		out, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(out)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+// FieldChanges compares the given objects field by field and returns the list of changed fields.
+// Both objects must be of the same type (e.g., *corev1.Pod). Items of lists are matched by their merge key as defined in
+// the strategic merge patch metadata of the object's type (e.g., containers by name) instead of by their index. This
+// makes the result independent of reordered list items.
+func FieldChanges(from, to runtime.Object) ([]FieldChange, error) {
+	fromContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+	if err != nil {
+		return nil, err
+	}
+	toContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta strategicpatch.LookupPatchMeta
+	if patchMeta, err := strategicpatch.NewPatchMetaFromStruct(from); err == nil {
+		meta = patchMeta
+	}
+
+	w := &fieldWalker{}
+	w.walk("", fromContent, toContent, meta)
+	return w.changes, nil
+}
+
+// PrintFieldChanges writes the given changes to the writer, one change per line.
+func PrintFieldChanges(w io.Writer, changes []FieldChange) error {
+	for _, change := range changes {
+		if _, err := fmt.Fprintln(w, change.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fieldWalker struct {
+	changes []FieldChange
+}
+
+func (w *fieldWalker) walk(path string, from, to any, meta strategicpatch.LookupPatchMeta) {
+	fromMap, fromIsMap := from.(map[string]any)
+	toMap, toIsMap := to.(map[string]any)
+	if fromIsMap && toIsMap {
+		w.walkMap(path, fromMap, toMap, meta)
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		w.changes = append(w.changes, FieldChange{Path: path, From: from, To: to})
+	}
+}
+
+func (w *fieldWalker) walkMap(path string, from, to map[string]any, meta strategicpatch.LookupPatchMeta) {
+	for _, key := range sortedKeys(from, to) {
+		fromValue, toValue := from[key], to[key]
+		childPath := joinPath(path, key)
+
+		fromList, fromIsList := fromValue.([]any)
+		toList, toIsList := toValue.([]any)
+		if fromIsList && toIsList {
+			var (
+				elemMeta strategicpatch.LookupPatchMeta
+				mergeKey string
+			)
+			if meta != nil {
+				if m, patchMeta, err := meta.LookupPatchMetadataForSlice(key); err == nil {
+					elemMeta, mergeKey = m, patchMeta.GetPatchMergeKey()
+				}
+			}
+
+			w.walkList(childPath, fromList, toList, elemMeta, mergeKey)
+			continue
+		}
+
+		var childMeta strategicpatch.LookupPatchMeta
+		if meta != nil {
+			if m, _, err := meta.LookupPatchMetadataForStruct(key); err == nil {
+				childMeta = m
+			}
+		}
+
+		w.walk(childPath, fromValue, toValue, childMeta)
+	}
+}
+
+func (w *fieldWalker) walkList(path string, from, to []any, meta strategicpatch.LookupPatchMeta, mergeKey string) {
+	if mergeKey == "" || !uniqueKeys(from, mergeKey) || !uniqueKeys(to, mergeKey) {
+		// compare items by index, merge key values are not unique in all lists, e.g., container ports with the same
+		// number but different protocols
+		for i := 0; i < max(len(from), len(to)); i++ {
+			var fromItem, toItem any
+			if i < len(from) {
+				fromItem = from[i]
+			}
+			if i < len(to) {
+				toItem = to[i]
+			}
+
+			w.walk(path+"["+strconv.Itoa(i)+"]", fromItem, toItem, meta)
+		}
+		return
+	}
+
+	// compare items by merge key, first all items in the new list (in their new order), then removed items
+	fromByKey := make(map[string]any, len(from))
+	for _, item := range from {
+		fromByKey[mergeKeyValue(item, mergeKey)] = item
+	}
+
+	seen := make(map[string]bool, len(to))
+	for _, item := range to {
+		value := mergeKeyValue(item, mergeKey)
+		seen[value] = true
+		w.walk(path+"["+mergeKey+"="+value+"]", fromByKey[value], item, meta)
+	}
+
+	for _, item := range from {
+		if value := mergeKeyValue(item, mergeKey); !seen[value] {
+			w.walk(path+"["+mergeKey+"="+value+"]", item, nil, meta)
+		}
+	}
+}
+
+// uniqueKeys returns true if all items in the list have the given key and the values of the key are unique.
+func uniqueKeys(list []any, key string) bool {
+	values := make(map[string]bool, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return false
+		}
+		if _, ok := m[key]; !ok {
+			return false
+		}
+
+		value := mergeKeyValue(item, key)
+		if values[value] {
+			return false
+		}
+		values[value] = true
+	}
+	return true
+}
+
+func mergeKeyValue(item any, key string) string {
+	return FormatValue(item.(map[string]any)[key])
+}
+
+func sortedKeys(maps ...map[string]any) []string {
+	set := make(map[string]struct{})
+	for _, m := range maps {
+		for k := range m {
+			set[k] = struct{}{}
+		}
+	}
+
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// joinPath appends the given key to the path. Keys that contain special characters (e.g., annotation keys) are quoted.
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, "./[]= ") {
+		return path + "['" + key + "']"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
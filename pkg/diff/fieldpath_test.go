@@ -0,0 +1,122 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("FieldChanges", func() {
+	var from, to *corev1.Pod
+
+	BeforeEach(func() {
+		from = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "test"},
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "app",
+						Image: "nginx:1.25",
+						Env: []corev1.EnvVar{
+							{Name: "FOO", Value: "foo"},
+							{Name: "BAR", Value: "bar"},
+						},
+					},
+					{
+						Name:  "sidecar",
+						Image: "sidecar:1",
+						Args:  []string{"--foo", "--bar"},
+					},
+				},
+			},
+		}
+		to = from.DeepCopy()
+	})
+
+	It("should return no changes for equal objects", func() {
+		Expect(FieldChanges(from, to)).To(BeEmpty())
+	})
+
+	It("should match list items by merge key", func() {
+		// reorder containers and env vars
+		to.Spec.Containers[0], to.Spec.Containers[1] = to.Spec.Containers[1], to.Spec.Containers[0]
+		to.Spec.Containers[1].Env[0], to.Spec.Containers[1].Env[1] = to.Spec.Containers[1].Env[1], to.Spec.Containers[1].Env[0]
+		to.Spec.Containers[1].Image = "nginx:1.26"
+		to.Spec.Containers[1].Env[0].Value = "baz"
+
+		Expect(FieldChanges(from, to)).To(HaveExactElements(
+			FieldChange{Path: "spec.containers[name=app].env[name=BAR].value", From: "bar", To: "baz"},
+			FieldChange{Path: "spec.containers[name=app].image", From: "nginx:1.25", To: "nginx:1.26"},
+		))
+	})
+
+	It("should report added and removed list items", func() {
+		to.Spec.Containers = to.Spec.Containers[:1]
+		to.Spec.Containers[0].Env = append(to.Spec.Containers[0].Env, corev1.EnvVar{Name: "NEW", Value: "new"})
+
+		Expect(FieldChanges(from, to)).To(HaveExactElements(
+			FieldChange{Path: "spec.containers[name=app].env[name=NEW]", From: nil, To: map[string]any{"name": "NEW", "value": "new"}},
+			FieldChange{Path: "spec.containers[name=sidecar]", From: map[string]any{
+				"name": "sidecar", "image": "sidecar:1", "args": []any{"--foo", "--bar"}, "resources": map[string]any{},
+			}, To: nil},
+		))
+	})
+
+	It("should compare lists without merge key by index", func() {
+		to.Spec.Containers[1].Args = []string{"--bar"}
+
+		Expect(FieldChanges(from, to)).To(HaveExactElements(
+			FieldChange{Path: "spec.containers[name=sidecar].args[0]", From: "--foo", To: "--bar"},
+			FieldChange{Path: "spec.containers[name=sidecar].args[1]", From: "--bar", To: nil},
+		))
+	})
+
+	It("should compare lists with duplicate merge keys by index", func() {
+		// the merge key of container ports is containerPort, but the same port can be used with different protocols
+		from.Spec.Containers[0].Ports = []corev1.ContainerPort{
+			{Name: "dns-tcp", ContainerPort: 53, Protocol: corev1.ProtocolTCP},
+			{Name: "dns-udp", ContainerPort: 53, Protocol: corev1.ProtocolUDP},
+		}
+		to = from.DeepCopy()
+		to.Spec.Containers[0].Ports[1].Name = "dns"
+
+		Expect(FieldChanges(from, to)).To(HaveExactElements(
+			FieldChange{Path: "spec.containers[name=app].ports[1].name", From: "dns-udp", To: "dns"},
+		))
+	})
+
+	It("should quote keys with special characters", func() {
+		to.Annotations["kubectl.kubernetes.io/restartedAt"] = "2024-01-02T00:00:00Z"
+		to.Labels["app"] = "other"
+
+		Expect(FieldChanges(from, to)).To(HaveExactElements(
+			FieldChange{Path: "metadata.annotations['kubectl.kubernetes.io/restartedAt']", From: "2024-01-01T00:00:00Z", To: "2024-01-02T00:00:00Z"},
+			FieldChange{Path: "metadata.labels.app", From: "test", To: "other"},
+		))
+	})
+})
+
+var _ = Describe("PrintFieldChanges", func() {
+	It("should print one change per line", func() {
+		out := NewBuffer()
+		Expect(PrintFieldChanges(out, []FieldChange{
+			{Path: "spec.containers[name=app].image", From: "nginx:1.25", To: "nginx:1.26"},
+			{Path: "spec.containers[name=app].env[name=FOO]", From: nil, To: map[string]any{"name": "FOO", "value": "foo"}},
+			{Path: "spec.replicas", From: int64(1), To: nil},
+		})).To(Succeed())
+
+		Expect(string(out.Contents())).To(Equal(`spec.containers[name=app].image: nginx:1.25 -> nginx:1.26
+spec.containers[name=app].env[name=FOO]: <none> -> {"name":"FOO","value":"foo"}
+spec.replicas: 1 -> <none>
+`))
+	})
+})
@@ -1,16 +1,55 @@
 package diff
 
 import (
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/utils/exec"
 
 	"github.com/timebertt/kubectl-revisions/pkg/runutil"
 )
 
+// Compare prints the given objects using the given printer to files with the given name in two temporary directories
+// (prefixed with fromDir and toDir) and runs the given Program against the directories. Exit status 1 of the Program
+// signals a diff and is not returned as an error.
+func Compare(program Program, p printers.ResourcePrinter, fromDir, toDir, fileName string, from, to runtime.Object) (err error) {
+	files, err := NewFiles(fromDir, toDir)
+	if err != nil {
+		return err
+	}
+	defer runutil.CaptureError(&err, files.TearDown)
+
+	// the yaml printer adds a `---` separator starting from the second call to PrintObj
+	// call it once to /dev/null to have the separator in both files to compare
+	if err = p.PrintObj(&corev1.Namespace{}, io.Discard); err != nil {
+		return err
+	}
+
+	if err := files.From.Print(fileName, from, p); err != nil {
+		return err
+	}
+	if err := files.To.Print(fileName, to, p); err != nil {
+		return err
+	}
+
+	if err := program.Run(files.From.Dir, files.To.Dir); err != nil {
+		// don't propagate exit status 1 (signaling a diff) upwards and exit cleanly instead
+		var exitError exec.ExitError
+		if errors.As(err, &exitError) && exitError.ExitStatus() <= 1 {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
 // Files is a compound handle for multiple directories and files that shall be compared using a diff program.
 // This is similar to how `kubectl diff` works. This should behave similarly (e.g., create files in two different
 // directories) as some external diff tools might have some heuristic detections in places, e.g., see dyff:
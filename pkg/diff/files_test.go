@@ -1,6 +1,8 @@
 package diff_test
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -9,6 +11,8 @@ import (
 	. "github.com/onsi/gomega/gbytes"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/utils/exec"
 
 	. "github.com/timebertt/kubectl-revisions/pkg/diff"
 )
@@ -69,3 +73,73 @@ var _ = Describe("diff files", func() {
 		})
 	})
 })
+
+var _ = Describe("Compare", func() {
+	var (
+		program     *fakeProgram
+		yamlPrinter printers.ResourcePrinter
+		from        *corev1.Namespace
+		to          *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		program = &fakeProgram{}
+		// the type setter is required for printing the separator object without apiVersion and kind
+		yamlPrinter = printers.NewTypeSetter(scheme.Scheme).ToPrinter(&printers.YAMLPrinter{})
+
+		from = &corev1.Namespace{}
+		from.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		from.SetName("foo")
+		to = from.DeepCopy()
+		to.SetName("bar")
+	})
+
+	It("should print both objects and run the program against the temp dirs", func() {
+		Expect(Compare(program, yamlPrinter, "a", "b", "ns.yaml", from, to)).To(Succeed())
+
+		Expect(filepath.Base(program.from)).To(HavePrefix("a-"))
+		Expect(filepath.Base(program.to)).To(HavePrefix("b-"))
+		Expect(program.fromContent).To(ContainSubstring("---\n"))
+		Expect(program.fromContent).To(ContainSubstring("name: foo"))
+		Expect(program.toContent).To(ContainSubstring("---\n"))
+		Expect(program.toContent).To(ContainSubstring("name: bar"))
+
+		// temp dirs are removed afterward
+		Expect(program.from).NotTo(BeADirectory())
+		Expect(program.to).NotTo(BeADirectory())
+	})
+
+	It("should not return exit status 1 signaling a diff", func() {
+		program.err = exec.CodeExitError{Err: fmt.Errorf("exit status 1"), Code: 1}
+		Expect(Compare(program, yamlPrinter, "a", "b", "ns.yaml", from, to)).To(Succeed())
+	})
+
+	It("should not return the difference reported by the builtin program", func() {
+		Expect(Compare(NewBuiltinProgram(io.Discard), yamlPrinter, "a", "b", "ns.yaml", from, to)).To(Succeed())
+	})
+
+	It("should return other errors", func() {
+		program.err = exec.CodeExitError{Err: fmt.Errorf("exit status 2"), Code: 2}
+		Expect(Compare(program, yamlPrinter, "a", "b", "ns.yaml", from, to)).To(MatchError("exit status 2"))
+	})
+})
+
+type fakeProgram struct {
+	err error
+
+	from, to               string
+	fromContent, toContent string
+}
+
+func (p *fakeProgram) Run(a, b string) error {
+	p.from, p.to = a, b
+	p.fromContent, p.toContent = readFile(filepath.Join(a, "ns.yaml")), readFile(filepath.Join(b, "ns.yaml"))
+	return p.err
+}
+
+func readFile(name string) string {
+	// nolint:gosec // this is test code
+	data, err := os.ReadFile(name)
+	Expect(err).NotTo(HaveOccurred())
+	return string(data)
+}
@@ -0,0 +1,258 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Ignore configures fields that are removed from both sides before comparing revisions, e.g., annotations that change
+// with every rollout but are irrelevant for the comparison.
+type Ignore struct {
+	// Paths is a list of field paths to remove in the same syntax as printed by FieldChange.String, e.g.,
+	// `spec.containers[name=app].env[name=BUILD_ID]` or `metadata.annotations['ci.example.com/build-id']`.
+	// Simple JSONPath expressions like `{.metadata.labels.build}` are also accepted. `[*]` matches all list items.
+	Paths []string `json:"paths,omitempty"`
+	// Annotations is a list of annotation keys to remove from all object metadata, including the pod template's.
+	Annotations []string `json:"annotations,omitempty"`
+	// Labels is a list of label keys to remove from all object metadata, including the pod template's.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// IsEmpty returns true if no fields are ignored.
+func (i Ignore) IsEmpty() bool {
+	return len(i.Paths) == 0 && len(i.Annotations) == 0 && len(i.Labels) == 0
+}
+
+// Validate validates the configured field paths.
+func (i Ignore) Validate() error {
+	for _, path := range i.Paths {
+		if _, err := parseFieldPath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the given object with all ignored fields removed. The returned object has the same type as
+// the given object.
+func (i Ignore) Apply(obj client.Object) (client.Object, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range i.Paths {
+		segments, err := parseFieldPath(path)
+		if err != nil {
+			return nil, err
+		}
+		content = removeField(content, segments).(map[string]any)
+	}
+
+	if len(i.Annotations) > 0 || len(i.Labels) > 0 {
+		removeMetadataKeys(content, i.Annotations, i.Labels)
+	}
+
+	out := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// removeMetadataKeys removes the given annotation and label keys from all metadata fields in the given value
+// recursively, e.g., from metadata and spec.template.metadata.
+func removeMetadataKeys(value any, annotations, labels []string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if metadata, ok := child.(map[string]any); ok && key == "metadata" {
+				removeKeys(metadata, "annotations", annotations)
+				removeKeys(metadata, "labels", labels)
+			}
+			removeMetadataKeys(child, annotations, labels)
+		}
+	case []any:
+		for _, item := range v {
+			removeMetadataKeys(item, annotations, labels)
+		}
+	}
+}
+
+func removeKeys(metadata map[string]any, field string, keys []string) {
+	m, ok := metadata[field].(map[string]any)
+	if !ok {
+		return
+	}
+
+	for _, key := range keys {
+		delete(m, key)
+	}
+	if len(m) == 0 {
+		delete(metadata, field)
+	}
+}
+
+type segmentType int
+
+const (
+	segmentKey segmentType = iota
+	segmentIndex
+	segmentMatch
+	segmentWildcard
+)
+
+// segment is a single element of a parsed field path.
+type segment struct {
+	typ   segmentType
+	key   string
+	value string
+	index int
+}
+
+// parseFieldPath parses a field path as printed by FieldChange.String, e.g., `spec.containers[name=app].image`.
+func parseFieldPath(path string) ([]segment, error) {
+	p := strings.TrimSpace(path)
+	// accept simple JSONPath expressions
+	p = strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}")
+	p = strings.TrimPrefix(p, "$")
+	p = strings.TrimPrefix(p, ".")
+
+	if p == "" {
+		return nil, fmt.Errorf("invalid field path %q: path must not be empty", path)
+	}
+
+	var segments []segment
+	for len(p) > 0 {
+		switch {
+		case p[0] == '.':
+			p = p[1:]
+			if p == "" || p[0] == '.' || p[0] == '[' {
+				return nil, fmt.Errorf("invalid field path %q: empty field name", path)
+			}
+		case strings.HasPrefix(p, "['"):
+			end := strings.Index(p, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: missing closing \"']\"", path)
+			}
+			segments = append(segments, segment{typ: segmentKey, key: p[2:end]})
+			p = p[end+2:]
+		case p[0] == '[':
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: missing closing \"]\"", path)
+			}
+			s, err := parseListSegment(p[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid field path %q: %w", path, err)
+			}
+			segments = append(segments, s)
+			p = p[end+1:]
+		default:
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			segments = append(segments, segment{typ: segmentKey, key: p[:end]})
+			p = p[end:]
+		}
+	}
+
+	return segments, nil
+}
+
+func parseListSegment(s string) (segment, error) {
+	if s == "*" {
+		return segment{typ: segmentWildcard}, nil
+	}
+
+	if key, value, ok := strings.Cut(s, "="); ok {
+		if key == "" {
+			return segment{}, fmt.Errorf("empty merge key in %q", "["+s+"]")
+		}
+		return segment{typ: segmentMatch, key: key, value: value}, nil
+	}
+
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return segment{}, fmt.Errorf("expected an index, a merge key (e.g., [name=app]), or [*], but got %q", "["+s+"]")
+	}
+	return segment{typ: segmentIndex, index: index}, nil
+}
+
+// removeField removes the field at the given path from the given value and returns the resulting value. Missing fields
+// are ignored.
+func removeField(value any, segments []segment) any {
+	if len(segments) == 0 {
+		return value
+	}
+	s, rest := segments[0], segments[1:]
+
+	switch s.typ {
+	case segmentKey:
+		m, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		child, ok := m[s.key]
+		if !ok {
+			return value
+		}
+		if len(rest) == 0 {
+			delete(m, s.key)
+		} else {
+			m[s.key] = removeField(child, rest)
+		}
+		return m
+	case segmentWildcard:
+		if m, ok := value.(map[string]any); ok {
+			for key, child := range m {
+				if len(rest) == 0 {
+					delete(m, key)
+				} else {
+					m[key] = removeField(child, rest)
+				}
+			}
+			return m
+		}
+	}
+
+	list, ok := value.([]any)
+	if !ok {
+		return value
+	}
+
+	out := make([]any, 0, len(list))
+	for i, item := range list {
+		if !s.matches(i, item) {
+			out = append(out, item)
+			continue
+		}
+		if len(rest) > 0 {
+			out = append(out, removeField(item, rest))
+		}
+	}
+	return out
+}
+
+func (s segment) matches(i int, item any) bool {
+	switch s.typ {
+	case segmentWildcard:
+		return true
+	case segmentIndex:
+		return i == s.index
+	case segmentMatch:
+		m, ok := item.(map[string]any)
+		if !ok {
+			return false
+		}
+		v, ok := m[s.key]
+		return ok && FormatValue(v) == s.value
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("Ignore", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "test", "build": "42"},
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "app",
+						Image: "nginx:1.25",
+						Env: []corev1.EnvVar{
+							{Name: "FOO", Value: "foo"},
+							{Name: "BUILD_ID", Value: "42"},
+						},
+					},
+					{Name: "sidecar", Image: "envoy:1.0"},
+				},
+			},
+		}
+	})
+
+	Describe("#Apply", func() {
+		It("should not modify the given object", func() {
+			original := pod.DeepCopy()
+			Expect(Ignore{Paths: []string{"spec"}, Labels: []string{"app"}}.Apply(pod)).To(BeAssignableToTypeOf(&corev1.Pod{}))
+			Expect(pod).To(Equal(original))
+		})
+
+		It("should remove the given field paths", func() {
+			result, err := Ignore{Paths: []string{
+				"spec.containers[name=app].env[name=BUILD_ID]",
+				"metadata.annotations['kubectl.kubernetes.io/restartedAt']",
+				"{.metadata.labels.build}",
+				"spec.containers[1].image",
+			}}.Apply(pod)
+			Expect(err).NotTo(HaveOccurred())
+
+			expected := pod.DeepCopy()
+			expected.Annotations = map[string]string{}
+			expected.Labels = map[string]string{"app": "test"}
+			expected.Spec.Containers[0].Env = expected.Spec.Containers[0].Env[:1]
+			expected.Spec.Containers[1].Image = ""
+			Expect(result).To(Equal(expected))
+		})
+
+		It("should remove fields of all list items", func() {
+			result, err := Ignore{Paths: []string{"spec.containers[*].image"}}.Apply(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.(*corev1.Pod).Spec.Containers).To(HaveEach(HaveField("Image", BeEmpty())))
+		})
+
+		It("should ignore missing fields", func() {
+			result, err := Ignore{Paths: []string{"spec.containers[name=foo].image", "status.foo", "spec.containers[5]"}}.Apply(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(pod))
+		})
+
+		It("should remove annotations and labels from all metadata", func() {
+			replicaSet := &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "test", appsv1.DefaultDeploymentUniqueLabelKey: "abc"},
+					Annotations: map[string]string{"kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z"},
+				},
+				Spec: appsv1.ReplicaSetSpec{
+					Template: corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta},
+				},
+			}
+			replicaSet.Spec.Template.Labels = map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "abc"}
+
+			result, err := Ignore{
+				Annotations: []string{"kubectl.kubernetes.io/restartedAt"},
+				Labels:      []string{appsv1.DefaultDeploymentUniqueLabelKey},
+			}.Apply(replicaSet)
+			Expect(err).NotTo(HaveOccurred())
+
+			resultReplicaSet := result.(*appsv1.ReplicaSet)
+			Expect(resultReplicaSet.Labels).To(Equal(map[string]string{"app": "test"}))
+			Expect(resultReplicaSet.Annotations).To(BeEmpty())
+			Expect(resultReplicaSet.Spec.Template.Labels).To(BeEmpty())
+			Expect(resultReplicaSet.Spec.Template.Annotations).To(BeEmpty())
+		})
+
+		It("should handle unstructured objects", func() {
+			obj := &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "Rollout",
+				"metadata":   map[string]any{"name": "test", "labels": map[string]any{"build": "42"}},
+			}}
+
+			result, err := Ignore{Labels: []string{"build"}}.Apply(obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.(*unstructured.Unstructured).Object).To(Equal(map[string]any{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "Rollout",
+				"metadata":   map[string]any{"name": "test"},
+			}))
+		})
+
+		It("should fail for invalid field paths", func() {
+			Expect(Ignore{Paths: []string{"spec..image"}}.Apply(pod)).Error().To(MatchError(ContainSubstring("empty field name")))
+		})
+	})
+
+	Describe("#Validate", func() {
+		It("should accept valid field paths", func() {
+			Expect(Ignore{Paths: []string{"spec.containers[name=app].image", "metadata.annotations['a.b/c']", "spec.containers[0]"}}.Validate()).To(Succeed())
+		})
+
+		It("should reject invalid field paths", func() {
+			Expect(Ignore{Paths: []string{""}}.Validate()).To(MatchError(`invalid field path "": path must not be empty`))
+			Expect(Ignore{Paths: []string{"spec[foo"}}.Validate()).To(MatchError(ContainSubstring(`missing closing "]"`)))
+			Expect(Ignore{Paths: []string{"spec.containers[-1]"}}.Validate()).To(MatchError(ContainSubstring("expected an index")))
+			Expect(Ignore{Paths: []string{"spec.containers[=app]"}}.Validate()).To(MatchError(ContainSubstring("empty merge key")))
+		})
+	})
+})
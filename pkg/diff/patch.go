@@ -0,0 +1,164 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// PatchType is a type of patch that can be created between two objects, see CreatePatch.
+type PatchType string
+
+const (
+	// PatchTypeJSON is a JSON patch as defined in RFC 6902.
+	PatchTypeJSON PatchType = "json-patch"
+	// PatchTypeMerge is a JSON merge patch as defined in RFC 7386.
+	PatchTypeMerge PatchType = "merge-patch"
+	// PatchTypeStrategicMerge is a Kubernetes strategic merge patch. It is only supported for built-in types.
+	PatchTypeStrategicMerge PatchType = "strategic-merge-patch"
+)
+
+// PatchTypes is the list of all supported patch types.
+var PatchTypes = []PatchType{PatchTypeJSON, PatchTypeMerge, PatchTypeStrategicMerge}
+
+// CreatePatch creates a patch of the given type that transforms the from object into the to object. Both objects must
+// be of the same type (e.g., *corev1.Pod). The returned patch is indented JSON.
+func CreatePatch(patchType PatchType, from, to runtime.Object) ([]byte, error) {
+	fromJSON, err := json.Marshal(from)
+	if err != nil {
+		return nil, err
+	}
+	toJSON, err := json.Marshal(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch []byte
+	switch patchType {
+	case PatchTypeJSON:
+		patch, err = createJSONPatch(fromJSON, toJSON)
+	case PatchTypeMerge:
+		patch, err = jsonpatch.CreateMergePatch(fromJSON, toJSON)
+	case PatchTypeStrategicMerge:
+		if _, ok := from.(*unstructured.Unstructured); ok {
+			return nil, fmt.Errorf("%s is not supported for custom resources, use %s instead", patchType, PatchTypeMerge)
+		}
+		patch, err = strategicpatch.CreateTwoWayMergePatch(fromJSON, toJSON, from)
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patchType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s: %w", patchType, err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := json.Indent(out, patch, "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// jsonPatchOperation is a single operation of a JSON patch as defined in RFC 6902.
+type jsonPatchOperation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+// MarshalJSON implements json.Marshaler. The value is omitted for remove operations only, as it might be null for add
+// and replace operations.
+func (o jsonPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+func createJSONPatch(from, to []byte) ([]byte, error) {
+	var fromValue, toValue any
+	if err := json.Unmarshal(from, &fromValue); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &toValue); err != nil {
+		return nil, err
+	}
+
+	operations := jsonPatchOperations("", fromValue, toValue)
+	if operations == nil {
+		operations = []jsonPatchOperation{}
+	}
+	return json.Marshal(operations)
+}
+
+// jsonPatchOperations returns the operations for transforming from into to at the given JSON pointer. Lists are
+// compared by index: the items in both lists are patched, and the remaining items are added or removed.
+func jsonPatchOperations(path string, from, to any) []jsonPatchOperation {
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	switch fromValue := from.(type) {
+	case map[string]any:
+		toValue, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+
+		var operations []jsonPatchOperation
+		for _, key := range sortedKeys(fromValue, toValue) {
+			childPath := path + "/" + escapeJSONPointer(key)
+			fromChild, inFrom := fromValue[key]
+			toChild, inTo := toValue[key]
+
+			switch {
+			case !inTo:
+				operations = append(operations, jsonPatchOperation{Op: "remove", Path: childPath})
+			case !inFrom:
+				operations = append(operations, jsonPatchOperation{Op: "add", Path: childPath, Value: toChild})
+			default:
+				operations = append(operations, jsonPatchOperations(childPath, fromChild, toChild)...)
+			}
+		}
+		return operations
+	case []any:
+		toValue, ok := to.([]any)
+		if !ok {
+			break
+		}
+
+		var operations []jsonPatchOperation
+		for i := 0; i < min(len(fromValue), len(toValue)); i++ {
+			operations = append(operations, jsonPatchOperations(path+"/"+strconv.Itoa(i), fromValue[i], toValue[i])...)
+		}
+		for i := len(fromValue); i < len(toValue); i++ {
+			operations = append(operations, jsonPatchOperation{Op: "add", Path: path + "/-", Value: toValue[i]})
+		}
+		// remove items from the end so that the indices of the remaining items stay valid
+		for i := len(fromValue) - 1; i >= len(toValue); i-- {
+			operations = append(operations, jsonPatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		return operations
+	}
+
+	return []jsonPatchOperation{{Op: "replace", Path: path, Value: to}}
+}
+
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
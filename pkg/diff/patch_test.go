@@ -0,0 +1,155 @@
+package diff_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("CreatePatch", func() {
+	var from, to *corev1.Pod
+
+	BeforeEach(func() {
+		from = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"app": "test"},
+				Annotations: map[string]string{"example.com/build-id": "1"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app", Image: "nginx:1.25", Args: []string{"--foo", "--bar"}},
+					{Name: "sidecar", Image: "envoy:1.0"},
+				},
+			},
+		}
+
+		to = from.DeepCopy()
+		to.Annotations = nil
+		to.Labels["tier"] = "frontend"
+		to.Spec.Containers[0].Image = "nginx:1.26"
+		to.Spec.Containers[0].Args = []string{"--foo"}
+		to.Spec.Containers = append(to.Spec.Containers, corev1.Container{Name: "logger", Image: "fluent-bit:3"})
+	})
+
+	// apply applies the given patch to the from object and returns the result as JSON.
+	apply := func(patchType PatchType, patch []byte) []byte {
+		fromJSON, err := json.Marshal(from)
+		Expect(err).NotTo(HaveOccurred())
+
+		var result []byte
+		switch patchType {
+		case PatchTypeJSON:
+			p, err := jsonpatch.DecodePatch(patch)
+			Expect(err).NotTo(HaveOccurred())
+			result, err = p.Apply(fromJSON)
+			Expect(err).NotTo(HaveOccurred())
+		case PatchTypeMerge:
+			result, err = jsonpatch.MergePatch(fromJSON, patch)
+			Expect(err).NotTo(HaveOccurred())
+		case PatchTypeStrategicMerge:
+			result, err = strategicpatch.StrategicMergePatch(fromJSON, patch, &corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		return result
+	}
+
+	DescribeTable("should create a patch that transforms from into to",
+		func(patchType PatchType) {
+			patch, err := CreatePatch(patchType, from, to)
+			Expect(err).NotTo(HaveOccurred())
+
+			toJSON, err := json.Marshal(to)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apply(patchType, patch)).To(MatchJSON(toJSON))
+		},
+		Entry("json-patch", PatchTypeJSON),
+		Entry("merge-patch", PatchTypeMerge),
+		Entry("strategic-merge-patch", PatchTypeStrategicMerge),
+	)
+
+	Describe("json-patch round trip", func() {
+		BeforeEach(func() {
+			from.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}, {Name: "C", Value: "3"}}
+			to = from.DeepCopy()
+		})
+
+		DescribeTable("should create a patch that transforms from into to when applied",
+			func(mutate func(pod *corev1.Pod)) {
+				mutate(to)
+
+				patch, err := CreatePatch(PatchTypeJSON, from, to)
+				Expect(err).NotTo(HaveOccurred())
+
+				toJSON, err := json.Marshal(to)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(apply(PatchTypeJSON, patch)).To(MatchJSON(toJSON))
+			},
+			Entry("insert at the front of a list", func(pod *corev1.Pod) {
+				pod.Spec.Containers = append([]corev1.Container{{Name: "init", Image: "busybox"}}, pod.Spec.Containers...)
+			}),
+			Entry("insert in the middle of a list", func(pod *corev1.Pod) {
+				env := pod.Spec.Containers[0].Env
+				pod.Spec.Containers[0].Env = append([]corev1.EnvVar{env[0], {Name: "X", Value: "x"}}, env[1:]...)
+			}),
+			Entry("delete from the front of a list", func(pod *corev1.Pod) {
+				pod.Spec.Containers = pod.Spec.Containers[1:]
+			}),
+			Entry("delete from the middle of a list", func(pod *corev1.Pod) {
+				env := pod.Spec.Containers[0].Env
+				pod.Spec.Containers[0].Env = []corev1.EnvVar{env[0], env[2]}
+			}),
+			Entry("delete all items of a list", func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].Env = nil
+			}),
+			Entry("reorder a list", func(pod *corev1.Pod) {
+				containers := pod.Spec.Containers
+				pod.Spec.Containers = []corev1.Container{containers[1], containers[0]}
+			}),
+			Entry("reorder and modify a list", func(pod *corev1.Pod) {
+				env := pod.Spec.Containers[0].Env
+				pod.Spec.Containers[0].Env = []corev1.EnvVar{env[2], {Name: "A", Value: "changed"}}
+			}),
+			Entry("insert, delete, and modify in nested lists", func(pod *corev1.Pod) {
+				pod.Spec.Containers[1].Args = []string{"--x"}
+				pod.Spec.Containers[0].Args = []string{"--bar", "--baz", "--foo"}
+				pod.Spec.Containers = append([]corev1.Container{{Name: "init", Env: []corev1.EnvVar{{Name: "D"}}}}, pod.Spec.Containers...)
+			}),
+			Entry("keys that need escaping", func(pod *corev1.Pod) {
+				pod.Annotations = map[string]string{"example.com/a~b": "1", "example.com/build-id": "2"}
+			}),
+		)
+	})
+
+	It("should escape JSON pointers in json-patch", func() {
+		Expect(CreatePatch(PatchTypeJSON, from, to)).To(ContainSubstring(`"path": "/metadata/annotations"`))
+
+		to = from.DeepCopy()
+		to.Annotations["example.com/build-id"] = "2"
+		Expect(CreatePatch(PatchTypeJSON, from, to)).To(MatchJSON(`[
+  {"op": "replace", "path": "/metadata/annotations/example.com~1build-id", "value": "2"}
+]`))
+	})
+
+	It("should create empty patches for equal objects", func() {
+		Expect(CreatePatch(PatchTypeJSON, from, from)).To(MatchJSON(`[]`))
+		Expect(CreatePatch(PatchTypeMerge, from, from)).To(MatchJSON(`{}`))
+		Expect(CreatePatch(PatchTypeStrategicMerge, from, from)).To(MatchJSON(`{}`))
+	})
+
+	It("should fail creating a strategic merge patch for unstructured objects", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{"kind": "Rollout"}}
+		Expect(CreatePatch(PatchTypeStrategicMerge, obj, obj)).Error().To(MatchError(ContainSubstring("not supported")))
+	})
+
+	It("should fail for unsupported patch types", func() {
+		Expect(CreatePatch("foo", from, to)).Error().To(MatchError(`unsupported patch type "foo"`))
+	})
+})
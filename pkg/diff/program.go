@@ -1,14 +1,21 @@
 package diff
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	kubectldiff "k8s.io/kubectl/pkg/cmd/diff"
-	"k8s.io/utils/exec"
+	utilsexec "k8s.io/utils/exec"
 )
 
 // Program is a diff program that compares two files.
 type Program interface {
-	// Run executes the diff program to compare the given files.
+	// Run executes the diff program to compare the given files. Like `diff`, it returns an exec.ExitError
+	// (k8s.io/utils/exec) with exit status 1 if the files differ and an error with a higher exit status or any other error
+	// if comparing the files failed. See Compare for handling the result.
 	Run(a, b string) error
 }
 
@@ -16,7 +23,49 @@ type Program interface {
 // variable. It falls back to `diff -u -N` if the env var is unset.
 func NewProgram(streams genericiooptions.IOStreams) Program {
 	return &kubectldiff.DiffProgram{
-		Exec:      exec.New(),
+		Exec:      utilsexec.New(),
 		IOStreams: streams,
 	}
 }
+
+// Engine selects a Program implementation.
+type Engine string
+
+const (
+	// EngineAuto selects EngineExternal if the external diff program can be found in PATH, and EngineBuiltin otherwise.
+	EngineAuto Engine = "auto"
+	// EngineBuiltin selects the BuiltinProgram.
+	EngineBuiltin Engine = "builtin"
+	// EngineExternal selects the external diff program, see NewProgram.
+	EngineExternal Engine = "external"
+)
+
+// Engines is a list of all supported engines.
+var Engines = []Engine{EngineAuto, EngineBuiltin, EngineExternal}
+
+// NewProgramForEngine returns the Program implementation for the given Engine.
+func NewProgramForEngine(engine Engine, streams genericiooptions.IOStreams) (Program, error) {
+	switch engine {
+	case EngineAuto:
+		if _, err := exec.LookPath(ExternalCommand()); err != nil {
+			return NewBuiltinProgram(streams.Out), nil
+		}
+		return NewProgram(streams), nil
+	case EngineBuiltin:
+		return NewBuiltinProgram(streams.Out), nil
+	case EngineExternal:
+		return NewProgram(streams), nil
+	}
+
+	return nil, fmt.Errorf("unsupported diff engine %q, must be one of %q", engine, Engines)
+}
+
+// ExternalCommand returns the name of the external diff command that is executed by the Program returned by
+// NewProgram, i.e., the first element of the KUBECTL_EXTERNAL_DIFF environment variable or `diff` if the env var is
+// unset.
+func ExternalCommand() string {
+	if envDiff := os.Getenv("KUBECTL_EXTERNAL_DIFF"); envDiff != "" {
+		return strings.Split(envDiff, " ")[0]
+	}
+	return "diff"
+}
@@ -0,0 +1,55 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	kubectldiff "k8s.io/kubectl/pkg/cmd/diff"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("NewProgramForEngine", func() {
+	var streams genericiooptions.IOStreams
+
+	BeforeEach(func() {
+		streams, _, _, _ = genericiooptions.NewTestIOStreams()
+	})
+
+	It("should return the builtin program", func() {
+		Expect(NewProgramForEngine(EngineBuiltin, streams)).To(BeAssignableToTypeOf(&BuiltinProgram{}))
+	})
+
+	It("should return the external program", func() {
+		Expect(NewProgramForEngine(EngineExternal, streams)).To(BeAssignableToTypeOf(&kubectldiff.DiffProgram{}))
+	})
+
+	Context("auto", func() {
+		It("should return the external program if it can be found", func() {
+			GinkgoT().Setenv("KUBECTL_EXTERNAL_DIFF", "ls -l")
+			Expect(NewProgramForEngine(EngineAuto, streams)).To(BeAssignableToTypeOf(&kubectldiff.DiffProgram{}))
+		})
+
+		It("should fall back to the builtin program if the external program cannot be found", func() {
+			GinkgoT().Setenv("KUBECTL_EXTERNAL_DIFF", "non-existing-diff -u")
+			Expect(NewProgramForEngine(EngineAuto, streams)).To(BeAssignableToTypeOf(&BuiltinProgram{}))
+		})
+	})
+
+	It("should fail for unsupported engines", func() {
+		_, err := NewProgramForEngine("foo", streams)
+		Expect(err).To(MatchError(ContainSubstring(`unsupported diff engine "foo"`)))
+	})
+})
+
+var _ = Describe("ExternalCommand", func() {
+	It("should return diff by default", func() {
+		GinkgoT().Setenv("KUBECTL_EXTERNAL_DIFF", "")
+		Expect(ExternalCommand()).To(Equal("diff"))
+	})
+
+	It("should return the command from KUBECTL_EXTERNAL_DIFF", func() {
+		GinkgoT().Setenv("KUBECTL_EXTERNAL_DIFF", "dyff between --omit-header")
+		Expect(ExternalCommand()).To(Equal("dyff"))
+	})
+})
@@ -0,0 +1,128 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FieldPathPattern matches field paths as contained in FieldChange, see ParseFieldPathPattern.
+type FieldPathPattern struct {
+	pattern  string
+	segments []segment
+}
+
+// ParseFieldPathPattern parses a pattern for matching field paths in the same syntax as accepted by Ignore.Paths, e.g.,
+// `spec.containers[*].resources`.
+func ParseFieldPathPattern(pattern string) (FieldPathPattern, error) {
+	segments, err := parseFieldPath(pattern)
+	if err != nil {
+		return FieldPathPattern{}, err
+	}
+	return FieldPathPattern{pattern: pattern, segments: segments}, nil
+}
+
+func (p FieldPathPattern) String() string {
+	return p.pattern
+}
+
+// Matches returns true if the given field path is matched by the pattern. This includes fields nested in the matched
+// field (e.g., spec.containers[name=app].resources.limits.cpu for spec.containers[*].resources) as well as parents
+// of the matched field (e.g., spec.containers[name=app] if the whole container was added or removed).
+func (p FieldPathPattern) Matches(path string) bool {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return false
+	}
+
+	for i := 0; i < min(len(p.segments), len(segments)); i++ {
+		if !p.segments[i].matchesSegment(segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s segment) matchesSegment(other segment) bool {
+	switch s.typ {
+	case segmentWildcard:
+		return true
+	case segmentKey:
+		return other.typ == segmentKey && other.key == s.key
+	case segmentIndex:
+		return other.typ == segmentIndex && other.index == s.index
+	case segmentMatch:
+		return other.typ == segmentMatch && other.key == s.key && other.value == s.value
+	}
+	return false
+}
+
+// ChangeReport is a machine-readable summary of the differences between revisions, e.g., for use in scripts and CI
+// gates.
+type ChangeReport struct {
+	// Failed is true if any of the comparisons contains relevant changes.
+	Failed bool `json:"failed"`
+	// Comparisons contains the result of every compared pair of revisions.
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+// Comparison is the result of comparing two revisions.
+type Comparison struct {
+	// Object is a reference to the compared workload object, e.g., deployment.apps/nginx.
+	Object string           `json:"object"`
+	From   ComparedRevision `json:"from"`
+	To     ComparedRevision `json:"to"`
+	// Changes contains all changed fields.
+	Changes []FieldChange `json:"changes"`
+	// FailedPaths contains the paths of all relevant changes.
+	FailedPaths []string `json:"failedPaths"`
+}
+
+// ComparedRevision identifies one side of a Comparison.
+type ComparedRevision struct {
+	// Revision is the revision number. It is 0 for revisions that are not part of the workload's history, e.g., manifests.
+	Revision int64  `json:"revision"`
+	Name     string `json:"name"`
+}
+
+// Add adds the given comparison to the report. Changes matching any of the given patterns are considered relevant.
+// If no patterns are given, all changes are relevant.
+func (r *ChangeReport) Add(c Comparison, failOn []FieldPathPattern) {
+	if c.Changes == nil {
+		c.Changes = []FieldChange{}
+	}
+
+	c.FailedPaths = []string{}
+	for _, change := range c.Changes {
+		if len(failOn) == 0 || matchesAny(failOn, change.Path) {
+			c.FailedPaths = append(c.FailedPaths, change.Path)
+		}
+	}
+
+	r.Failed = r.Failed || len(c.FailedPaths) > 0
+	r.Comparisons = append(r.Comparisons, c)
+}
+
+func matchesAny(patterns []FieldPathPattern, path string) bool {
+	for _, p := range patterns {
+		if p.Matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes the report as indented JSON to the given writer.
+func (r *ChangeReport) Print(w io.Writer) error {
+	if r.Comparisons == nil {
+		r.Comparisons = []Comparison{}
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling report: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,97 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("FieldPathPattern", func() {
+	DescribeTable("#Matches",
+		func(pattern, path string, matches bool) {
+			p, err := ParseFieldPathPattern(pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.Matches(path)).To(Equal(matches))
+		},
+		Entry("equal path", "spec.containers[name=app].image", "spec.containers[name=app].image", true),
+		Entry("nested field", "spec.containers[*].resources", "spec.containers[name=app].resources.limits.cpu", true),
+		Entry("parent field", "spec.containers[*].resources", "spec.containers[name=sidecar]", true),
+		Entry("quoted key", "metadata.annotations['a.b/c']", "metadata.annotations['a.b/c']", true),
+		Entry("index", "spec.containers[0]", "spec.containers[0].image", true),
+		Entry("different key", "spec.containers[*].resources", "spec.containers[name=app].image", false),
+		Entry("different merge key", "spec.containers[name=app]", "spec.containers[name=sidecar].image", false),
+		Entry("index and merge key", "spec.containers[0]", "spec.containers[name=app].image", false),
+	)
+
+	It("should fail for invalid patterns", func() {
+		Expect(ParseFieldPathPattern("spec[")).Error().To(MatchError(ContainSubstring("invalid field path")))
+	})
+})
+
+var _ = Describe("ChangeReport", func() {
+	var (
+		report  *ChangeReport
+		changes []FieldChange
+	)
+
+	BeforeEach(func() {
+		report = &ChangeReport{}
+		changes = []FieldChange{
+			{Path: "spec.containers[name=app].image", From: "nginx:1.25", To: "nginx:1.26"},
+			{Path: "spec.containers[name=app].resources.limits.cpu", From: "1", To: nil},
+		}
+	})
+
+	Describe("#Add", func() {
+		It("should consider all changes relevant without patterns", func() {
+			report.Add(Comparison{Changes: changes}, nil)
+			Expect(report.Failed).To(BeTrue())
+			Expect(report.Comparisons[0].FailedPaths).To(ConsistOf(changes[0].Path, changes[1].Path))
+		})
+
+		It("should only consider changes matching the patterns relevant", func() {
+			p, err := ParseFieldPathPattern("spec.containers[*].resources")
+			Expect(err).NotTo(HaveOccurred())
+
+			report.Add(Comparison{Changes: changes}, []FieldPathPattern{p})
+			Expect(report.Failed).To(BeTrue())
+			Expect(report.Comparisons[0].FailedPaths).To(ConsistOf(changes[1].Path))
+		})
+
+		It("should not fail if no relevant changes exist", func() {
+			p, err := ParseFieldPathPattern("spec.containers[*].resources")
+			Expect(err).NotTo(HaveOccurred())
+
+			report.Add(Comparison{Changes: changes[:1]}, []FieldPathPattern{p})
+			report.Add(Comparison{}, nil)
+			Expect(report.Failed).To(BeFalse())
+			Expect(report.Comparisons).To(HaveLen(2))
+		})
+	})
+
+	Describe("#Print", func() {
+		It("should print the report as JSON", func() {
+			report.Add(Comparison{
+				Object:  "deployment.apps/nginx",
+				From:    ComparedRevision{Revision: 1, Name: "nginx-1"},
+				To:      ComparedRevision{Revision: 2, Name: "nginx-2"},
+				Changes: changes[:1],
+			}, nil)
+
+			out := NewBuffer()
+			Expect(report.Print(out)).To(Succeed())
+			Expect(out.Contents()).To(MatchJSON(`{
+  "failed": true,
+  "comparisons": [{
+    "object": "deployment.apps/nginx",
+    "from": {"revision": 1, "name": "nginx-1"},
+    "to": {"revision": 2, "name": "nginx-2"},
+    "changes": [{"path": "spec.containers[name=app].image", "from": "nginx:1.25", "to": "nginx:1.26"}],
+    "failedPaths": ["spec.containers[name=app].image"]
+  }]
+}`))
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/timebertt/kubectl-revisions/pkg/helper"
+)
+
+var (
+	containerPathRegexp  = regexp.MustCompile(`^spec\.(?:initContainers|containers|ephemeralContainers)\[name=([^\]]+)\](?:\.(.+))?$`)
+	envPathRegexp        = regexp.MustCompile(`^env\[name=([^\]]+)\](?:\.(.+))?$`)
+	annotationPathRegexp = regexp.MustCompile(`^metadata\.annotations(?:\.([^.\[]+)|\['(.+)'\])?$`)
+)
+
+// Summarize returns a compact, human-readable summary of the given changes between two pod templates as returned by
+// FieldChanges. Changes of images, environment variables, resources, and annotations are summarized in one line each,
+// e.g., `image app: nginx:1.25 -> nginx:1.26`. All other changes are counted in a single line.
+func Summarize(changes []FieldChange) []string {
+	var (
+		summary []string
+		other   int
+	)
+
+	for _, change := range changes {
+		if lines, ok := summarizeContainerChange(change); ok {
+			summary = append(summary, lines...)
+			continue
+		}
+		if lines, ok := summarizeAnnotationChange(change); ok {
+			summary = append(summary, lines...)
+			continue
+		}
+
+		other++
+	}
+
+	if other > 0 {
+		summary = append(summary, fmt.Sprintf("%d other %s changed", other, helper.Pluralize(other, "field")))
+	}
+
+	return summary
+}
+
+func summarizeContainerChange(change FieldChange) ([]string, bool) {
+	match := containerPathRegexp.FindStringSubmatch(change.Path)
+	if match == nil {
+		return nil, false
+	}
+	container, field := match[1], match[2]
+
+	switch {
+	case field == "":
+		if change.From == nil {
+			return []string{fmt.Sprintf("container %s added", container)}, true
+		}
+		if change.To == nil {
+			return []string{fmt.Sprintf("container %s removed", container)}, true
+		}
+	case field == "image":
+		return []string{fmt.Sprintf("image %s: %s -> %s", container, FormatValue(change.From), FormatValue(change.To))}, true
+	case field == "resources":
+		return []string{fmt.Sprintf("resources %s: %s -> %s", container, FormatValue(change.From), FormatValue(change.To))}, true
+	case strings.HasPrefix(field, "resources."):
+		resource := strings.TrimPrefix(field, "resources.")
+		return []string{fmt.Sprintf("resources %s %s: %s -> %s", container, resource, FormatValue(change.From), FormatValue(change.To))}, true
+	case strings.HasPrefix(field, "env["):
+		if envMatch := envPathRegexp.FindStringSubmatch(field); envMatch != nil {
+			name, envField := envMatch[1], envMatch[2]
+			if envField == "" || envField == "value" {
+				return []string{fmt.Sprintf("env %s/%s: %s -> %s", container, name, envValue(change.From), envValue(change.To))}, true
+			}
+			return []string{fmt.Sprintf("env %s/%s %s: %s -> %s", container, name, envField, FormatValue(change.From), FormatValue(change.To))}, true
+		}
+	}
+
+	return nil, false
+}
+
+func summarizeAnnotationChange(change FieldChange) ([]string, bool) {
+	changes, ok := annotationChanges(change)
+	if !ok {
+		return nil, false
+	}
+
+	summary := make([]string, 0, len(changes))
+	for _, c := range changes {
+		summary = append(summary, fmt.Sprintf("annotation %s: %s -> %s", c.Path, FormatValue(c.From), FormatValue(c.To)))
+	}
+	return summary, true
+}
+
+// annotationChanges returns the changes of individual annotations contained in the given change. The Path of the
+// returned changes is the annotation key. If the given change is not a change of annotations, false is returned.
+func annotationChanges(change FieldChange) ([]FieldChange, bool) {
+	match := annotationPathRegexp.FindStringSubmatch(change.Path)
+	if match == nil {
+		return nil, false
+	}
+
+	if key := match[1] + match[2]; key != "" {
+		return []FieldChange{{Path: key, From: change.From, To: change.To}}, true
+	}
+
+	// all annotations were added or removed, split them into individual changes
+	from, _ := change.From.(map[string]any)
+	to, _ := change.To.(map[string]any)
+
+	var changes []FieldChange
+	for _, key := range sortedKeys(from, to) {
+		changes = append(changes, FieldChange{Path: key, From: from[key], To: to[key]})
+	}
+	return changes, true
+}
+
+// envValue returns the value of an environment variable if the given field value is a full EnvVar. Otherwise, the
+// value is formatted as is.
+func envValue(v any) string {
+	if m, ok := v.(map[string]any); ok {
+		if value, ok := m["value"]; ok {
+			return FormatValue(value)
+		}
+		if valueFrom, ok := m["valueFrom"]; ok {
+			return FormatValue(valueFrom)
+		}
+	}
+	return FormatValue(v)
+}
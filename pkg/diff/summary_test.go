@@ -0,0 +1,94 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("Summarize", func() {
+	var from, to *corev1.Pod
+
+	BeforeEach(func() {
+		from = &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "app",
+					Image: "nginx:1.25",
+					Env: []corev1.EnvVar{
+						{Name: "FOO", Value: "foo"},
+						{Name: "BAR", Value: "bar"},
+					},
+				}},
+			},
+		}
+		to = from.DeepCopy()
+	})
+
+	summarize := func() []string {
+		GinkgoHelper()
+
+		changes, err := FieldChanges(from, to)
+		Expect(err).NotTo(HaveOccurred())
+		return Summarize(changes)
+	}
+
+	It("should return nothing if nothing changed", func() {
+		Expect(summarize()).To(BeEmpty())
+	})
+
+	It("should summarize changed images", func() {
+		to.Spec.Containers[0].Image = "nginx:1.26"
+		Expect(summarize()).To(ConsistOf("image app: nginx:1.25 -> nginx:1.26"))
+	})
+
+	It("should summarize changed, added, and removed env vars", func() {
+		to.Spec.Containers[0].Env = []corev1.EnvVar{
+			{Name: "FOO", Value: "baz"},
+			{Name: "NEW", Value: "new"},
+		}
+		Expect(summarize()).To(ConsistOf(
+			"env app/FOO: foo -> baz",
+			"env app/NEW: <none> -> new",
+			"env app/BAR: bar -> <none>",
+		))
+	})
+
+	It("should summarize changed resources", func() {
+		to.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")}
+		from.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")}
+		Expect(summarize()).To(ConsistOf("resources app limits.memory: 128Mi -> 256Mi"))
+	})
+
+	It("should summarize added and removed containers", func() {
+		to.Spec.Containers[0].Name = "new"
+		Expect(summarize()).To(ConsistOf("container new added", "container app removed"))
+	})
+
+	It("should summarize changed annotations", func() {
+		from.Annotations = map[string]string{"foo": "bar"}
+		to.Annotations = map[string]string{"foo": "baz", "kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z"}
+		Expect(summarize()).To(ConsistOf(
+			"annotation foo: bar -> baz",
+			"annotation kubectl.kubernetes.io/restartedAt: <none> -> 2024-01-01T00:00:00Z",
+		))
+	})
+
+	It("should summarize annotations that were added at once", func() {
+		to.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar", "bar": "baz"}}
+		Expect(summarize()).To(HaveExactElements(
+			"annotation bar: <none> -> baz",
+			"annotation foo: <none> -> bar",
+		))
+	})
+
+	It("should count all other changes", func() {
+		to.Spec.Containers[0].Args = []string{"--foo"}
+		to.Spec.ServiceAccountName = "foo"
+		Expect(summarize()).To(ConsistOf("2 other fields changed"))
+	})
+})
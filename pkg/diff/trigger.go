@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Trigger is an inferred reason for a new revision.
+type Trigger string
+
+const (
+	// TriggerImage means that a container image changed.
+	TriggerImage Trigger = "image"
+	// TriggerEnv means that the environment of a container changed.
+	TriggerEnv Trigger = "env"
+	// TriggerRestart means that the workload was restarted, e.g., using `kubectl rollout restart`.
+	TriggerRestart Trigger = "restart"
+	// TriggerConfig means that a config hash annotation changed, e.g., a checksum of a ConfigMap added by helm charts.
+	TriggerConfig Trigger = "config"
+	// TriggerOther means that any other field of the pod template changed.
+	TriggerOther Trigger = "other"
+)
+
+// Triggers is the ordered list of all Trigger values.
+var Triggers = []Trigger{TriggerImage, TriggerEnv, TriggerRestart, TriggerConfig, TriggerOther}
+
+// RestartedAtAnnotation is the annotation added to the pod template by `kubectl rollout restart`.
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+var configHashAnnotationRegexp = regexp.MustCompile(`(?i)(checksum|hash)`)
+
+// InferTriggers infers the reasons for a new revision from the given changes between two pod templates as returned by
+// FieldChanges. The returned triggers are unique and ordered like Triggers.
+func InferTriggers(changes []FieldChange) []Trigger {
+	found := make(map[Trigger]bool)
+
+	for _, change := range changes {
+		if match := containerPathRegexp.FindStringSubmatch(change.Path); match != nil {
+			switch field := match[2]; {
+			case field == "image":
+				found[TriggerImage] = true
+			case field == "env" || strings.HasPrefix(field, "env[") || strings.HasPrefix(field, "envFrom"):
+				found[TriggerEnv] = true
+			default:
+				found[TriggerOther] = true
+			}
+			continue
+		}
+
+		if annotations, ok := annotationChanges(change); ok {
+			for _, annotation := range annotations {
+				switch {
+				case annotation.Path == RestartedAtAnnotation:
+					found[TriggerRestart] = true
+				case configHashAnnotationRegexp.MatchString(annotation.Path):
+					found[TriggerConfig] = true
+				default:
+					found[TriggerOther] = true
+				}
+			}
+			continue
+		}
+
+		found[TriggerOther] = true
+	}
+
+	var triggers []Trigger
+	for _, trigger := range Triggers {
+		if found[trigger] {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
@@ -0,0 +1,71 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/diff"
+)
+
+var _ = Describe("InferTriggers", func() {
+	var from, to *corev1.Pod
+
+	BeforeEach(func() {
+		from = &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "app",
+					Image: "nginx:1.25",
+					Env:   []corev1.EnvVar{{Name: "FOO", Value: "foo"}},
+				}},
+			},
+		}
+		to = from.DeepCopy()
+	})
+
+	inferTriggers := func() []Trigger {
+		GinkgoHelper()
+
+		changes, err := FieldChanges(from, to)
+		Expect(err).NotTo(HaveOccurred())
+		return InferTriggers(changes)
+	}
+
+	It("should return nothing if nothing changed", func() {
+		Expect(inferTriggers()).To(BeEmpty())
+	})
+
+	It("should detect image changes", func() {
+		to.Spec.Containers[0].Image = "nginx:1.26"
+		Expect(inferTriggers()).To(HaveExactElements(TriggerImage))
+	})
+
+	It("should detect env changes", func() {
+		to.Spec.Containers[0].Env[0].Value = "bar"
+		Expect(inferTriggers()).To(HaveExactElements(TriggerEnv))
+	})
+
+	It("should detect restarts", func() {
+		to.Annotations = map[string]string{RestartedAtAnnotation: "2024-01-01T00:00:00Z"}
+		Expect(inferTriggers()).To(HaveExactElements(TriggerRestart))
+	})
+
+	It("should detect config hash changes", func() {
+		from.Annotations = map[string]string{"checksum/config": "abc"}
+		to.Annotations = map[string]string{"checksum/config": "def"}
+		Expect(inferTriggers()).To(HaveExactElements(TriggerConfig))
+	})
+
+	It("should detect other changes", func() {
+		to.Spec.ServiceAccountName = "foo"
+		Expect(inferTriggers()).To(HaveExactElements(TriggerOther))
+	})
+
+	It("should return multiple triggers in order", func() {
+		to.Spec.ServiceAccountName = "foo"
+		to.Spec.Containers[0].Env[0].Value = "bar"
+		to.Spec.Containers[0].Image = "nginx:1.26"
+		Expect(inferTriggers()).To(HaveExactElements(TriggerImage, TriggerEnv, TriggerOther))
+	})
+})
@@ -0,0 +1,96 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// Correlation maps revisions of a workload object to the Helm release versions that rendered their pod templates, see
+// Correlate.
+type Correlation map[history.Revision]*Release
+
+// Correlate finds the Helm release version that rendered the pod template of each of the given revisions of the given
+// workload object. A release version matches a revision if all fields of the pod template rendered for the object in
+// the release's manifest are equal in the revision's pod template. I.e., fields that are defaulted by the API server
+// are not considered.
+// If multiple versions rendered the same pod template (e.g., when an upgrade only changed other objects or after a
+// rollback), the latest version is used. Revisions without a matching release version are not part of the result.
+func Correlate(releases []*Release, obj client.Object, gk schema.GroupKind, revs history.Revisions) (Correlation, error) {
+	type renderedTemplate struct {
+		release  *Release
+		template *corev1.Pod
+	}
+
+	var templates []renderedTemplate
+	// iterate over the releases from the latest to the oldest version
+	for i := len(releases) - 1; i >= 0; i-- {
+		rendered, err := findObject(releases[i].Manifest, obj, gk)
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifest of Helm release %s version %d: %w", releases[i].Name, releases[i].Version, err)
+		}
+		if rendered == nil {
+			continue
+		}
+
+		template, err := history.PodTemplateOf(rendered)
+		if err != nil {
+			return nil, fmt.Errorf("error reading pod template of Helm release %s version %d: %w", releases[i].Name, releases[i].Version, err)
+		}
+		templates = append(templates, renderedTemplate{release: releases[i], template: template})
+	}
+
+	correlation := Correlation{}
+	for _, rev := range revs {
+		for _, t := range templates {
+			matches, err := history.PodTemplateContains(rev.PodTemplate(), t.template)
+			if err != nil {
+				return nil, err
+			}
+			if matches {
+				correlation[rev] = t.release
+				break
+			}
+		}
+	}
+
+	return correlation, nil
+}
+
+// findObject returns the given object from the given manifest or nil if the manifest doesn't contain the object.
+func findObject(manifest string, obj client.Object, gk schema.GroupKind) (client.Object, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+
+	for {
+		content := map[string]any{}
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		u := &unstructured.Unstructured{Object: content}
+		if len(content) == 0 || u.GroupVersionKind().GroupKind() != gk || u.GetName() != obj.GetName() {
+			continue
+		}
+		if namespace := u.GetNamespace(); namespace != "" && namespace != obj.GetNamespace() {
+			continue
+		}
+
+		typed, err := history.ToTyped(u)
+		if err != nil {
+			return nil, err
+		}
+		return typed.(client.Object), nil
+	}
+}
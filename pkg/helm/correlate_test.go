@@ -0,0 +1,131 @@
+package helm_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/helm"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+)
+
+var _ = Describe("Correlate", func() {
+	var (
+		deployment *appsv1.Deployment
+		gk         = appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind()
+	)
+
+	BeforeEach(func() {
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "app"}}
+	})
+
+	It("should match the rendered pod templates ignoring defaulted fields", func() {
+		rev1 := fake.ReplicaSetRevision(Default, 1, time.Time{}, podSpec("nginx:1.25"))
+		rev2 := fake.ReplicaSetRevision(Default, 2, time.Time{}, podSpec("nginx:1.26"))
+		release1, release2 := release(1, "nginx:1.25"), release(2, "nginx:1.26")
+
+		correlation, err := Correlate([]*Release{release1, release2}, deployment, gk, history.Revisions{rev1, rev2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(correlation).To(HaveLen(2))
+		Expect(correlation[rev1]).To(BeIdenticalTo(release1))
+		Expect(correlation[rev2]).To(BeIdenticalTo(release2))
+	})
+
+	It("should use the latest release version that rendered the same pod template", func() {
+		rev1 := fake.ReplicaSetRevision(Default, 1, time.Time{}, podSpec("nginx:1.25"))
+		release1, release2 := release(1, "nginx:1.25"), release(2, "nginx:1.25")
+
+		correlation, err := Correlate([]*Release{release1, release2}, deployment, gk, history.Revisions{rev1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(correlation[rev1]).To(BeIdenticalTo(release2))
+	})
+
+	It("should not match revisions with different pod templates", func() {
+		rev1 := fake.ReplicaSetRevision(Default, 1, time.Time{}, podSpec("nginx:1.24"))
+
+		correlation, err := Correlate([]*Release{release(1, "nginx:1.25")}, deployment, gk, history.Revisions{rev1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(correlation).To(BeEmpty())
+	})
+
+	It("should ignore releases that don't render the object", func() {
+		rev1 := fake.ReplicaSetRevision(Default, 1, time.Time{}, podSpec("nginx:1.25"))
+		other := release(1, "nginx:1.25")
+		deployment.Name = "other"
+
+		correlation, err := Correlate([]*Release{other}, deployment, gk, history.Revisions{rev1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(correlation).To(BeEmpty())
+	})
+
+	It("should fail for invalid manifests", func() {
+		invalid := &Release{Name: "nginx", Version: 1, Manifest: "kind: [foo"}
+		Expect(Correlate([]*Release{invalid}, deployment, gk, nil)).Error().To(MatchError(ContainSubstring("error reading manifest of Helm release nginx version 1")))
+	})
+})
+
+// release returns a release version rendering a Deployment with the given image (without any defaults).
+func release(version int, image string) *Release {
+	return &Release{
+		Name:      "nginx",
+		Namespace: "app",
+		Version:   version,
+		Info:      ReleaseInfo{Status: "deployed"},
+		Chart:     Chart{Metadata: ChartMetadata{Name: "nginx", Version: fmt.Sprintf("1.0.%d", version)}},
+		Manifest: fmt.Sprintf(`---
+# Source: nginx/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+---
+# Source: nginx/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: %s
+        ports:
+        - containerPort: 80
+        resources:
+          requests:
+            cpu: 0.5
+      volumes: []
+`, image),
+	}
+}
+
+// podSpec returns a pod spec with the given image and all defaults set by the API server.
+func podSpec(image string) corev1.PodSpec {
+	return corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "nginx",
+			Image: image,
+			Ports: []corev1.ContainerPort{{ContainerPort: 80, Protocol: corev1.ProtocolTCP}},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			},
+			TerminationMessagePath:   corev1.TerminationMessagePathDefault,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+		}},
+		RestartPolicy: corev1.RestartPolicyAlways,
+		DNSPolicy:     corev1.DNSClusterFirst,
+		SchedulerName: corev1.DefaultSchedulerName,
+	}
+}
@@ -0,0 +1,54 @@
+package helm_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/helm"
+)
+
+func TestHelm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Helm Suite")
+}
+
+// encodeRelease encodes the given release like Helm does in the release key of its Secrets.
+func encodeRelease(release *Release) []byte {
+	data, err := json.Marshal(release)
+	Expect(err).NotTo(HaveOccurred())
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err = writer.Write(data)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(writer.Close()).To(Succeed())
+
+	return []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+// releaseSecret returns a Secret storing the given release like Helm does.
+func releaseSecret(release *Release) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s%s.v%d", SecretNamePrefix, release.Name, release.Version),
+			Namespace: release.Namespace,
+			Labels: map[string]string{
+				"owner":   "helm",
+				"name":    release.Name,
+				"version": fmt.Sprint(release.Version),
+				"status":  release.Info.Status,
+			},
+		},
+		Type: SecretType,
+		Data: map[string][]byte{"release": encodeRelease(release)},
+	}
+}
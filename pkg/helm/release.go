@@ -0,0 +1,142 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReleaseNameAnnotation is the annotation that Helm adds to all objects of a release with the release's name.
+	ReleaseNameAnnotation = "meta.helm.sh/release-name"
+	// ReleaseNamespaceAnnotation is the annotation that Helm adds to all objects of a release with the release's
+	// namespace.
+	ReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+
+	// SecretType is the type of the Secrets storing Helm releases (one Secret per release version).
+	SecretType corev1.SecretType = "helm.sh/release.v1"
+	// SecretNamePrefix is the name prefix of the Secrets storing Helm releases. The full name is
+	// `sh.helm.release.v1.<release>.v<version>`.
+	SecretNamePrefix = "sh.helm.release.v1."
+)
+
+// ReleaseOf returns the name and namespace of the Helm release managing the given object based on its annotations.
+// ok is false if the object is not managed by Helm.
+func ReleaseOf(obj client.Object) (name, namespace string, ok bool) {
+	annotations := obj.GetAnnotations()
+	name = annotations[ReleaseNameAnnotation]
+	if name == "" {
+		return "", "", false
+	}
+
+	namespace = annotations[ReleaseNamespaceAnnotation]
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	return name, namespace, true
+}
+
+// Release is a single version of a Helm release as stored by Helm. Only the fields needed for correlating releases with
+// revisions are decoded.
+type Release struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Version   int         `json:"version"`
+	Info      ReleaseInfo `json:"info"`
+	Chart     Chart       `json:"chart"`
+	// Manifest is the rendered manifest of the release, i.e., a multi-document YAML stream.
+	Manifest string `json:"manifest"`
+}
+
+// ReleaseInfo describes the deployment of a release version.
+type ReleaseInfo struct {
+	Status      string `json:"status"`
+	Description string `json:"description"`
+}
+
+// Chart is the chart a release version was installed from.
+type Chart struct {
+	Metadata ChartMetadata `json:"metadata"`
+}
+
+// ChartMetadata contains the name and version of a chart.
+type ChartMetadata struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion"`
+}
+
+// ChartString returns the chart of the release in the same format as the CHART column of `helm history`, e.g.,
+// nginx-1.2.3.
+func (r *Release) ChartString() string {
+	if r.Chart.Metadata.Name == "" {
+		return ""
+	}
+	return r.Chart.Metadata.Name + "-" + r.Chart.Metadata.Version
+}
+
+// ListReleases reads all versions of the given Helm release from the release Secrets in the given namespace. The
+// result is sorted by version (ascending).
+func ListReleases(ctx context.Context, r client.Reader, namespace, name string) ([]*Release, error) {
+	secretList := &corev1.SecretList{}
+	if err := r.List(ctx, secretList, client.InNamespace(namespace), client.MatchingLabels{"owner": "helm", "name": name}); err != nil {
+		return nil, fmt.Errorf("error listing Helm release Secrets: %w", err)
+	}
+
+	var releases []*Release
+	for _, secret := range secretList.Items {
+		if secret.Type != SecretType || !strings.HasPrefix(secret.Name, SecretNamePrefix) {
+			continue
+		}
+
+		release, err := DecodeRelease(secret.Data["release"])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding Helm release Secret %s: %w", secret.Name, err)
+		}
+		releases = append(releases, release)
+	}
+
+	slices.SortFunc(releases, func(a, b *Release) int {
+		return a.Version - b.Version
+	})
+	return releases, nil
+}
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// DecodeRelease decodes a release as stored by Helm in the release key of its Secrets, i.e., base64-encoded gzipped
+// JSON.
+func DecodeRelease(data []byte) (*Release, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// older Helm versions didn't compress releases
+	if bytes.HasPrefix(decoded, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		if decoded, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	release := &Release{}
+	if err := json.Unmarshal(decoded, release); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
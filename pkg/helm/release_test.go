@@ -0,0 +1,116 @@
+package helm_test
+
+import (
+	"context"
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/helm"
+)
+
+var _ = Describe("Release", func() {
+	Describe("ReleaseOf", func() {
+		It("should return the release from the annotations", func() {
+			obj := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "app",
+				Annotations: map[string]string{
+					ReleaseNameAnnotation:      "nginx",
+					ReleaseNamespaceAnnotation: "releases",
+				},
+			}}
+
+			name, namespace, ok := ReleaseOf(obj)
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal("nginx"))
+			Expect(namespace).To(Equal("releases"))
+		})
+
+		It("should default the namespace to the object's namespace", func() {
+			obj := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "app",
+				Annotations: map[string]string{ReleaseNameAnnotation: "nginx"},
+			}}
+
+			_, namespace, ok := ReleaseOf(obj)
+			Expect(ok).To(BeTrue())
+			Expect(namespace).To(Equal("app"))
+		})
+
+		It("should return false for objects not managed by Helm", func() {
+			_, _, ok := ReleaseOf(&appsv1.Deployment{})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("#ChartString", func() {
+		It("should return the chart name and version", func() {
+			release := &Release{Chart: Chart{Metadata: ChartMetadata{Name: "nginx", Version: "1.2.3"}}}
+			Expect(release.ChartString()).To(Equal("nginx-1.2.3"))
+			Expect((&Release{}).ChartString()).To(BeEmpty())
+		})
+	})
+
+	Describe("DecodeRelease", func() {
+		It("should decode gzipped releases", func() {
+			release := &Release{Name: "nginx", Version: 3, Manifest: "kind: Deployment"}
+			Expect(DecodeRelease(encodeRelease(release))).To(Equal(release))
+		})
+
+		It("should decode uncompressed releases", func() {
+			data := base64.StdEncoding.EncodeToString([]byte(`{"name":"nginx","version":1,"info":{"status":"superseded"}}`))
+			Expect(DecodeRelease([]byte(data))).To(Equal(&Release{Name: "nginx", Version: 1, Info: ReleaseInfo{Status: "superseded"}}))
+		})
+
+		It("should fail for invalid data", func() {
+			Expect(DecodeRelease([]byte("not base64!"))).Error().To(HaveOccurred())
+		})
+	})
+
+	Describe("ListReleases", func() {
+		var (
+			ctx        context.Context
+			fakeClient client.Client
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			fakeClient = fakeclient.NewClientBuilder().Build()
+
+			for _, release := range []*Release{
+				{Name: "nginx", Namespace: "app", Version: 2, Info: ReleaseInfo{Status: "deployed"}},
+				{Name: "nginx", Namespace: "app", Version: 1, Info: ReleaseInfo{Status: "superseded"}},
+				{Name: "other", Namespace: "app", Version: 1, Info: ReleaseInfo{Status: "deployed"}},
+				{Name: "nginx", Namespace: "other", Version: 1, Info: ReleaseInfo{Status: "deployed"}},
+			} {
+				Expect(fakeClient.Create(ctx, releaseSecret(release))).To(Succeed())
+			}
+
+			Expect(fakeClient.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:      "unrelated",
+				Namespace: "app",
+				Labels:    map[string]string{"owner": "helm", "name": "nginx"},
+			}})).To(Succeed())
+		})
+
+		It("should return all versions of the release sorted by version", func() {
+			releases, err := ListReleases(ctx, fakeClient, "app", "nginx")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(releases).To(HaveExactElements(
+				HaveField("Version", 1),
+				HaveField("Version", 2),
+			))
+			Expect(releases).To(HaveEach(HaveField("Name", "nginx")))
+		})
+
+		It("should return an empty list if the release doesn't exist", func() {
+			Expect(ListReleases(ctx, fakeClient, "app", "foo")).To(BeEmpty())
+		})
+	})
+})
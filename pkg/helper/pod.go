@@ -35,3 +35,62 @@ func SetPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType, sta
 	}
 	pod.Status.Conditions = append(pod.Status.Conditions, *condition)
 }
+
+// PodStatus returns a short human-readable status of a pod similar to the STATUS column of `kubectl get pods`, e.g.,
+// Running, CrashLoopBackOff, or Terminating.
+func PodStatus(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+
+	for _, container := range pod.Status.InitContainerStatuses {
+		switch {
+		case container.State.Terminated != nil && container.State.Terminated.ExitCode == 0:
+			continue
+		case container.State.Terminated != nil && container.State.Terminated.Reason != "":
+			return "Init:" + container.State.Terminated.Reason
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "" && container.State.Waiting.Reason != "PodInitializing":
+			return "Init:" + container.State.Waiting.Reason
+		case container.State.Terminated != nil || container.State.Waiting != nil || container.State.Running != nil:
+			return "Init"
+		}
+	}
+
+	for _, container := range pod.Status.ContainerStatuses {
+		switch {
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+			status = container.State.Waiting.Reason
+		case container.State.Terminated != nil && container.State.Terminated.Reason != "":
+			status = container.State.Terminated.Reason
+		}
+	}
+
+	if status == "" {
+		return "Unknown"
+	}
+	return status
+}
+
+// ReadyContainers returns the number of ready containers and the total number of containers of a pod.
+func ReadyContainers(pod *corev1.Pod) (ready, total int) {
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Ready {
+			ready++
+		}
+	}
+	return ready, len(pod.Spec.Containers)
+}
+
+// RestartCount returns the sum of the restart counts of all containers of a pod.
+func RestartCount(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, container := range pod.Status.ContainerStatuses {
+		restarts += container.RestartCount
+	}
+	return restarts
+}
@@ -4,6 +4,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	. "github.com/timebertt/kubectl-revisions/pkg/helper"
 )
@@ -76,4 +77,60 @@ var _ = Describe("Pod helpers", func() {
 			))
 		})
 	})
+
+	Describe("PodStatus", func() {
+		It("should return the phase by default", func() {
+			Expect(PodStatus(pod)).To(Equal("Unknown"))
+			pod.Status.Phase = corev1.PodRunning
+			Expect(PodStatus(pod)).To(Equal("Running"))
+		})
+
+		It("should prefer the pod's reason", func() {
+			pod.Status.Phase = corev1.PodFailed
+			pod.Status.Reason = "Evicted"
+			Expect(PodStatus(pod)).To(Equal("Evicted"))
+		})
+
+		It("should return the reason of waiting or terminated containers", func() {
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			}
+			Expect(PodStatus(pod)).To(Equal("CrashLoopBackOff"))
+		})
+
+		It("should return the status of init containers", func() {
+			pod.Status.Phase = corev1.PodPending
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+				{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+			}
+			Expect(PodStatus(pod)).To(Equal("Init:ImagePullBackOff"))
+		})
+
+		It("should return Terminating for deleted pods", func() {
+			pod.Status.Phase = corev1.PodRunning
+			pod.DeletionTimestamp = &metav1.Time{}
+			Expect(PodStatus(pod)).To(Equal("Terminating"))
+		})
+	})
+
+	Describe("ReadyContainers", func() {
+		It("should count the ready containers", func() {
+			pod.Spec.Containers = []corev1.Container{{Name: "a"}, {Name: "b"}}
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "a", Ready: true}, {Name: "b"}}
+
+			ready, total := ReadyContainers(pod)
+			Expect(ready).To(Equal(1))
+			Expect(total).To(Equal(2))
+		})
+	})
+
+	Describe("RestartCount", func() {
+		It("should sum up the restarts of all containers", func() {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{RestartCount: 2}, {RestartCount: 3}}
+			Expect(RestartCount(pod)).To(BeEquivalentTo(5))
+		})
+	})
 })
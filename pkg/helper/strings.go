@@ -0,0 +1,9 @@
+package helper
+
+// Pluralize returns the given word with an "s" appended unless n is 1, e.g., "1 revision" but "2 revisions".
+func Pluralize(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
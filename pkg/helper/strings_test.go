@@ -0,0 +1,19 @@
+package helper_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/helper"
+)
+
+var _ = Describe("String helpers", func() {
+	DescribeTable("Pluralize",
+		func(n int, expected string) {
+			Expect(Pluralize(n, "field")).To(Equal(expected))
+		},
+		Entry("zero", 0, "fields"),
+		Entry("one", 1, "field"),
+		Entry("many", 2, "fields"),
+	)
+})
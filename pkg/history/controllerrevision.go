@@ -12,7 +12,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var _ Revision = &ControllerRevision{}
+var (
+	_ Revision      = &ControllerRevision{}
+	_ RoledRevision = &ControllerRevision{}
+
+	_ PodMatchingRevision = &ControllerRevision{}
+)
 
 // ControllerRevision is a Revision of a StatefulSet or DaemonSet.
 type ControllerRevision struct {
@@ -20,6 +25,10 @@ type ControllerRevision struct {
 	Template           *corev1.Pod
 
 	Replicas
+
+	role Role
+	// podPredicate matches the Pods belonging to this revision, it is set by the kind-specific constructors.
+	podPredicate PodPredicate
 }
 
 // GetObjectKind implements runtime.Object.
@@ -62,6 +71,21 @@ func (c *ControllerRevision) PodTemplate() *corev1.Pod {
 	return c.Template
 }
 
+// Role returns the role of the ControllerRevision in the rollout of its owner, see Role.
+func (c *ControllerRevision) Role() Role {
+	return c.role
+}
+
+func (c *ControllerRevision) setRole(role Role) {
+	c.role = role
+}
+
+// MatchesPod returns true if the given Pod belongs to the ControllerRevision, i.e., if it is controlled by the
+// ControllerRevision's owner and refers to the ControllerRevision via the kind-specific revision label.
+func (c *ControllerRevision) MatchesPod(pod *corev1.Pod) bool {
+	return c.podPredicate != nil && sameController(pod, c.ControllerRevision) && c.podPredicate(pod)
+}
+
 // ListControllerRevisionsAndPods is a helper for a ControllerRevision-based History implementation that needs to find
 // all ControllerRevisions and Pods belonging to a given workload object.
 func ListControllerRevisionsAndPods(ctx context.Context, r client.Reader, namespace string, selector *metav1.LabelSelector) (*appsv1.ControllerRevisionList, *corev1.PodList, error) {
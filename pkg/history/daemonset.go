@@ -48,6 +48,11 @@ func (d DaemonSetHistory) ListRevisions(ctx context.Context, obj client.Object)
 	}
 
 	Sort(revs)
+	if len(revs) > 0 {
+		// the DaemonSet controller always assigns the highest revision number to the ControllerRevision matching the
+		// DaemonSet's pod template
+		assignRoles(revs, nil, revs[len(revs)-1])
+	}
 	return revs, nil
 }
 
@@ -57,6 +62,7 @@ func NewControllerRevisionForDaemonSet(controllerRevision *appsv1.ControllerRevi
 
 	revision := &ControllerRevision{}
 	revision.ControllerRevision = controllerRevision
+	revision.podPredicate = PodBelongsToDaemonSetRevision(controllerRevision)
 
 	daemonSet := &appsv1.DaemonSet{}
 	if daemonSetData, ok := revision.ControllerRevision.Data.Object.(*appsv1.DaemonSet); ok && daemonSetData != nil {
@@ -151,6 +151,26 @@ var _ = Describe("DaemonSetHistory", func() {
 			Expect(revs[1].ReadyReplicas()).To(BeEquivalentTo(1))
 		})
 
+		It("should mark the latest revision as the update revision during a rollout", func() {
+			Expect(fakeClient.Create(context.Background(), podForDaemonSetRevision(controllerRevision1))).To(Succeed())
+
+			revs, err := history.ListRevisions(ctx, daemonSet)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(RoleOf(revs[0])).To(Equal(RoleCurrent))
+			Expect(RoleOf(revs[1])).To(Equal(RoleUpdate))
+		})
+
+		It("should mark the latest revision as the current revision after a rollout", func() {
+			Expect(fakeClient.Create(context.Background(), podForDaemonSetRevision(controllerRevision3))).To(Succeed())
+
+			revs, err := history.ListRevisions(ctx, daemonSet)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(RoleOf(revs[0])).To(Equal(RoleOld))
+			Expect(RoleOf(revs[1])).To(Equal(RoleCurrent))
+		})
+
 		It("should also work via ListRevisions shortcut", func() {
 			revs, err := ListRevisions(ctx, fakeClient, daemonSet)
 			Expect(err).NotTo(HaveOccurred())
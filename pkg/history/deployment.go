@@ -47,5 +47,7 @@ func (d DeploymentHistory) ListRevisions(ctx context.Context, obj client.Object)
 	}
 
 	Sort(revs)
+	// the new ReplicaSet is the one matching the Deployment's pod template
+	assignRoles(revs, nil, revisionByTemplate(revs, &deployment.Spec.Template))
 	return revs, nil
 }
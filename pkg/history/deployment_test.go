@@ -139,6 +139,50 @@ var _ = Describe("DeploymentHistory", func() {
 			Expect(revs[1].ReadyReplicas()).To(BeEquivalentTo(0))
 		})
 
+		Context("roles", func() {
+			BeforeEach(func() {
+				// let the Deployment's pod template match the ReplicaSet of revision 3
+				deployment.Spec.Template.Labels = map[string]string{"app": "deploy"}
+				deployment.Spec.Template.Spec.Containers[0].Image = "test:3"
+			})
+
+			It("should mark the new ReplicaSet as the update revision during a rollout", func() {
+				revs, err := history.ListRevisions(ctx, deployment)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(RoleOf(revs[0])).To(Equal(RoleCurrent))
+				Expect(RoleOf(revs[1])).To(Equal(RoleUpdate))
+
+				Expect(revs.ByRole(RoleCurrent)).To(haveNumber(1))
+				Expect(revs.ByRole(RoleUpdate)).To(haveNumber(3))
+			})
+
+			It("should mark the new ReplicaSet as the current revision after a rollout", func() {
+				replicaSet1.Status.Replicas = 0
+				replicaSet1.Status.ReadyReplicas = 0
+				Expect(fakeClient.Status().Update(ctx, replicaSet1)).To(Succeed())
+
+				revs, err := history.ListRevisions(ctx, deployment)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(RoleOf(revs[0])).To(Equal(RoleOld))
+				Expect(RoleOf(revs[1])).To(Equal(RoleCurrent))
+
+				// without a rollout in progress, the update revision is the current one
+				Expect(revs.ByRole(RoleUpdate)).To(haveNumber(3))
+			})
+
+			It("should mark the newest ReplicaSet with replicas as the current revision if there is no new ReplicaSet", func() {
+				deployment.Spec.Template.Spec.Containers[0].Image = "test:4"
+
+				revs, err := history.ListRevisions(ctx, deployment)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(RoleOf(revs[0])).To(Equal(RoleOld))
+				Expect(RoleOf(revs[1])).To(Equal(RoleCurrent))
+			})
+		})
+
 		It("should also work via ListRevisions shortcut", func() {
 			revs, err := ListRevisions(ctx, fakeClient, deployment)
 			Expect(err).NotTo(HaveOccurred())
@@ -171,6 +215,7 @@ func replicaSetForDeployment(deployment *appsv1.Deployment, revision int64, sche
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
+			Template: *template,
 		},
 	}
 
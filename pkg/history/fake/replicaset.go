@@ -0,0 +1,44 @@
+package fake
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// ReplicaSetRevision returns a revision of the nginx Deployment in the default namespace backed by a ReplicaSet, e.g.,
+// nginx-1 with UID rs-1 for revision number 1. The ReplicaSet is created at the given time and its pod template has the
+// given pod spec and the labels app=nginx and pod-template-hash=hash-<number>. Failures are reported to the given Gomega
+// instance, e.g., gomega.Default in ginkgo tests.
+func ReplicaSetRevision(g gomega.Gomega, number int64, created time.Time, spec corev1.PodSpec) *history.ReplicaSet {
+	rev, err := history.NewReplicaSet(&appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("nginx-%d", number),
+			Namespace:         "default",
+			UID:               types.UID(fmt.Sprintf("rs-%d", number)),
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       map[string]string{"deployment.kubernetes.io/revision": strconv.FormatInt(number, 10)},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app":                                  "nginx",
+						appsv1.DefaultDeploymentUniqueLabelKey: fmt.Sprintf("hash-%d", number),
+					},
+				},
+				Spec: spec,
+			},
+		},
+	})
+	g.ExpectWithOffset(1, err).NotTo(gomega.HaveOccurred())
+	return rev
+}
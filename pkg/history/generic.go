@@ -0,0 +1,252 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DataFormat specifies how the data of a ControllerRevision is decoded.
+type DataFormat string
+
+const (
+	// DataFormatPatch means that the ControllerRevision data is a strategic merge patch of the owner object (like for
+	// DaemonSets). Patch directives like `$patch: replace` are removed when decoding the pod template.
+	DataFormatPatch DataFormat = "patch"
+	// DataFormatObject means that the ControllerRevision data is the full owner object (like for StatefulSets).
+	DataFormatObject DataFormat = "object"
+)
+
+// DataFormats is the list of supported DataFormat values.
+var DataFormats = []DataFormat{DataFormatPatch, DataFormatObject}
+
+// GenericKind configures how the ControllerRevision-based history of objects of an arbitrary kind is read, e.g., for
+// custom resources of operators that store their history in ControllerRevisions like StatefulSets and DaemonSets.
+type GenericKind struct {
+	// Group is the API group of the kind.
+	Group string `json:"group"`
+	// Kind is the name of the kind.
+	Kind string `json:"kind"`
+
+	// SelectorPath is the dot-separated path of the label selector in the owner object. Defaults to spec.selector.
+	SelectorPath string `json:"selectorPath,omitempty"`
+	// TemplatePath is the dot-separated path of the pod template in the ControllerRevision data. Defaults to
+	// spec.template.
+	TemplatePath string `json:"templatePath,omitempty"`
+	// DataFormat specifies how the ControllerRevision data is decoded. Defaults to DataFormatPatch.
+	DataFormat DataFormat `json:"dataFormat,omitempty"`
+	// PodRevisionLabel is the label on Pods that refers to the ControllerRevision they belong to. Pods match a revision if
+	// the label value equals the ControllerRevision's name or its value of the same label. Defaults to
+	// controller-revision-hash.
+	PodRevisionLabel string `json:"podRevisionLabel,omitempty"`
+}
+
+// GroupKind returns the GroupKind of this GenericKind.
+func (g GenericKind) GroupKind() schema.GroupKind {
+	return schema.GroupKind{Group: g.Group, Kind: g.Kind}
+}
+
+// Default returns a copy of this GenericKind with defaults applied for all empty fields.
+func (g GenericKind) Default() GenericKind {
+	if g.SelectorPath == "" {
+		g.SelectorPath = "spec.selector"
+	}
+	if g.TemplatePath == "" {
+		g.TemplatePath = "spec.template"
+	}
+	if g.DataFormat == "" {
+		g.DataFormat = DataFormatPatch
+	}
+	if g.PodRevisionLabel == "" {
+		g.PodRevisionLabel = appsv1.ControllerRevisionHashLabelKey
+	}
+	return g
+}
+
+// Validate validates this GenericKind.
+func (g GenericKind) Validate() error {
+	if g.Kind == "" {
+		return fmt.Errorf("kind must not be empty")
+	}
+
+	switch g.DataFormat {
+	case "", DataFormatPatch, DataFormatObject:
+	default:
+		return fmt.Errorf("unsupported data format %q, must be one of %v", g.DataFormat, DataFormats)
+	}
+
+	return nil
+}
+
+var _ History = GenericHistory{}
+
+// GenericHistory implements the History interface for objects of arbitrary kinds that store their history in
+// ControllerRevisions. It works on unstructured objects, so that no API types of the owner kind are required.
+type GenericHistory struct {
+	Client client.Reader
+	Kind   GenericKind
+}
+
+func (g GenericHistory) ListRevisions(ctx context.Context, obj client.Object) (Revisions, error) {
+	kind := g.Kind.Default()
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error converting %s to unstructured: %w", kind.Kind, err)
+	}
+
+	selector, ok, err := selectorAt(content, kind.SelectorPath)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s selector: %w", kind.Kind, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s %s has no selector at %s", kind.Kind, obj.GetName(), kind.SelectorPath)
+	}
+
+	controllerRevisionList, podList, err := ListControllerRevisionsAndPods(ctx, g.Client, obj.GetNamespace(), selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var revs Revisions
+	for _, controllerRevision := range controllerRevisionList.Items {
+		if !metav1.IsControlledBy(&controllerRevision, obj) {
+			continue
+		}
+
+		revision, err := NewControllerRevisionForGenericKind(&controllerRevision, kind)
+		if err != nil {
+			return nil, fmt.Errorf("error converting ControllerRevision %s: %w", controllerRevision.Name, err)
+		}
+
+		revision.Replicas = CountReplicas(podList, PodBelongsToGenericRevision(&controllerRevision, kind.PodRevisionLabel))
+
+		revs = append(revs, revision)
+	}
+
+	Sort(revs)
+	// many operators mirror the status fields of StatefulSets (e.g., OpenKruise's CloneSet)
+	currentName, _, _ := unstructured.NestedString(content, "status", "currentRevision")
+	updateName, _, _ := unstructured.NestedString(content, "status", "updateRevision")
+	assignRoles(revs, revisionByName(revs, currentName), revisionByName(revs, updateName))
+	return revs, nil
+}
+
+// NewControllerRevisionForGenericKind transforms the given ControllerRevision of an object of the given GenericKind to
+// a Revision object.
+func NewControllerRevisionForGenericKind(controllerRevision *appsv1.ControllerRevision, kind GenericKind) (*ControllerRevision, error) {
+	kind = kind.Default()
+	controllerRevision = controllerRevision.DeepCopy()
+
+	revision := &ControllerRevision{}
+	revision.ControllerRevision = controllerRevision
+	revision.podPredicate = PodBelongsToGenericRevision(controllerRevision, kind.PodRevisionLabel)
+
+	raw := controllerRevision.Data.Raw
+	if controllerRevision.Data.Object != nil {
+		var err error
+		if raw, err = json.Marshal(controllerRevision.Data.Object); err != nil {
+			return nil, err
+		}
+	}
+
+	data := map[string]any{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("error decoding revision data: %w", err)
+	}
+
+	templateField, ok, err := unstructured.NestedMap(data, splitPath(kind.TemplatePath)...)
+	if err != nil || !ok {
+		return nil, fmt.Errorf("revision data has no pod template at %s", kind.TemplatePath)
+	}
+	if kind.DataFormat == DataFormatPatch {
+		removePatchDirectives(templateField)
+	}
+
+	template := &corev1.PodTemplateSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(templateField, template); err != nil {
+		return nil, fmt.Errorf("error parsing pod template: %w", err)
+	}
+
+	revision.Template = &corev1.Pod{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+
+	return revision, nil
+}
+
+// PodBelongsToGenericRevision returns a PodPredicate that matches Pods whose revision label refers to the given
+// ControllerRevision, either by name or by the ControllerRevision's value of the same label.
+func PodBelongsToGenericRevision(revision *appsv1.ControllerRevision, label string) PodPredicate {
+	return func(pod *corev1.Pod) bool {
+		value := pod.Labels[label]
+		if value == "" {
+			return false
+		}
+		return value == revision.Name || value == revision.Labels[label]
+	}
+}
+
+// SelectorPath returns the dot-separated path of the label selector in the objects whose history is read by the given
+// History, i.e., the configured GenericKind.SelectorPath for generic kinds and spec.selector for all other kinds.
+func SelectorPath(h History) string {
+	if g, ok := h.(GenericHistory); ok {
+		return g.Kind.Default().SelectorPath
+	}
+	return "spec.selector"
+}
+
+// selectorAt reads the label selector at the given dot-separated path of the given unstructured object content. ok is
+// false if there is no selector at the path.
+func selectorAt(content map[string]any, path string) (selector *metav1.LabelSelector, ok bool, err error) {
+	selectorField, ok, err := unstructured.NestedFieldNoCopy(content, splitPath(path)...)
+	if err != nil || !ok || selectorField == nil {
+		return nil, false, nil
+	}
+	selectorMap, ok := selectorField.(map[string]any)
+	if !ok {
+		return nil, false, fmt.Errorf("expected a label selector at %s, got %T", path, selectorField)
+	}
+
+	selector = &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, selector); err != nil {
+		return nil, false, err
+	}
+	return selector, true, nil
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "."), ".")
+}
+
+// removePatchDirectives recursively removes strategic merge patch directives (keys starting with `$`) from the given
+// map.
+func removePatchDirectives(m map[string]any) {
+	for k, v := range m {
+		if strings.HasPrefix(k, "$") {
+			delete(m, k)
+			continue
+		}
+
+		switch val := v.(type) {
+		case map[string]any:
+			removePatchDirectives(val)
+		case []any:
+			for _, item := range val {
+				if itemMap, ok := item.(map[string]any); ok {
+					removePatchDirectives(itemMap)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package history_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("GenericKind", func() {
+	Describe("#Default", func() {
+		It("should default all empty fields", func() {
+			Expect(GenericKind{Kind: "CloneSet"}.Default()).To(Equal(GenericKind{
+				Kind:             "CloneSet",
+				SelectorPath:     "spec.selector",
+				TemplatePath:     "spec.template",
+				DataFormat:       DataFormatPatch,
+				PodRevisionLabel: "controller-revision-hash",
+			}))
+		})
+
+		It("should not overwrite set fields", func() {
+			kind := GenericKind{Kind: "CloneSet", SelectorPath: "a", TemplatePath: "b", DataFormat: DataFormatObject, PodRevisionLabel: "c"}
+			Expect(kind.Default()).To(Equal(kind))
+		})
+	})
+
+	Describe("#Validate", func() {
+		It("should succeed for valid kinds", func() {
+			Expect(GenericKind{Kind: "CloneSet"}.Validate()).To(Succeed())
+			Expect(GenericKind{Kind: "CloneSet", DataFormat: DataFormatObject}.Validate()).To(Succeed())
+		})
+
+		It("should fail for an empty kind", func() {
+			Expect(GenericKind{}.Validate()).To(MatchError("kind must not be empty"))
+		})
+
+		It("should fail for an unsupported data format", func() {
+			Expect(GenericKind{Kind: "CloneSet", DataFormat: "foo"}.Validate()).To(MatchError(ContainSubstring(`unsupported data format "foo"`)))
+		})
+	})
+})
+
+var _ = Describe("GenericHistory", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+
+		history GenericHistory
+		owner   *unstructured.Unstructured
+
+		controllerRevision1, controllerRevision3 *appsv1.ControllerRevision
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClient = fakeclient.NewClientBuilder().Build()
+
+		history = GenericHistory{
+			Client: fakeClient,
+			Kind: GenericKind{
+				Group: "apps.kruise.io",
+				Kind:  "CloneSet",
+			},
+		}
+
+		owner = &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"selector": map[string]any{
+					"matchLabels": map[string]any{"app": "clone"},
+				},
+			},
+		}}
+		owner.SetAPIVersion("apps.kruise.io/v1alpha1")
+		owner.SetKind("CloneSet")
+		owner.SetName("clone")
+		owner.SetNamespace("test")
+		owner.SetUID("clone-uid")
+
+		// create a non-sorted list of ControllerRevisions to verify that ListRevisions returns a sorted list
+		controllerRevision3 = controllerRevisionForGenericOwner(owner, 3, patchData(3))
+		Expect(fakeClient.Create(ctx, controllerRevision3)).To(Succeed())
+		controllerRevision1 = controllerRevisionForGenericOwner(owner, 1, patchData(1))
+		Expect(fakeClient.Create(ctx, controllerRevision1)).To(Succeed())
+
+		controllerRevisionUnrelated := controllerRevisionForGenericOwner(owner, 2, patchData(2))
+		controllerRevisionUnrelated.OwnerReferences[0].UID = "other"
+		Expect(fakeClient.Create(ctx, controllerRevisionUnrelated)).To(Succeed())
+
+		for _, revision := range []*appsv1.ControllerRevision{controllerRevision1, controllerRevision3, controllerRevision3} {
+			Expect(fakeClient.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				GenerateName: revision.Name + "-",
+				Namespace:    "test",
+				Labels:       map[string]string{"app": "clone", "controller-revision-hash": revision.Name},
+			}})).To(Succeed())
+		}
+	})
+
+	Describe("#ListRevisions", func() {
+		It("should return a sorted list of the owned ControllerRevisions", func() {
+			revs, err := history.ListRevisions(ctx, owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs).To(HaveLen(2))
+
+			Expect(revs[0].Number()).To(BeEquivalentTo(1))
+			Expect(revs[0].Name()).To(Equal(controllerRevision1.Name))
+			Expect(revs[0].CurrentReplicas()).To(BeEquivalentTo(1))
+			Expect(revs[1].Number()).To(BeEquivalentTo(3))
+			Expect(revs[1].Name()).To(Equal(controllerRevision3.Name))
+			Expect(revs[1].CurrentReplicas()).To(BeEquivalentTo(2))
+		})
+
+		It("should decode the pod template and remove patch directives", func() {
+			revs, err := history.ListRevisions(ctx, owner)
+			Expect(err).NotTo(HaveOccurred())
+
+			template := revs[1].PodTemplate()
+			Expect(template.Labels).To(Equal(map[string]string{"app": "clone"}))
+			Expect(template.Spec.Containers).To(ConsistOf(corev1.Container{Name: "app", Image: "app:3"}))
+		})
+
+		It("should assign the roles from the owner's status if present", func() {
+			Expect(unstructured.SetNestedStringMap(owner.Object, map[string]string{
+				"currentRevision": controllerRevision1.Name,
+				"updateRevision":  controllerRevision3.Name,
+			}, "status")).To(Succeed())
+
+			revs, err := history.ListRevisions(ctx, owner)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(RoleOf(revs[0])).To(Equal(RoleCurrent))
+			Expect(RoleOf(revs[1])).To(Equal(RoleUpdate))
+		})
+
+		It("should use the configured selector path", func() {
+			history.Kind.SelectorPath = "spec.other"
+			_, err := history.ListRevisions(ctx, owner)
+			Expect(err).To(MatchError("CloneSet clone has no selector at spec.other"))
+
+			Expect(unstructured.SetNestedField(owner.Object, map[string]any{"matchLabels": map[string]any{"app": "clone"}}, "spec", "other")).To(Succeed())
+			Expect(history.ListRevisions(ctx, owner)).To(HaveLen(2))
+		})
+	})
+})
+
+var _ = Describe("NewControllerRevisionForGenericKind", func() {
+	It("should decode full objects at the configured template path", func() {
+		controllerRevision := &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm-1"},
+			Revision:   1,
+			Data: runtime.RawExtension{
+				Raw: []byte(`{"spec":{"template":{"metadata":{"labels":{"app":"vm","$patch":"keep"}},"spec":{"containers":[{"name":"app","image":"app:1"}]}}}}`),
+			},
+		}
+
+		revision, err := NewControllerRevisionForGenericKind(controllerRevision, GenericKind{
+			Kind:         "VirtualMachine",
+			TemplatePath: ".spec.template",
+			DataFormat:   DataFormatObject,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision.Number()).To(BeEquivalentTo(1))
+		Expect(revision.PodTemplate().Labels).To(Equal(map[string]string{"app": "vm", "$patch": "keep"}))
+		Expect(revision.PodTemplate().Spec.Containers).To(ConsistOf(corev1.Container{Name: "app", Image: "app:1"}))
+	})
+
+	It("should fail if there is no pod template at the configured path", func() {
+		controllerRevision := &appsv1.ControllerRevision{Data: runtime.RawExtension{Raw: []byte(`{"spec":{}}`)}}
+
+		_, err := NewControllerRevisionForGenericKind(controllerRevision, GenericKind{Kind: "CloneSet"})
+		Expect(err).To(MatchError("revision data has no pod template at spec.template"))
+	})
+})
+
+func patchData(revision int) []byte {
+	return []byte(fmt.Sprintf(`{"spec":{"template":{"$patch":"replace","metadata":{"labels":{"app":"clone"}},"spec":{"containers":[{"name":"app","image":"app:%d"}]}}}}`, revision))
+}
+
+func controllerRevisionForGenericOwner(owner *unstructured.Unstructured, revision int64, data []byte) *appsv1.ControllerRevision {
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", owner.GetName(), revision),
+			Namespace: owner.GetNamespace(),
+			Labels:    map[string]string{"app": "clone"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: owner.GetAPIVersion(),
+				Kind:       owner.GetKind(),
+				Name:       owner.GetName(),
+				UID:        owner.GetUID(),
+				Controller: ptr.To(true),
+			}},
+		},
+		Revision: revision,
+		Data:     runtime.RawExtension{Raw: data},
+	}
+}
+
+var _ = Describe("SelectorPath", func() {
+	It("should return the configured selector path of generic kinds", func() {
+		Expect(SelectorPath(GenericHistory{Kind: GenericKind{SelectorPath: "spec.podSelector"}})).To(Equal("spec.podSelector"))
+		Expect(SelectorPath(GenericHistory{})).To(Equal("spec.selector"))
+	})
+
+	It("should return spec.selector for all other kinds", func() {
+		Expect(SelectorPath(DeploymentHistory{})).To(Equal("spec.selector"))
+	})
+})
@@ -16,7 +16,7 @@ import (
 )
 
 // SupportedKinds is a list of object kinds supported by this package.
-var SupportedKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+var SupportedKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "Rollout"}
 
 // ListRevisions returns a sorted revision history (ascending) of the given object.
 // This is a convenient shortcut for using For and calling History.ListRevisions.
@@ -65,6 +65,8 @@ func ForGroupKind(c client.Reader, gk schema.GroupKind) (History, error) {
 		return DeploymentHistory{Client: c}, nil
 	case gk.Group == appsv1.GroupName && gk.Kind == "StatefulSet":
 		return StatefulSetHistory{Client: c}, nil
+	case gk == RolloutGroupKind:
+		return RolloutHistory{Client: c}, nil
 	}
 
 	return nil, fmt.Errorf("%s is not supported", gk.String())
@@ -104,6 +106,24 @@ type Revision interface {
 	ReadyReplicas() int32
 }
 
+// MarkedRevision is an optional interface implemented by Revisions that can carry additional markers, e.g., stable or
+// canary for revisions of Argo Rollouts.
+type MarkedRevision interface {
+	Revision
+
+	// Markers returns the list of markers of this Revision.
+	Markers() []string
+}
+
+// ChangeCauseAnnotation is the annotation recording the cause of a change, e.g., the command that triggered a rollout.
+// It is copied from the workload object to its revision objects and is also shown by `kubectl rollout history`.
+const ChangeCauseAnnotation = "kubernetes.io/change-cause"
+
+// ChangeCause returns the change cause recorded on the given Revision's object, see ChangeCauseAnnotation.
+func ChangeCause(rev Revision) string {
+	return rev.Object().GetAnnotations()[ChangeCauseAnnotation]
+}
+
 // GetObjectKind implements runtime.Object.
 func (r Revisions) GetObjectKind() schema.ObjectKind {
 	if len(r) == 0 {
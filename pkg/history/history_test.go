@@ -191,6 +191,18 @@ var _ = Describe("Revisions", func() {
 	})
 })
 
+var _ = Describe("ChangeCause", func() {
+	It("should return the change cause annotation", func() {
+		rev := someRevision(1)
+		rev.Object().SetAnnotations(map[string]string{ChangeCauseAnnotation: "kubectl set image deploy/nginx nginx=nginx:1.26"})
+		Expect(ChangeCause(rev)).To(Equal("kubectl set image deploy/nginx nginx=nginx:1.26"))
+	})
+
+	It("should return an empty string if the annotation is not set", func() {
+		Expect(ChangeCause(someRevision(1))).To(BeEmpty())
+	})
+})
+
 var _ = Describe("Replicas", func() {
 	Describe("CountReplicas", func() {
 		var (
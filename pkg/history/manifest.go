@@ -0,0 +1,90 @@
+package history
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ Revision = &Manifest{}
+
+// Manifest is a Revision for a workload object read from a manifest, e.g., a local file that is about to be applied.
+// It is not part of the workload's history, i.e., it has no revision number and no replicas.
+type Manifest struct {
+	Obj      client.Object
+	Template *corev1.Pod
+
+	Replicas
+}
+
+// NewManifest transforms the given workload object (e.g., a Deployment, StatefulSet, or DaemonSet) to a Revision object
+// by extracting its pod template from spec.template.
+func NewManifest(obj client.Object) (*Manifest, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	templateField, ok, err := unstructured.NestedMap(content, "spec", "template")
+	if err != nil || !ok {
+		return nil, fmt.Errorf("%s has no pod template at spec.template", obj.GetName())
+	}
+
+	template := &corev1.PodTemplateSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(templateField, template); err != nil {
+		return nil, fmt.Errorf("error parsing pod template: %w", err)
+	}
+
+	return &Manifest{
+		Obj: obj.DeepCopyObject().(client.Object),
+		Template: &corev1.Pod{
+			ObjectMeta: template.ObjectMeta,
+			Spec:       template.Spec,
+		},
+	}, nil
+}
+
+// GetObjectKind implements runtime.Object.
+func (m *Manifest) GetObjectKind() schema.ObjectKind {
+	if m == nil || m.Obj == nil {
+		return &metav1.TypeMeta{}
+	}
+	return m.Obj.GetObjectKind()
+}
+
+// DeepCopyObject implements runtime.Object.
+func (m *Manifest) DeepCopyObject() runtime.Object {
+	if m == nil {
+		return nil
+	}
+
+	out := new(Manifest)
+	*out = *m
+	if m.Obj != nil {
+		out.Obj = m.Obj.DeepCopyObject().(client.Object)
+	}
+	out.Template = m.Template.DeepCopy()
+	return out
+}
+
+// Number returns 0, manifests are not part of the workload's history.
+func (m *Manifest) Number() int64 {
+	return 0
+}
+
+func (m *Manifest) Name() string {
+	return m.Obj.GetName()
+}
+
+func (m *Manifest) Object() client.Object {
+	return m.Obj
+}
+
+func (m *Manifest) PodTemplate() *corev1.Pod {
+	return m.Template
+}
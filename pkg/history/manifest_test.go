@@ -0,0 +1,43 @@
+package history_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("Manifest", func() {
+	Describe("NewManifest", func() {
+		It("should extract the pod template of the workload object", func() {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test"},
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "app"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "app", Image: "app:1"}},
+						},
+					},
+				},
+			}
+
+			manifest, err := NewManifest(deployment)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(manifest.Number()).To(BeZero())
+			Expect(manifest.Name()).To(Equal("app"))
+			Expect(manifest.Object()).To(Equal(deployment))
+			Expect(manifest.PodTemplate().Labels).To(Equal(map[string]string{"app": "app"}))
+			Expect(manifest.PodTemplate().Spec.Containers).To(ConsistOf(corev1.Container{Name: "app", Image: "app:1"}))
+		})
+
+		It("should fail if the object has no pod template", func() {
+			_, err := NewManifest(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}})
+			Expect(err).To(MatchError("config has no pod template at spec.template"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package history
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodMatchingRevision is an optional interface implemented by Revisions that know which Pods belong to them, e.g., the
+// Pods controlled by a ReplicaSet.
+type PodMatchingRevision interface {
+	Revision
+
+	// MatchesPod returns true if the given Pod belongs to this Revision.
+	MatchesPod(pod *corev1.Pod) bool
+}
+
+// RevisionPod is a Pod together with the Revision it belongs to.
+type RevisionPod struct {
+	Pod      *corev1.Pod
+	Revision Revision
+}
+
+// ListPods lists the Pods in the namespace of the given workload object that match its label selector at the given
+// dot-separated path (see SelectorPath). Use MatchPods for finding the Revision of each listed Pod.
+func ListPods(ctx context.Context, r client.Reader, obj client.Object, selectorPath string) (*corev1.PodList, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error converting %s to unstructured: %w", obj.GetName(), err)
+	}
+
+	labelSelector, ok, err := selectorAt(content, selectorPath)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing selector: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s has no selector at %s", obj.GetName(), selectorPath)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing selector: %w", err)
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(obj.GetNamespace()), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("error listing Pods: %w", err)
+	}
+
+	return podList, nil
+}
+
+// MatchPods finds the Revision of each Pod in the given list. Pods that don't belong to any of the given revisions
+// are skipped. The result is ordered like the given revisions, Pods of the same revision are sorted by name.
+func MatchPods(podList *corev1.PodList, revs Revisions) []RevisionPod {
+	var pods []RevisionPod
+
+	for _, rev := range revs {
+		matcher, ok := rev.(PodMatchingRevision)
+		if !ok {
+			continue
+		}
+
+		var revisionPods []RevisionPod
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if matcher.MatchesPod(pod) {
+				revisionPods = append(revisionPods, RevisionPod{Pod: pod, Revision: rev})
+			}
+		}
+
+		slices.SortFunc(revisionPods, func(a, b RevisionPod) int {
+			return cmp.Compare(a.Pod.Name, b.Pod.Name)
+		})
+		pods = append(pods, revisionPods...)
+	}
+
+	return pods
+}
+
+// sameController returns false if both objects have a controller reference and the references point to different
+// objects. Pods of a StatefulSet or DaemonSet are not controlled by a ControllerRevision but by the same object as the
+// ControllerRevision.
+func sameController(a, b metav1.Object) bool {
+	refA, refB := metav1.GetControllerOfNoCopy(a), metav1.GetControllerOfNoCopy(b)
+	if refA == nil || refB == nil {
+		return true
+	}
+	return refA.UID == refB.UID
+}
@@ -0,0 +1,206 @@
+package history_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("Pods", func() {
+	Describe("ReplicaSet#MatchesPod", func() {
+		var (
+			rev *ReplicaSet
+			pod *corev1.Pod
+		)
+
+		BeforeEach(func() {
+			replicaSet := &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app-1",
+					UID:         "rs-1",
+					Annotations: map[string]string{"deployment.kubernetes.io/revision": "1"},
+				},
+			}
+
+			var err error
+			rev, err = NewReplicaSet(replicaSet)
+			Expect(err).NotTo(HaveOccurred())
+
+			pod = podControlledBy("app-1-abc", "ReplicaSet", "app-1", "rs-1")
+		})
+
+		It("should match pods controlled by the ReplicaSet", func() {
+			Expect(rev.MatchesPod(pod)).To(BeTrue())
+		})
+
+		It("should not match pods controlled by another object", func() {
+			pod.OwnerReferences[0].UID = "other"
+			Expect(rev.MatchesPod(pod)).To(BeFalse())
+
+			Expect(rev.MatchesPod(podControlledBy("app-1-abc", "ReplicaSet", "app-2", "rs-1"))).To(BeFalse())
+		})
+
+		It("should not match pods without a controller", func() {
+			pod.OwnerReferences = nil
+			Expect(rev.MatchesPod(pod)).To(BeFalse())
+		})
+	})
+
+	Describe("ControllerRevision#MatchesPod", func() {
+		var (
+			controllerRevision *appsv1.ControllerRevision
+			rev                *ControllerRevision
+			pod                *corev1.Pod
+		)
+
+		BeforeEach(func() {
+			controllerRevision = &appsv1.ControllerRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "app-abc",
+					OwnerReferences: []metav1.OwnerReference{controllerRef("StatefulSet", "app", "sts")},
+				},
+				Revision: 1,
+				Data:     runtime.RawExtension{Object: &appsv1.StatefulSet{}},
+			}
+
+			var err error
+			rev, err = NewControllerRevisionForStatefulSet(controllerRevision)
+			Expect(err).NotTo(HaveOccurred())
+
+			pod = podControlledBy("app-0", "StatefulSet", "app", "sts")
+			pod.Labels = map[string]string{appsv1.StatefulSetRevisionLabel: "app-abc"}
+		})
+
+		It("should match pods with the revision label and the same controller", func() {
+			Expect(rev.MatchesPod(pod)).To(BeTrue())
+		})
+
+		It("should not match pods of another revision", func() {
+			pod.Labels[appsv1.StatefulSetRevisionLabel] = "app-def"
+			Expect(rev.MatchesPod(pod)).To(BeFalse())
+		})
+
+		It("should not match pods of another controller", func() {
+			pod.OwnerReferences[0].UID = "other"
+			Expect(rev.MatchesPod(pod)).To(BeFalse())
+		})
+
+		It("should not match pods if the ControllerRevision was not created by a constructor", func() {
+			Expect((&ControllerRevision{ControllerRevision: controllerRevision}).MatchesPod(pod)).To(BeFalse())
+		})
+	})
+
+	Describe("MatchPods", func() {
+		It("should return the pods grouped by revision", func() {
+			rev1, err := NewReplicaSet(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name: "app-1", UID: "rs-1", Annotations: map[string]string{"deployment.kubernetes.io/revision": "1"},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			rev2, err := NewReplicaSet(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name: "app-2", UID: "rs-2", Annotations: map[string]string{"deployment.kubernetes.io/revision": "2"},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+
+			podList := toPodList(
+				podControlledBy("app-2-b", "ReplicaSet", "app-2", "rs-2"),
+				podControlledBy("app-1-a", "ReplicaSet", "app-1", "rs-1"),
+				podControlledBy("other", "ReplicaSet", "other", "rs-3"),
+				podControlledBy("app-2-a", "ReplicaSet", "app-2", "rs-2"),
+			)
+
+			// revisions that don't implement PodMatchingRevision are skipped
+			pods := MatchPods(podList, Revisions{rev1, someRevision(3), rev2})
+			Expect(pods).To(HaveLen(3))
+			Expect(pods[0].Pod.Name).To(Equal("app-1-a"))
+			Expect(pods[0].Revision).To(BeIdenticalTo(rev1))
+			Expect(pods[1].Pod.Name).To(Equal("app-2-a"))
+			Expect(pods[1].Revision).To(BeIdenticalTo(rev2))
+			Expect(pods[2].Pod.Name).To(Equal("app-2-b"))
+			Expect(pods[2].Revision).To(BeIdenticalTo(rev2))
+		})
+	})
+})
+
+var _ = Describe("ListPods", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		newPod := func(namespace, name, app string) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": app}}}
+		}
+
+		fakeClient = fakeclient.NewClientBuilder().WithObjects(
+			newPod("default", "app-a", "app"),
+			newPod("default", "other-a", "other"),
+			newPod("other", "app-b", "app"),
+		).Build()
+	})
+
+	It("should list the pods matching the selector of the workload object", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+			},
+		}
+
+		podList, err := ListPods(ctx, fakeClient, deployment, "spec.selector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podList.Items).To(ConsistOf(HaveField("Name", "app-a")))
+	})
+
+	It("should read the selector at the given path", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"podSelector": map[string]any{"matchLabels": map[string]any{"app": "other"}},
+			},
+		}}
+		obj.SetNamespace("default")
+		obj.SetName("other")
+
+		podList, err := ListPods(ctx, fakeClient, obj, "spec.podSelector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podList.Items).To(ConsistOf(HaveField("Name", "other-a")))
+	})
+
+	It("should fail if the workload object has no selector", func() {
+		_, err := ListPods(ctx, fakeClient, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}}, "spec.selector")
+		Expect(err).To(MatchError("app has no selector at spec.selector"))
+	})
+})
+
+func controllerRef(kind, name string, uid types.UID) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "apps/v1",
+		Kind:       kind,
+		Name:       name,
+		UID:        uid,
+		Controller: ptr.To(true),
+	}
+}
+
+func podControlledBy(name, kind, ownerName string, uid types.UID) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{controllerRef(kind, ownerName, uid)},
+		},
+	}
+}
@@ -2,6 +2,8 @@ package history
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -12,21 +14,31 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var _ Revision = &ReplicaSet{}
+var (
+	_ Revision       = &ReplicaSet{}
+	_ MarkedRevision = &ReplicaSet{}
+	_ RoledRevision  = &ReplicaSet{}
 
-// ReplicaSet is a Revision of a Deployment.
+	_ PodMatchingRevision = &ReplicaSet{}
+)
+
+// ReplicaSet is a Revision of a Deployment or an Argo Rollout.
 type ReplicaSet struct {
-	number int64
+	number       int64
+	hashLabelKey string
+	markers      []string
+	role         Role
 
 	ReplicaSet *appsv1.ReplicaSet
 }
 
-// NewReplicaSet transforms the given ReplicaSet to a Revision object.
+// NewReplicaSet transforms the given ReplicaSet of a Deployment to a Revision object.
 func NewReplicaSet(replicaSet *appsv1.ReplicaSet) (*ReplicaSet, error) {
 	replicaSet = replicaSet.DeepCopy()
 
 	revision := &ReplicaSet{}
 	revision.ReplicaSet = replicaSet
+	revision.hashLabelKey = appsv1.DefaultDeploymentUniqueLabelKey
 
 	var err error
 	revision.number, err = deploymentutil.Revision(replicaSet)
@@ -37,6 +49,28 @@ func NewReplicaSet(replicaSet *appsv1.ReplicaSet) (*ReplicaSet, error) {
 	return revision, nil
 }
 
+// newRolloutReplicaSet transforms the given ReplicaSet of an Argo Rollout to a Revision object.
+func newRolloutReplicaSet(replicaSet *appsv1.ReplicaSet) (*ReplicaSet, error) {
+	replicaSet = replicaSet.DeepCopy()
+
+	revision := &ReplicaSet{}
+	revision.ReplicaSet = replicaSet
+	revision.hashLabelKey = RolloutPodTemplateHashLabel
+
+	v, ok := replicaSet.Annotations[RolloutRevisionAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("missing %s annotation", RolloutRevisionAnnotation)
+	}
+
+	var err error
+	revision.number, err = strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing revision: %w", err)
+	}
+
+	return revision, nil
+}
+
 // GetObjectKind implements runtime.Object.
 func (r *ReplicaSet) GetObjectKind() schema.ObjectKind {
 	if r == nil {
@@ -56,6 +90,7 @@ func (r *ReplicaSet) DeepCopyObject() runtime.Object {
 
 	out := new(ReplicaSet)
 	*out = *r
+	out.markers = slices.Clone(r.markers)
 	out.ReplicaSet = r.ReplicaSet.DeepCopy()
 	return out
 }
@@ -74,7 +109,7 @@ func (r *ReplicaSet) Object() client.Object {
 
 func (r *ReplicaSet) PodTemplate() *corev1.Pod {
 	t := r.ReplicaSet.Spec.Template.DeepCopy()
-	delete(t.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	delete(t.Labels, r.hashLabelKey)
 	return &corev1.Pod{
 		ObjectMeta: t.ObjectMeta,
 		Spec:       t.Spec,
@@ -88,3 +123,23 @@ func (r *ReplicaSet) CurrentReplicas() int32 {
 func (r *ReplicaSet) ReadyReplicas() int32 {
 	return r.ReplicaSet.Status.ReadyReplicas
 }
+
+// MatchesPod returns true if the given Pod is controlled by the ReplicaSet.
+func (r *ReplicaSet) MatchesPod(pod *corev1.Pod) bool {
+	ref := metav1.GetControllerOfNoCopy(pod)
+	return ref != nil && ref.Kind == "ReplicaSet" && ref.Name == r.ReplicaSet.Name && ref.UID == r.ReplicaSet.UID
+}
+
+// Markers returns the markers of the ReplicaSet, e.g., stable or canary for revisions of Argo Rollouts.
+func (r *ReplicaSet) Markers() []string {
+	return r.markers
+}
+
+// Role returns the role of the ReplicaSet in the rollout of its owner, see Role.
+func (r *ReplicaSet) Role() Role {
+	return r.role
+}
+
+func (r *ReplicaSet) setRole(role Role) {
+	r.role = role
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+// Role describes which part a Revision plays in the rollout of its workload object.
+type Role string
+
+const (
+	// RoleCurrent is the role of the revision that the workload is currently running. During a rollout, this is the
+	// revision that is being replaced (like the currentRevision of a StatefulSet). Once the rollout is complete, the
+	// rolled out revision is the current one.
+	RoleCurrent Role = "current"
+	// RoleUpdate is the role of the revision that is currently being rolled out (like the updateRevision of a
+	// StatefulSet).
+	RoleUpdate Role = "update"
+	// RoleOld is the role of all other revisions in the history.
+	RoleOld Role = "old"
+)
+
+// RoledRevision is an optional interface implemented by Revisions that know their Role in the rollout of the workload
+// object.
+type RoledRevision interface {
+	Revision
+
+	// Role returns the role of this Revision.
+	Role() Role
+}
+
+// RoleOf returns the Role of the given Revision or an empty string if it doesn't implement RoledRevision.
+func RoleOf(rev Revision) Role {
+	if roled, ok := rev.(RoledRevision); ok {
+		return roled.Role()
+	}
+	return ""
+}
+
+// ByRole finds the Revision with the given Role in a revision list.
+// If no rollout is in progress, the current revision is returned for RoleUpdate, i.e., the rolled out revision.
+func (r Revisions) ByRole(role Role) (Revision, error) {
+	for _, rev := range r {
+		if RoleOf(rev) == role {
+			return rev, nil
+		}
+	}
+
+	if role == RoleUpdate {
+		return r.ByRole(RoleCurrent)
+	}
+
+	return nil, fmt.Errorf("no %s revision found", role)
+}
+
+// roleSetter is implemented by Revisions of this package that store their Role.
+type roleSetter interface {
+	setRole(Role)
+}
+
+// assignRoles assigns a Role to each of the given sorted revisions. update is the revision that the workload's
+// controller rolls out (nil if unknown). current is the revision the workload is running. If current is nil, the newest
+// revision other than update that still has replicas is the current one. If there is no such revision, the rollout of
+// update is complete and update is the current revision.
+func assignRoles(revs Revisions, current, update Revision) {
+	if current == nil {
+		for i := len(revs) - 1; i >= 0; i-- {
+			if revs[i] != update && revs[i].CurrentReplicas() > 0 {
+				current = revs[i]
+				break
+			}
+		}
+	}
+	if current == nil {
+		current = update
+	}
+
+	for _, rev := range revs {
+		setter, ok := rev.(roleSetter)
+		if !ok {
+			continue
+		}
+
+		switch rev {
+		case current:
+			setter.setRole(RoleCurrent)
+		case update:
+			setter.setRole(RoleUpdate)
+		default:
+			setter.setRole(RoleOld)
+		}
+	}
+}
+
+// revisionByName returns the Revision with the given name or nil if there is none.
+func revisionByName(revs Revisions, name string) Revision {
+	if name == "" {
+		return nil
+	}
+
+	for _, rev := range revs {
+		if rev.Name() == name {
+			return rev
+		}
+	}
+	return nil
+}
+
+// revisionByTemplate returns the newest Revision with the given pod template or nil if there is none.
+func revisionByTemplate(revs Revisions, template *corev1.PodTemplateSpec) Revision {
+	pod := &corev1.Pod{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+
+	for i := len(revs) - 1; i >= 0; i-- {
+		if apiequality.Semantic.DeepEqual(revs[i].PodTemplate(), pod) {
+			return revs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package history
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RolloutRevisionAnnotation is the annotation on ReplicaSets of Argo Rollouts holding the revision number.
+	RolloutRevisionAnnotation = "rollout.argoproj.io/revision"
+	// RolloutPodTemplateHashLabel is the label added by the Argo Rollouts controller to ReplicaSets and Pods for
+	// differentiating revisions (similar to the pod-template-hash label of Deployments).
+	RolloutPodTemplateHashLabel = "rollouts-pod-template-hash"
+
+	// MarkerStable marks the stable revision of a canary Rollout.
+	MarkerStable = "stable"
+	// MarkerCanary marks the canary revision of a canary Rollout that is currently being rolled out.
+	MarkerCanary = "canary"
+	// MarkerActive marks the revision of a blue-green Rollout that is currently served by the active service.
+	MarkerActive = "active"
+	// MarkerPreview marks the revision of a blue-green Rollout that is currently served by the preview service.
+	MarkerPreview = "preview"
+)
+
+// RolloutGroupKind is the GroupKind of Argo Rollouts.
+var RolloutGroupKind = schema.GroupKind{Group: "argoproj.io", Kind: "Rollout"}
+
+var _ History = RolloutHistory{}
+
+// RolloutHistory implements the History interface for Argo Rollouts. It works on unstructured objects, so that no
+// dependency on the Argo Rollouts API types is required.
+type RolloutHistory struct {
+	Client client.Reader
+}
+
+func (r RolloutHistory) ListRevisions(ctx context.Context, obj client.Object) (Revisions, error) {
+	rollout, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected *unstructured.Unstructured, got %T", obj)
+	}
+	if gk := rollout.GroupVersionKind().GroupKind(); gk != RolloutGroupKind {
+		return nil, fmt.Errorf("expected %s, got %s", RolloutGroupKind.String(), gk.String())
+	}
+
+	selectorField, ok, err := unstructured.NestedMap(rollout.Object, "spec", "selector")
+	if err != nil {
+		return nil, fmt.Errorf("error reading Rollout selector: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("rollout %s has no selector", rollout.GetName())
+	}
+
+	labelSelector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorField, labelSelector); err != nil {
+		return nil, fmt.Errorf("error parsing Rollout selector: %w", err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Rollout selector: %w", err)
+	}
+
+	replicaSetList := &appsv1.ReplicaSetList{}
+	if err := r.Client.List(ctx, replicaSetList, client.InNamespace(rollout.GetNamespace()), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("error listing ReplicaSets: %w", err)
+	}
+
+	var (
+		revs            Revisions
+		current, update Revision
+	)
+	for _, replicaSet := range replicaSetList.Items {
+		if !metav1.IsControlledBy(&replicaSet, rollout) {
+			continue
+		}
+
+		revision, err := newRolloutReplicaSet(&replicaSet)
+		if err != nil {
+			return nil, fmt.Errorf("error converting ReplicaSet %s: %w", replicaSet.Name, err)
+		}
+		revision.markers = rolloutMarkers(rollout, replicaSet.Labels[RolloutPodTemplateHashLabel])
+
+		switch rolloutRole(rollout, replicaSet.Labels[RolloutPodTemplateHashLabel]) {
+		case RoleCurrent:
+			current = revision
+		case RoleUpdate:
+			update = revision
+		}
+
+		revs = append(revs, revision)
+	}
+
+	Sort(revs)
+	assignRoles(revs, current, update)
+	return revs, nil
+}
+
+// rolloutMarkers determines the markers of the revision with the given pod template hash from the Rollout's status.
+func rolloutMarkers(rollout *unstructured.Unstructured, hash string) []string {
+	if hash == "" {
+		return nil
+	}
+
+	var (
+		markers []string
+
+		stableHash, _  = nestedString(rollout, "status", "stableRS")
+		currentHash, _ = nestedString(rollout, "status", "currentPodHash")
+		activeHash, _  = nestedString(rollout, "status", "blueGreen", "activeSelector")
+		previewHash, _ = nestedString(rollout, "status", "blueGreen", "previewSelector")
+	)
+
+	_, isCanary, _ := unstructured.NestedMap(rollout.Object, "spec", "strategy", "canary")
+
+	if hash == stableHash {
+		markers = append(markers, MarkerStable)
+	}
+	if isCanary && hash == currentHash && hash != stableHash {
+		markers = append(markers, MarkerCanary)
+	}
+	if hash == activeHash {
+		markers = append(markers, MarkerActive)
+	}
+	if hash == previewHash && hash != activeHash {
+		markers = append(markers, MarkerPreview)
+	}
+
+	return markers
+}
+
+// rolloutRole determines the role of the revision with the given pod template hash from the Rollout's status. The
+// stable (or active) revision is the current one, the revision of the Rollout's pod template is the update revision
+// unless it is also the stable one.
+func rolloutRole(rollout *unstructured.Unstructured, hash string) Role {
+	if hash == "" {
+		return ""
+	}
+
+	stableHash, ok := nestedString(rollout, "status", "stableRS")
+	if !ok {
+		stableHash, _ = nestedString(rollout, "status", "blueGreen", "activeSelector")
+	}
+	currentHash, _ := nestedString(rollout, "status", "currentPodHash")
+
+	switch hash {
+	case stableHash:
+		return RoleCurrent
+	case currentHash:
+		return RoleUpdate
+	}
+	return ""
+}
+
+func nestedString(obj *unstructured.Unstructured, fields ...string) (string, bool) {
+	v, ok, err := unstructured.NestedString(obj.Object, fields...)
+	return v, ok && err == nil
+}
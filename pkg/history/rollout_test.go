@@ -0,0 +1,191 @@
+package history_test
+
+import (
+	"context"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("RolloutHistory", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClient = fakeclient.NewClientBuilder().Build()
+	})
+
+	Describe("initialization", func() {
+		It("should be constructable via ForGroupKind", func() {
+			history, err := ForGroupKind(fakeClient, RolloutGroupKind)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(BeAssignableToTypeOf(RolloutHistory{}))
+
+			h := history.(RolloutHistory)
+			Expect(h.Client).To(Equal(fakeClient))
+		})
+	})
+
+	Describe("ListRevisions", func() {
+		var (
+			history RolloutHistory
+
+			rollout *unstructured.Unstructured
+
+			replicaSet1, replicaSet2, replicaSet3 *appsv1.ReplicaSet
+		)
+
+		BeforeEach(func() {
+			history = RolloutHistory{
+				Client: fakeClient,
+			}
+
+			rollout = &unstructured.Unstructured{Object: map[string]any{
+				"spec": map[string]any{
+					"selector": map[string]any{
+						"matchLabels": map[string]any{"app": "rollout"},
+					},
+					"strategy": map[string]any{
+						"canary": map[string]any{},
+					},
+				},
+				"status": map[string]any{
+					"stableRS":       "hash-2",
+					"currentPodHash": "hash-3",
+				},
+			}}
+			rollout.SetGroupVersionKind(RolloutGroupKind.WithVersion("v1alpha1"))
+			rollout.SetName("rollout")
+			rollout.SetNamespace("test")
+			rollout.SetUID("rollout-uid")
+
+			replicaSet1 = replicaSetForRollout(rollout, 1)
+			Expect(fakeClient.Create(ctx, replicaSet1)).To(Succeed())
+			replicaSet3 = replicaSetForRollout(rollout, 3)
+			Expect(fakeClient.Create(ctx, replicaSet3)).To(Succeed())
+			replicaSet2 = replicaSetForRollout(rollout, 2)
+			Expect(fakeClient.Create(ctx, replicaSet2)).To(Succeed())
+
+			replicaSetUnrelated := replicaSetForRollout(rollout, 4)
+			replicaSetUnrelated.OwnerReferences[0].UID = "other"
+			Expect(fakeClient.Create(ctx, replicaSetUnrelated)).To(Succeed())
+		})
+
+		It("should fail for typed objects", func() {
+			_, err := history.ListRevisions(ctx, &appsv1.Deployment{})
+			Expect(err).To(MatchError(ContainSubstring("expected *unstructured.Unstructured")))
+		})
+
+		It("should fail for other kinds", func() {
+			rollout.SetKind("Other")
+			_, err := history.ListRevisions(ctx, rollout)
+			Expect(err).To(MatchError("expected Rollout.argoproj.io, got Other.argoproj.io"))
+		})
+
+		It("should fail for ReplicaSets without revision annotation", func() {
+			replicaSet4 := replicaSetForRollout(rollout, 4)
+			replicaSet4.Name = "rollout-no-revision"
+			delete(replicaSet4.Annotations, RolloutRevisionAnnotation)
+			Expect(fakeClient.Create(ctx, replicaSet4)).To(Succeed())
+
+			_, err := history.ListRevisions(ctx, rollout)
+			Expect(err).To(MatchError("error converting ReplicaSet rollout-no-revision: missing rollout.argoproj.io/revision annotation"))
+		})
+
+		It("should return a sorted list of the owned ReplicaSets", func() {
+			revs, err := history.ListRevisions(ctx, rollout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs).To(HaveLen(3))
+
+			Expect(revs[0].Number()).To(BeEquivalentTo(1))
+			Expect(revs[0].Object()).To(Equal(replicaSet1))
+			Expect(revs[1].Number()).To(BeEquivalentTo(2))
+			Expect(revs[1].Object()).To(Equal(replicaSet2))
+			Expect(revs[2].Number()).To(BeEquivalentTo(3))
+			Expect(revs[2].Object()).To(Equal(replicaSet3))
+		})
+
+		It("should strip the rollouts-pod-template-hash label from the pod template", func() {
+			revs, err := history.ListRevisions(ctx, rollout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs[0].PodTemplate().Labels).To(Equal(map[string]string{"app": "rollout"}))
+		})
+
+		It("should mark the stable and canary revisions", func() {
+			revs, err := history.ListRevisions(ctx, rollout)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(revs[0].(MarkedRevision).Markers()).To(BeEmpty())
+			Expect(revs[1].(MarkedRevision).Markers()).To(ConsistOf(MarkerStable))
+			Expect(revs[2].(MarkedRevision).Markers()).To(ConsistOf(MarkerCanary))
+		})
+
+		It("should mark the stable revision as current and the canary revision as update revision", func() {
+			revs, err := history.ListRevisions(ctx, rollout)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(RoleOf(revs[0])).To(Equal(RoleOld))
+			Expect(RoleOf(revs[1])).To(Equal(RoleCurrent))
+			Expect(RoleOf(revs[2])).To(Equal(RoleUpdate))
+		})
+
+		It("should mark the active and preview revisions", func() {
+			Expect(unstructured.SetNestedMap(rollout.Object, map[string]any{"blueGreen": map[string]any{}}, "spec", "strategy")).To(Succeed())
+			Expect(unstructured.SetNestedField(rollout.Object, map[string]any{
+				"activeSelector":  "hash-2",
+				"previewSelector": "hash-3",
+			}, "status", "blueGreen")).To(Succeed())
+
+			revs, err := history.ListRevisions(ctx, rollout)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(revs[0].(MarkedRevision).Markers()).To(BeEmpty())
+			Expect(revs[1].(MarkedRevision).Markers()).To(ConsistOf(MarkerStable, MarkerActive))
+			Expect(revs[2].(MarkedRevision).Markers()).To(ConsistOf(MarkerPreview))
+		})
+	})
+})
+
+func replicaSetForRollout(rollout *unstructured.Unstructured, revision int64) *appsv1.ReplicaSet {
+	labels := map[string]string{
+		"app":                       "rollout",
+		RolloutPodTemplateHashLabel: "hash-" + strconv.FormatInt(revision, 10),
+	}
+
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rollout.GetName() + "-" + strconv.FormatInt(revision, 10),
+			Namespace: rollout.GetNamespace(),
+			Labels:    labels,
+			Annotations: map[string]string{
+				RolloutRevisionAnnotation: strconv.FormatInt(revision, 10),
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: rollout.GetAPIVersion(),
+				Kind:       rollout.GetKind(),
+				Name:       rollout.GetName(),
+				UID:        rollout.GetUID(),
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			},
+		},
+	}
+}
@@ -1,9 +1,12 @@
 package history
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -29,3 +32,22 @@ func init() {
 	metav1.AddToGroupVersion(Scheme, schema.GroupVersion{Version: "v1"})
 	utilruntime.Must(AddToScheme(Scheme))
 }
+
+// ToTyped converts the given unstructured object to the corresponding typed object if its kind is registered in Scheme.
+// All other objects are returned unchanged, e.g., Argo Rollouts are handled as unstructured objects.
+func ToTyped(obj runtime.Object) (runtime.Object, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || !Scheme.Recognizes(u.GroupVersionKind()) {
+		return obj, nil
+	}
+
+	typed, err := Scheme.New(u.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+		return nil, fmt.Errorf("error converting %s: %w", u.GroupVersionKind().Kind, err)
+	}
+	return typed, nil
+}
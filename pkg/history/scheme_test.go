@@ -0,0 +1,40 @@
+package history_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("ToTyped", func() {
+	It("should convert objects of kinds registered in the scheme", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{"replicas": int64(2)},
+		}}
+		obj.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		obj.SetName("app")
+
+		typed, err := ToTyped(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(typed).To(BeAssignableToTypeOf(&appsv1.Deployment{}))
+
+		deployment := typed.(*appsv1.Deployment)
+		Expect(deployment.Name).To(Equal("app"))
+		Expect(*deployment.Spec.Replicas).To(BeEquivalentTo(2))
+	})
+
+	It("should return objects of other kinds unchanged", func() {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(RolloutGroupKind.WithVersion("v1alpha1"))
+
+		Expect(ToTyped(obj)).To(BeIdenticalTo(obj))
+	})
+
+	It("should return typed objects unchanged", func() {
+		obj := &appsv1.Deployment{}
+		Expect(ToTyped(obj)).To(BeIdenticalTo(obj))
+	})
+})
@@ -0,0 +1,293 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// Selector identifies a single Revision in a revision history. Use ParseSelector for parsing a Selector from its string
+// representation, e.g., given via the --revision flag.
+type Selector interface {
+	fmt.Stringer
+
+	// Select finds the selected Revision in a sorted revision list.
+	Select(revs Revisions) (Revision, error)
+}
+
+const (
+	// SelectorLatest selects the latest revision, see NumberSelector.
+	SelectorLatest = "latest"
+
+	selectorImagePrefix  = "image="
+	selectorCommitPrefix = "commit:"
+
+	// RangeSeparator separates the two ends of a revision range, see ParseRange.
+	RangeSeparator = ".."
+)
+
+// ParseSelector parses a Selector from the given string. The following syntax is supported:
+//
+//   - a revision number, e.g., 3, or a negative number relative to the latest revision, e.g., -2 (see NumberSelector)
+//   - latest for the latest revision
+//   - current or update for the revision that is currently running or being rolled out (see RoleSelector)
+//   - @{2h} for the revision that was live 2 hours ago, or @{2006-01-02T15:04:05Z} for the revision that was live at the
+//     given time (see TimeSelector)
+//   - image=nginx:1.25 for the newest revision running the given image (see ImageSelector)
+//   - commit:3f2a9c1 for the newest revision deployed from the given commit (see CommitSelector)
+//   - the name or pod-template-hash of a revision, e.g., nginx-5d8f7c or 5d8f7c (see NameSelector)
+func ParseSelector(s string) (Selector, error) {
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("revision must not be empty")
+	case s == SelectorLatest:
+		return NumberSelector(-1), nil
+	case s == string(RoleCurrent) || s == string(RoleUpdate):
+		return RoleSelector(s), nil
+	case strings.HasPrefix(s, "@{") && strings.HasSuffix(s, "}"):
+		return parseTimeSelector(strings.TrimSuffix(strings.TrimPrefix(s, "@{"), "}"))
+	case strings.HasPrefix(s, selectorImagePrefix):
+		image := strings.TrimPrefix(s, selectorImagePrefix)
+		if image == "" {
+			return nil, fmt.Errorf("invalid revision %q: image must not be empty", s)
+		}
+		return ImageSelector(image), nil
+	case strings.HasPrefix(s, selectorCommitPrefix):
+		commit := strings.TrimPrefix(s, selectorCommitPrefix)
+		if commit == "" {
+			return nil, fmt.Errorf("invalid revision %q: commit must not be empty", s)
+		}
+		return CommitSelector(strings.ToLower(commit)), nil
+	}
+
+	if number, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if number == 0 {
+			return nil, fmt.Errorf("invalid revision number 0")
+		}
+		return NumberSelector(number), nil
+	}
+
+	return NameSelector(s), nil
+}
+
+func parseTimeSelector(s string) (Selector, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return TimeSelector{Time: time.Now().Add(-d)}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision %q: expected a duration (e.g., @{2h}) or a RFC3339 time", "@{"+s+"}")
+	}
+	return TimeSelector{Time: t}, nil
+}
+
+// NumberSelector selects a Revision by its revision number. -1 denotes the latest revision, -2 the previous one, etc.
+// See Revisions.ByNumber.
+type NumberSelector int64
+
+func (n NumberSelector) String() string {
+	return strconv.FormatInt(int64(n), 10)
+}
+
+func (n NumberSelector) Select(revs Revisions) (Revision, error) {
+	return revs.ByNumber(int64(n))
+}
+
+// RoleSelector selects a Revision by its Role. See Revisions.ByRole.
+type RoleSelector Role
+
+func (r RoleSelector) String() string {
+	return string(r)
+}
+
+func (r RoleSelector) Select(revs Revisions) (Revision, error) {
+	return revs.ByRole(Role(r))
+}
+
+// NameSelector selects a Revision by the name of the revision object or by its pod-template-hash (or
+// controller-revision-hash) label.
+type NameSelector string
+
+// hashLabelKeys are the labels of revision objects that hold the hash of the revision's pod template.
+var hashLabelKeys = []string{
+	appsv1.DefaultDeploymentUniqueLabelKey,
+	appsv1.ControllerRevisionHashLabelKey,
+	RolloutPodTemplateHashLabel,
+}
+
+func (n NameSelector) String() string {
+	return string(n)
+}
+
+func (n NameSelector) Select(revs Revisions) (Revision, error) {
+	for _, rev := range revs {
+		if rev.Name() == string(n) {
+			return rev, nil
+		}
+	}
+
+	for _, rev := range revs {
+		labels := rev.Object().GetLabels()
+		for _, key := range hashLabelKeys {
+			if labels[key] == string(n) {
+				return rev, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("revision %q not found", string(n))
+}
+
+// TimeSelector selects the Revision that was live at the given time, i.e., the newest revision that was created before
+// the given time. Note that this is based on the creation timestamps of the revision objects only. I.e., it doesn't
+// consider how long the rollout of a revision took.
+type TimeSelector struct {
+	Time time.Time
+}
+
+func (t TimeSelector) String() string {
+	return "@{" + t.Time.Format(time.RFC3339) + "}"
+}
+
+func (t TimeSelector) Select(revs Revisions) (Revision, error) {
+	var selected Revision
+	for _, rev := range revs {
+		if rev.Object().GetCreationTimestamp().Time.After(t.Time) {
+			continue
+		}
+		if selected == nil || rev.Object().GetCreationTimestamp().Time.After(selected.Object().GetCreationTimestamp().Time) {
+			selected = rev
+		}
+	}
+
+	if selected == nil {
+		return nil, fmt.Errorf("no revision found that was live at %s", t.Time.Format(time.RFC3339))
+	}
+	return selected, nil
+}
+
+// ImageSelector selects the newest Revision that runs the given image in any of its containers or init containers.
+type ImageSelector string
+
+func (i ImageSelector) String() string {
+	return selectorImagePrefix + string(i)
+}
+
+func (i ImageSelector) Select(revs Revisions) (Revision, error) {
+	for j := len(revs) - 1; j >= 0; j-- {
+		spec := revs[j].PodTemplate().Spec
+		for _, container := range append(spec.InitContainers, spec.Containers...) {
+			if container.Image == string(i) {
+				return revs[j], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no revision found with image %q", string(i))
+}
+
+// CommitSelector selects the newest Revision that was deployed from the given commit, see SourceOf. Abbreviated commit
+// SHAs are supported.
+type CommitSelector string
+
+func (c CommitSelector) String() string {
+	return selectorCommitPrefix + string(c)
+}
+
+func (c CommitSelector) Select(revs Revisions) (Revision, error) {
+	for j := len(revs) - 1; j >= 0; j-- {
+		source, ok := SourceOf(revs[j])
+		if !ok {
+			continue
+		}
+		if commit := source.Commit(); commit != "" && strings.HasPrefix(commit, string(c)) {
+			return revs[j], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no revision found deployed from commit %q", string(c))
+}
+
+// Range selects all revisions between two selected revisions, including both ends. A nil selector denotes the oldest
+// (From) or latest (To) revision respectively.
+type Range struct {
+	From, To Selector
+}
+
+// IsRange returns true if the given string specifies a revision range, see ParseRange.
+func IsRange(s string) bool {
+	return strings.Contains(s, RangeSeparator)
+}
+
+// ParseRange parses a Range from the given string consisting of two selectors (see ParseSelector) separated by "..",
+// e.g., 3..7 or @{168h}..latest. Either end can be omitted, e.g., 3.. selects revision 3 and all later revisions.
+func ParseRange(s string) (*Range, error) {
+	from, to, ok := strings.Cut(s, RangeSeparator)
+	if !ok {
+		return nil, fmt.Errorf("invalid revision range %q: expected <from>..<to>", s)
+	}
+
+	r := &Range{}
+	var err error
+	if from != "" {
+		if r.From, err = ParseSelector(from); err != nil {
+			return nil, fmt.Errorf("invalid revision range %q: %w", s, err)
+		}
+	}
+	if to != "" {
+		if r.To, err = ParseSelector(to); err != nil {
+			return nil, fmt.Errorf("invalid revision range %q: %w", s, err)
+		}
+	}
+
+	return r, nil
+}
+
+func (r Range) String() string {
+	var from, to string
+	if r.From != nil {
+		from = r.From.String()
+	}
+	if r.To != nil {
+		to = r.To.String()
+	}
+	return from + RangeSeparator + to
+}
+
+// Select returns all revisions between the selected revisions in a sorted revision list. If the ends of the range are
+// given in reverse order, they are swapped.
+func (r Range) Select(revs Revisions) (Revisions, error) {
+	if len(revs) == 0 {
+		return nil, fmt.Errorf("no revisions found")
+	}
+
+	from, to := revs[0], revs[len(revs)-1]
+
+	var err error
+	if r.From != nil {
+		if from, err = r.From.Select(revs); err != nil {
+			return nil, err
+		}
+	}
+	if r.To != nil {
+		if to, err = r.To.Select(revs); err != nil {
+			return nil, err
+		}
+	}
+
+	if from.Number() > to.Number() {
+		from, to = to, from
+	}
+
+	var selected Revisions
+	for _, rev := range revs {
+		if rev.Number() >= from.Number() && rev.Number() <= to.Number() {
+			selected = append(selected, rev)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,191 @@
+package history_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+)
+
+var _ = Describe("Selector", func() {
+	Describe("ParseSelector", func() {
+		It("should parse revision numbers", func() {
+			Expect(ParseSelector("3")).To(Equal(NumberSelector(3)))
+			Expect(ParseSelector("-2")).To(Equal(NumberSelector(-2)))
+			Expect(ParseSelector("latest")).To(Equal(NumberSelector(-1)))
+		})
+
+		It("should parse roles", func() {
+			Expect(ParseSelector("current")).To(Equal(RoleSelector(RoleCurrent)))
+			Expect(ParseSelector("update")).To(Equal(RoleSelector(RoleUpdate)))
+		})
+
+		It("should parse durations relative to now", func() {
+			selector, err := ParseSelector("@{2h}")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector).To(BeAssignableToTypeOf(TimeSelector{}))
+			Expect(selector.(TimeSelector).Time).To(BeTemporally("~", time.Now().Add(-2*time.Hour), time.Minute))
+		})
+
+		It("should parse absolute times", func() {
+			Expect(ParseSelector("@{2024-01-02T15:04:05Z}")).To(Equal(TimeSelector{Time: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}))
+		})
+
+		It("should parse images", func() {
+			Expect(ParseSelector("image=nginx:1.25")).To(Equal(ImageSelector("nginx:1.25")))
+		})
+
+		It("should parse commits", func() {
+			Expect(ParseSelector("commit:3F2A9C1")).To(Equal(CommitSelector("3f2a9c1")))
+		})
+
+		It("should parse names", func() {
+			Expect(ParseSelector("nginx-5d8f7c")).To(Equal(NameSelector("nginx-5d8f7c")))
+		})
+
+		It("should fail for invalid selectors", func() {
+			Expect(ParseSelector("")).Error().To(MatchError("revision must not be empty"))
+			Expect(ParseSelector("0")).Error().To(MatchError("invalid revision number 0"))
+			Expect(ParseSelector("image=")).Error().To(MatchError(`invalid revision "image=": image must not be empty`))
+			Expect(ParseSelector("commit:")).Error().To(MatchError(`invalid revision "commit:": commit must not be empty`))
+			Expect(ParseSelector("@{foo}")).Error().To(MatchError(ContainSubstring(`invalid revision "@{foo}"`)))
+		})
+	})
+
+	Describe("#Select", func() {
+		var (
+			now  time.Time
+			revs Revisions
+		)
+
+		BeforeEach(func() {
+			now = time.Now()
+			revs = Revisions{
+				selectorRevision(1, now.Add(-3*time.Hour), "nginx:1.25"),
+				selectorRevision(2, now.Add(-90*time.Minute), "nginx:1.26"),
+				selectorRevision(3, now.Add(-time.Minute), "nginx:1.25"),
+			}
+		})
+
+		It("should select revisions by number", func() {
+			Expect(NumberSelector(2).Select(revs)).To(haveNumber(2))
+			Expect(NumberSelector(-1).Select(revs)).To(haveNumber(3))
+		})
+
+		It("should fail if no revision has the selected role", func() {
+			Expect(RoleSelector(RoleCurrent).Select(revs)).Error().To(MatchError("no current revision found"))
+		})
+
+		It("should select revisions by name", func() {
+			Expect(NameSelector("nginx-2").Select(revs)).To(haveNumber(2))
+		})
+
+		It("should select revisions by pod-template-hash", func() {
+			Expect(NameSelector("hash-2").Select(revs)).To(haveNumber(2))
+		})
+
+		It("should fail if no revision has the selected name", func() {
+			Expect(NameSelector("foo").Select(revs)).Error().To(MatchError(`revision "foo" not found`))
+		})
+
+		It("should select the revision that was live at the given time", func() {
+			Expect(TimeSelector{Time: now.Add(-2 * time.Hour)}.Select(revs)).To(haveNumber(1))
+			Expect(TimeSelector{Time: now.Add(-time.Hour)}.Select(revs)).To(haveNumber(2))
+			Expect(TimeSelector{Time: now}.Select(revs)).To(haveNumber(3))
+		})
+
+		It("should fail if no revision was live at the given time", func() {
+			Expect(TimeSelector{Time: now.Add(-4 * time.Hour)}.Select(revs)).Error().To(MatchError(ContainSubstring("no revision found that was live at")))
+		})
+
+		It("should select the newest revision running the given image", func() {
+			Expect(ImageSelector("nginx:1.25").Select(revs)).To(haveNumber(3))
+			Expect(ImageSelector("nginx:1.26").Select(revs)).To(haveNumber(2))
+		})
+
+		It("should fail if no revision runs the given image", func() {
+			Expect(ImageSelector("nginx:1.27").Select(revs)).Error().To(MatchError(`no revision found with image "nginx:1.27"`))
+		})
+
+		It("should select the newest revision deployed from the given commit", func() {
+			revs[0].PodTemplate().Annotations = map[string]string{SourceCommitAnnotation: "3f2a9c1e5b"}
+			revs[1].PodTemplate().Annotations = map[string]string{SourceCommitAnnotation: "9b1e04d7aa"}
+			revs[2].PodTemplate().Annotations = map[string]string{SourceCommitAnnotation: "3f2a9c1e5b"}
+
+			Expect(CommitSelector("3f2a9c1").Select(revs)).To(haveNumber(3))
+			Expect(CommitSelector("9b1e04d7aa").Select(revs)).To(haveNumber(2))
+		})
+
+		It("should fail if no revision was deployed from the given commit", func() {
+			Expect(CommitSelector("3f2a9c1").Select(revs)).Error().To(MatchError(`no revision found deployed from commit "3f2a9c1"`))
+		})
+	})
+})
+
+var _ = Describe("Range", func() {
+	Describe("ParseRange", func() {
+		It("should parse both ends", func() {
+			Expect(ParseRange("3..7")).To(Equal(&Range{From: NumberSelector(3), To: NumberSelector(7)}))
+			Expect(ParseRange("current..latest")).To(Equal(&Range{From: RoleSelector(RoleCurrent), To: NumberSelector(-1)}))
+		})
+
+		It("should allow omitting either end", func() {
+			Expect(ParseRange("3..")).To(Equal(&Range{From: NumberSelector(3)}))
+			Expect(ParseRange("..-2")).To(Equal(&Range{To: NumberSelector(-2)}))
+		})
+
+		It("should fail for invalid ranges", func() {
+			Expect(ParseRange("3")).Error().To(MatchError(`invalid revision range "3": expected <from>..<to>`))
+			Expect(ParseRange("0..3")).Error().To(MatchError(`invalid revision range "0..3": invalid revision number 0`))
+		})
+	})
+
+	Describe("#Select", func() {
+		var revs Revisions
+
+		BeforeEach(func() {
+			now := time.Now()
+			revs = Revisions{
+				selectorRevision(2, now, "nginx:1.25"),
+				selectorRevision(3, now, "nginx:1.26"),
+				selectorRevision(5, now, "nginx:1.27"),
+				selectorRevision(6, now, "nginx:1.28"),
+			}
+		})
+
+		It("should select all revisions between both ends", func() {
+			Expect(Range{From: NumberSelector(3), To: NumberSelector(6)}.Select(revs)).To(Equal(revs[1:]))
+			Expect(Range{From: NumberSelector(2), To: NumberSelector(-2)}.Select(revs)).To(Equal(revs[:3]))
+		})
+
+		It("should swap reversed ends", func() {
+			Expect(Range{From: NumberSelector(5), To: NumberSelector(2)}.Select(revs)).To(Equal(revs[:3]))
+		})
+
+		It("should default to the oldest and latest revision", func() {
+			Expect(Range{From: NumberSelector(5)}.Select(revs)).To(Equal(revs[2:]))
+			Expect(Range{To: NumberSelector(3)}.Select(revs)).To(Equal(revs[:2]))
+			Expect(Range{}.Select(revs)).To(Equal(revs))
+		})
+
+		It("should fail if an end is not found", func() {
+			Expect(Range{From: NumberSelector(4)}.Select(revs)).Error().To(MatchError("revision 4 not found"))
+		})
+	})
+})
+
+func selectorRevision(num int64, created time.Time, image string) Revision {
+	rev := someRevision(num).(*fake.Revision)
+	rev.Obj.SetName(fmt.Sprintf("nginx-%d", num))
+	rev.Obj.SetCreationTimestamp(metav1.NewTime(created))
+	rev.Obj.SetLabels(map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: fmt.Sprintf("hash-%d", num)})
+	rev.Template = &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}}
+	return rev
+}
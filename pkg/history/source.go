@@ -0,0 +1,212 @@
+package history
+
+import (
+	"strings"
+)
+
+const (
+	// SourceCommitAnnotation is the annotation on pod templates (or revision objects) holding the commit that a revision
+	// was deployed from. It is not set by any GitOps tool itself but can be propagated by users, e.g., via a kustomize
+	// patch or a CI pipeline.
+	SourceCommitAnnotation = "gitops/commit"
+	// SourceRepositoryAnnotation is the annotation holding the repository that a revision was deployed from, see
+	// SourceCommitAnnotation.
+	SourceRepositoryAnnotation = "gitops/repository"
+	// SourcePathAnnotation is the annotation holding the path in the repository that a revision was deployed from, see
+	// SourceCommitAnnotation.
+	SourcePathAnnotation = "gitops/path"
+
+	// ArgoCDTrackingIDAnnotation is the annotation that Argo CD adds to all objects of an Application when using
+	// annotation-based resource tracking. The format is `<application>:<group>/<kind>:<namespace>/<name>`.
+	ArgoCDTrackingIDAnnotation = "argocd.argoproj.io/tracking-id"
+
+	// FluxKustomizationNameLabel is the label that Flux adds to all objects applied by a Kustomization with the
+	// Kustomization's name.
+	FluxKustomizationNameLabel = "kustomize.toolkit.fluxcd.io/name"
+	// FluxKustomizationNamespaceLabel is the label that Flux adds to all objects applied by a Kustomization with the
+	// Kustomization's namespace.
+	FluxKustomizationNamespaceLabel = "kustomize.toolkit.fluxcd.io/namespace"
+	// FluxRevisionAnnotation is the annotation holding the source revision that Flux applied, e.g.,
+	// `main@sha1:<commit>`. Flux doesn't add it by default, but it can be propagated via the Kustomization's
+	// commonMetadata.
+	FluxRevisionAnnotation = "kustomize.toolkit.fluxcd.io/revision"
+
+	// ToolArgoCD identifies Argo CD in Source.Tool.
+	ToolArgoCD = "argocd"
+	// ToolFlux identifies Flux in Source.Tool.
+	ToolFlux = "flux"
+)
+
+// Source references the GitOps source that a Revision was deployed from. All fields are optional, see SourceOf.
+type Source struct {
+	// Tool is the GitOps tool that deployed the revision, e.g., argocd or flux.
+	Tool string
+	// Application is the name of the Argo CD Application or the namespace/name of the Flux Kustomization that deployed
+	// the revision.
+	Application string
+	// Repository is the repository that the revision was deployed from.
+	Repository string
+	// Path is the path in the repository that the revision was deployed from.
+	Path string
+	// Revision is the source revision that the revision was deployed from, e.g., a commit SHA or a Flux revision like
+	// `main@sha1:<commit>`.
+	Revision string
+}
+
+// IsEmpty returns true if no field of the Source is set.
+func (s Source) IsEmpty() bool {
+	return s == Source{}
+}
+
+// Location returns a short description of where the revision was deployed from: the repository and path if known,
+// otherwise the tool and application, e.g., `argocd:nginx`.
+func (s Source) Location() string {
+	if s.Repository != "" {
+		if s.Path != "" {
+			return strings.TrimSuffix(s.Repository, "/") + "/" + strings.TrimPrefix(s.Path, "/")
+		}
+		return s.Repository
+	}
+
+	if s.Application != "" && s.Tool != "" {
+		return s.Tool + ":" + s.Application
+	}
+	return s.Application
+}
+
+// Commit returns the commit SHA of the source revision, e.g., `<commit>` for `main@sha1:<commit>` (Flux) or
+// `main/<commit>` (older Flux versions).
+func (s Source) Commit() string {
+	commit := s.Revision
+	if i := strings.LastIndex(commit, "@"); i >= 0 {
+		commit = commit[i+1:]
+	}
+	if i := strings.LastIndex(commit, ":"); i >= 0 {
+		commit = commit[i+1:]
+	}
+	if i := strings.LastIndex(commit, "/"); i >= 0 {
+		commit = commit[i+1:]
+	}
+	return strings.ToLower(commit)
+}
+
+// ShortCommit returns the commit SHA of the source revision abbreviated to 7 characters like `git log --oneline`.
+func (s Source) ShortCommit() string {
+	commit := s.Commit()
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// SourceExtractor extracts the (partial) Source of a Revision, e.g., from annotations of the revision's pod template or
+// object.
+type SourceExtractor interface {
+	ExtractSource(rev Revision) Source
+}
+
+// SourceExtractorFunc is a function implementing SourceExtractor.
+type SourceExtractorFunc func(rev Revision) Source
+
+func (f SourceExtractorFunc) ExtractSource(rev Revision) Source {
+	return f(rev)
+}
+
+// SourceExtractors is the list of extractors used by SourceOf. Additional extractors can be added for supporting other
+// tools or conventions.
+var SourceExtractors = []SourceExtractor{
+	AnnotationSourceExtractor{
+		CommitKey:     SourceCommitAnnotation,
+		RepositoryKey: SourceRepositoryAnnotation,
+		PathKey:       SourcePathAnnotation,
+	},
+	SourceExtractorFunc(ArgoCDSource),
+	SourceExtractorFunc(FluxSource),
+}
+
+// SourceOf returns the Source of the given Revision by merging the results of all SourceExtractors. For each field, the
+// first non-empty value wins. ok is false if none of the extractors found any source information.
+func SourceOf(rev Revision) (source Source, ok bool) {
+	for _, extractor := range SourceExtractors {
+		s := extractor.ExtractSource(rev)
+		source.Tool = firstNonEmpty(source.Tool, s.Tool)
+		source.Application = firstNonEmpty(source.Application, s.Application)
+		source.Repository = firstNonEmpty(source.Repository, s.Repository)
+		source.Path = firstNonEmpty(source.Path, s.Path)
+		source.Revision = firstNonEmpty(source.Revision, s.Revision)
+	}
+	return source, !source.IsEmpty()
+}
+
+// AnnotationSourceExtractor extracts the Source from the given annotation keys. Keys can be left empty for disabling
+// the corresponding field.
+type AnnotationSourceExtractor struct {
+	CommitKey, RepositoryKey, PathKey string
+}
+
+func (a AnnotationSourceExtractor) ExtractSource(rev Revision) Source {
+	return Source{
+		Repository: metadataValue(rev, a.RepositoryKey),
+		Path:       metadataValue(rev, a.PathKey),
+		Revision:   metadataValue(rev, a.CommitKey),
+	}
+}
+
+// ArgoCDSource extracts the Argo CD Application from the tracking-id annotation.
+func ArgoCDSource(rev Revision) Source {
+	trackingID := metadataValue(rev, ArgoCDTrackingIDAnnotation)
+	application, _, ok := strings.Cut(trackingID, ":")
+	if !ok || application == "" {
+		return Source{}
+	}
+	return Source{Tool: ToolArgoCD, Application: application}
+}
+
+// FluxSource extracts the Flux Kustomization from the kustomize.toolkit.fluxcd.io labels and the applied revision from
+// the kustomize.toolkit.fluxcd.io/revision annotation.
+func FluxSource(rev Revision) Source {
+	name := metadataValue(rev, FluxKustomizationNameLabel)
+	if name == "" {
+		return Source{}
+	}
+
+	source := Source{Tool: ToolFlux, Application: name, Revision: metadataValue(rev, FluxRevisionAnnotation)}
+	if namespace := metadataValue(rev, FluxKustomizationNamespaceLabel); namespace != "" {
+		source.Application = namespace + "/" + name
+	}
+	return source
+}
+
+// metadataValue returns the value of the given key from the annotations or labels of the revision's pod template, or
+// from the annotations or labels of the revision object otherwise. The pod template takes precedence because it
+// reflects what the revision actually runs, while annotations of the revision object might be copied from the workload
+// object on every update (e.g., for ReplicaSets).
+func metadataValue(rev Revision, key string) string {
+	if key == "" {
+		return ""
+	}
+
+	var metadata []map[string]string
+	if template := rev.PodTemplate(); template != nil {
+		metadata = append(metadata, template.GetAnnotations(), template.GetLabels())
+	}
+	if obj := rev.Object(); obj != nil {
+		metadata = append(metadata, obj.GetAnnotations(), obj.GetLabels())
+	}
+
+	for _, m := range metadata {
+		if value := m[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
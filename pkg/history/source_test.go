@@ -0,0 +1,131 @@
+package history_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+)
+
+var _ = Describe("Source", func() {
+	Describe("SourceOf", func() {
+		It("should extract the source from pod template annotations", func() {
+			rev := sourceRevision(map[string]string{
+				SourceCommitAnnotation:     "3f2a9c1e5b",
+				SourceRepositoryAnnotation: "https://github.com/acme/apps",
+				SourcePathAnnotation:       "nginx/overlay",
+			}, nil)
+
+			Expect(sourceOf(rev)).To(Equal(Source{
+				Repository: "https://github.com/acme/apps",
+				Path:       "nginx/overlay",
+				Revision:   "3f2a9c1e5b",
+			}))
+		})
+
+		It("should prefer the pod template over the revision object", func() {
+			rev := sourceRevision(
+				map[string]string{SourceCommitAnnotation: "3f2a9c1e5b"},
+				map[string]string{SourceCommitAnnotation: "9b1e04d7aa", SourceRepositoryAnnotation: "https://github.com/acme/apps"},
+			)
+
+			Expect(sourceOf(rev)).To(Equal(Source{
+				Repository: "https://github.com/acme/apps",
+				Revision:   "3f2a9c1e5b",
+			}))
+		})
+
+		It("should extract the Argo CD Application from the tracking-id", func() {
+			rev := sourceRevision(map[string]string{SourceCommitAnnotation: "3f2a9c1e5b"}, map[string]string{
+				ArgoCDTrackingIDAnnotation: "nginx:apps/Deployment:default/nginx",
+			})
+
+			Expect(sourceOf(rev)).To(Equal(Source{
+				Tool:        ToolArgoCD,
+				Application: "nginx",
+				Revision:    "3f2a9c1e5b",
+			}))
+		})
+
+		It("should extract the Flux Kustomization and revision", func() {
+			rev := sourceRevision(map[string]string{
+				FluxKustomizationNameLabel:      "apps",
+				FluxKustomizationNamespaceLabel: "flux-system",
+				FluxRevisionAnnotation:          "main@sha1:3f2a9c1e5b",
+			}, nil)
+
+			Expect(sourceOf(rev)).To(Equal(Source{
+				Tool:        ToolFlux,
+				Application: "flux-system/apps",
+				Revision:    "main@sha1:3f2a9c1e5b",
+			}))
+		})
+
+		It("should return false if there is no source information", func() {
+			_, ok := SourceOf(sourceRevision(nil, nil))
+			Expect(ok).To(BeFalse())
+			_, ok = SourceOf(someRevision(1))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should use additional extractors", func() {
+			DeferCleanup(func(extractors []SourceExtractor) {
+				SourceExtractors = extractors
+			}, SourceExtractors)
+
+			SourceExtractors = append(SourceExtractors, AnnotationSourceExtractor{CommitKey: "example.com/sha"})
+			rev := sourceRevision(map[string]string{"example.com/sha": "3f2a9c1e5b"}, nil)
+
+			Expect(sourceOf(rev)).To(Equal(Source{Revision: "3f2a9c1e5b"}))
+		})
+	})
+
+	Describe("#Location", func() {
+		It("should return the repository and path", func() {
+			Expect(Source{Repository: "https://github.com/acme/apps/", Path: "/nginx"}.Location()).To(Equal("https://github.com/acme/apps/nginx"))
+			Expect(Source{Repository: "https://github.com/acme/apps", Tool: ToolFlux, Application: "apps"}.Location()).To(Equal("https://github.com/acme/apps"))
+		})
+
+		It("should fall back to the tool and application", func() {
+			Expect(Source{Tool: ToolArgoCD, Application: "nginx"}.Location()).To(Equal("argocd:nginx"))
+			Expect(Source{}.Location()).To(BeEmpty())
+		})
+	})
+
+	Describe("#Commit", func() {
+		It("should return the commit SHA of the revision", func() {
+			Expect(Source{Revision: "3F2A9C1E5B"}.Commit()).To(Equal("3f2a9c1e5b"))
+			Expect(Source{Revision: "main@sha1:3f2a9c1e5b"}.Commit()).To(Equal("3f2a9c1e5b"))
+			Expect(Source{Revision: "main/3f2a9c1e5b"}.Commit()).To(Equal("3f2a9c1e5b"))
+			Expect(Source{}.Commit()).To(BeEmpty())
+		})
+
+		It("should abbreviate the commit SHA", func() {
+			Expect(Source{Revision: "3f2a9c1e5b"}.ShortCommit()).To(Equal("3f2a9c1"))
+			Expect(Source{Revision: "3f2a"}.ShortCommit()).To(Equal("3f2a"))
+		})
+	})
+})
+
+func sourceOf(rev Revision) Source {
+	GinkgoHelper()
+
+	source, ok := SourceOf(rev)
+	Expect(ok).To(BeTrue())
+	return source
+}
+
+func sourceRevision(templateAnnotations, objectAnnotations map[string]string) Revision {
+	return &fake.Revision{
+		Num: 1,
+		Obj: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx-1", Annotations: objectAnnotations}},
+		Template: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			// labels and annotations are treated equally
+			Labels:      templateAnnotations,
+			Annotations: templateAnnotations,
+		}},
+	}
+}
@@ -48,6 +48,7 @@ func (d StatefulSetHistory) ListRevisions(ctx context.Context, obj client.Object
 	}
 
 	Sort(revs)
+	assignRoles(revs, revisionByName(revs, statefulSet.Status.CurrentRevision), revisionByName(revs, statefulSet.Status.UpdateRevision))
 	return revs, nil
 }
 
@@ -57,6 +58,7 @@ func NewControllerRevisionForStatefulSet(controllerRevision *appsv1.ControllerRe
 
 	revision := &ControllerRevision{}
 	revision.ControllerRevision = controllerRevision
+	revision.podPredicate = PodBelongsToStatefulSetRevision(controllerRevision)
 
 	statefulSet := &appsv1.StatefulSet{}
 	if statefulSetData, ok := revision.ControllerRevision.Data.Object.(*appsv1.StatefulSet); ok && statefulSetData != nil {
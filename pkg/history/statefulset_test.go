@@ -151,6 +151,25 @@ var _ = Describe("StatefulSetHistory", func() {
 			Expect(revs[1].ReadyReplicas()).To(BeEquivalentTo(1))
 		})
 
+		It("should assign the roles from the StatefulSet's status", func() {
+			statefulSet.Status.CurrentRevision = controllerRevision1.Name
+			statefulSet.Status.UpdateRevision = controllerRevision3.Name
+
+			revs, err := history.ListRevisions(ctx, statefulSet)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(RoleOf(revs[0])).To(Equal(RoleCurrent))
+			Expect(RoleOf(revs[1])).To(Equal(RoleUpdate))
+
+			statefulSet.Status.CurrentRevision = controllerRevision3.Name
+
+			revs, err = history.ListRevisions(ctx, statefulSet)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(RoleOf(revs[0])).To(Equal(RoleOld))
+			Expect(RoleOf(revs[1])).To(Equal(RoleCurrent))
+		})
+
 		It("should also work via ListRevisions shortcut", func() {
 			revs, err := ListRevisions(ctx, fakeClient, statefulSet)
 			Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodTemplateOf returns the pod template currently specified in the given workload object in the same form as returned
+// by Revision.PodTemplate.
+func PodTemplateOf(obj client.Object) (*corev1.Pod, error) {
+	var template *corev1.PodTemplateSpec
+
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		template = &o.Spec.Template
+	case *appsv1.StatefulSet:
+		template = &o.Spec.Template
+	case *appsv1.DaemonSet:
+		template = &o.Spec.Template
+	case *unstructured.Unstructured:
+		// e.g., Argo Rollouts
+		field, ok, err := unstructured.NestedMap(o.Object, "spec", "template")
+		if err != nil {
+			return nil, fmt.Errorf("error reading pod template: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s %s doesn't specify a pod template", o.GetKind(), o.GetName())
+		}
+
+		template = &corev1.PodTemplateSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(field, template); err != nil {
+			return nil, fmt.Errorf("error parsing pod template: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported object type %T", obj)
+	}
+
+	t := template.DeepCopy()
+	return &corev1.Pod{
+		ObjectMeta: t.ObjectMeta,
+		Spec:       t.Spec,
+	}, nil
+}
+
+// PodTemplateSpec converts a pod template as returned by Revision.PodTemplate back to a corev1.PodTemplateSpec, e.g.,
+// for writing it to the spec.template field of a workload object.
+func PodTemplateSpec(pod *corev1.Pod) *corev1.PodTemplateSpec {
+	p := pod.DeepCopy()
+	return &corev1.PodTemplateSpec{
+		ObjectMeta: p.ObjectMeta,
+		Spec:       p.Spec,
+	}
+}
+
+// IdenticalPodTemplates returns true if the given revisions have semantically equal pod templates, e.g., when comparing
+// the revisions of the same workload in different clusters or namespaces. The labels holding the hash of the pod
+// template (e.g., pod-template-hash or controller-revision-hash) are not considered.
+func IdenticalPodTemplates(a, b Revision) bool {
+	return apiequality.Semantic.DeepEqual(withoutHashLabels(a.PodTemplate()), withoutHashLabels(b.PodTemplate()))
+}
+
+// withoutHashLabels returns a copy of the given pod template without the labels holding the hash of the pod template.
+func withoutHashLabels(pod *corev1.Pod) *corev1.Pod {
+	if pod == nil {
+		return nil
+	}
+
+	p := pod.DeepCopy()
+	for _, key := range hashLabelKeys {
+		delete(p.Labels, key)
+	}
+	return p
+}
+
+// PodTemplateContains returns true if all fields set in the partial pod template are equal in the actual pod template,
+// e.g., for matching a pod template that hasn't been defaulted by the API server (like in a rendered manifest) with the
+// pod template of a revision. Both templates are normalized by converting them to unstructured content, e.g., for
+// comparing quantities.
+func PodTemplateContains(actual, partial *corev1.Pod) (bool, error) {
+	partialContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(partial)
+	if err != nil {
+		return false, err
+	}
+	actualContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(actual)
+	if err != nil {
+		return false, err
+	}
+
+	return isSubset(partialContent, actualContent), nil
+}
+
+// isSubset returns true if all fields of a are equal in b. Lists must have the same length, their items are compared
+// by index.
+func isSubset(a, b any) bool {
+	switch aValue := a.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		bValue, ok := b.(map[string]any)
+		if !ok {
+			return len(aValue) == 0 && b == nil
+		}
+		for key, value := range aValue {
+			if !isSubset(value, bValue[key]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bValue, ok := b.([]any)
+		if !ok {
+			return len(aValue) == 0 && b == nil
+		}
+		if len(aValue) != len(bValue) {
+			return false
+		}
+		for i := range aValue {
+			if !isSubset(aValue[i], bValue[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,198 @@
+package history_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+var _ = Describe("PodTemplateOf", func() {
+	var template corev1.PodTemplateSpec
+
+	BeforeEach(func() {
+		template = corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "test"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "test",
+					Image: "test:1",
+				}},
+			},
+		}
+	})
+
+	DescribeTable("should return the workload's pod template",
+		func(obj func() client.Object) {
+			pod, err := PodTemplateOf(obj())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pod.ObjectMeta).To(Equal(template.ObjectMeta))
+			Expect(pod.Spec).To(Equal(template.Spec))
+		},
+		Entry("Deployment", func() client.Object {
+			return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: template}}
+		}),
+		Entry("StatefulSet", func() client.Object {
+			return &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Template: template}}
+		}),
+		Entry("DaemonSet", func() client.Object {
+			return &appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: template}}
+		}),
+		Entry("unstructured Rollout", func() client.Object {
+			t, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&template)
+			Expect(err).NotTo(HaveOccurred())
+
+			rollout := &unstructured.Unstructured{}
+			rollout.SetGroupVersionKind(RolloutGroupKind.WithVersion("v1alpha1"))
+			Expect(unstructured.SetNestedMap(rollout.Object, t, "spec", "template")).To(Succeed())
+			return rollout
+		}),
+	)
+
+	It("should fail for unstructured objects without pod template", func() {
+		rollout := &unstructured.Unstructured{}
+		rollout.SetGroupVersionKind(RolloutGroupKind.WithVersion("v1alpha1"))
+		rollout.SetName("app")
+
+		_, err := PodTemplateOf(rollout)
+		Expect(err).To(MatchError("Rollout app doesn't specify a pod template"))
+	})
+
+	It("should not return a reference to the object's template", func() {
+		deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: template}}
+
+		pod, err := PodTemplateOf(deployment)
+		Expect(err).NotTo(HaveOccurred())
+
+		pod.Labels["app"] = "other"
+		Expect(deployment.Spec.Template.Labels).To(HaveKeyWithValue("app", "test"))
+	})
+
+	It("should fail for unsupported objects", func() {
+		_, err := PodTemplateOf(&corev1.ConfigMap{})
+		Expect(err).To(MatchError(ContainSubstring("unsupported object type")))
+	})
+})
+
+var _ = Describe("PodTemplateSpec", func() {
+	It("should convert the pod template", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "test"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "test"}},
+			},
+		}
+
+		Expect(PodTemplateSpec(pod)).To(Equal(&corev1.PodTemplateSpec{
+			ObjectMeta: pod.ObjectMeta,
+			Spec:       pod.Spec,
+		}))
+	})
+})
+
+var _ = Describe("IdenticalPodTemplates", func() {
+	var a, b *appsv1.ReplicaSet
+
+	BeforeEach(func() {
+		a = &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "nginx-a",
+				Annotations: map[string]string{"deployment.kubernetes.io/revision": "3"},
+			},
+			Spec: appsv1.ReplicaSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{"app": "nginx", appsv1.DefaultDeploymentUniqueLabelKey: "a"},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.25"}},
+					},
+				},
+			},
+		}
+
+		b = a.DeepCopy()
+		b.Name = "nginx-b"
+		b.Annotations["deployment.kubernetes.io/revision"] = "7"
+		b.Spec.Template.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = "b"
+	})
+
+	It("should ignore the revision number and pod-template-hash", func() {
+		Expect(IdenticalPodTemplates(newReplicaSet(a), newReplicaSet(b))).To(BeTrue())
+	})
+
+	It("should detect different pod templates", func() {
+		b.Spec.Template.Spec.Containers[0].Image = "nginx:1.26"
+		Expect(IdenticalPodTemplates(newReplicaSet(a), newReplicaSet(b))).To(BeFalse())
+	})
+
+	It("should ignore the hash labels of other revision kinds", func() {
+		revA := &Manifest{Template: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "nginx", appsv1.ControllerRevisionHashLabelKey: "nginx-a"},
+		}}}
+		revB := &Manifest{Template: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "nginx", RolloutPodTemplateHashLabel: "b"},
+		}}}
+		Expect(IdenticalPodTemplates(revA, revB)).To(BeTrue())
+	})
+})
+
+var _ = Describe("PodTemplateContains", func() {
+	var actual *corev1.Pod
+
+	BeforeEach(func() {
+		actual = &corev1.Pod{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "nginx",
+				Image:           "nginx:1.25",
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+			}},
+			RestartPolicy: corev1.RestartPolicyAlways,
+		}}
+	})
+
+	It("should ignore fields that are not set in the partial template", func() {
+		partial := &corev1.Pod{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "nginx",
+				Image: "nginx:1.25",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.5")},
+				},
+			}},
+			Volumes: []corev1.Volume{},
+		}}
+		Expect(PodTemplateContains(actual, partial)).To(BeTrue())
+	})
+
+	It("should detect different fields", func() {
+		partial := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.26"}}}}
+		Expect(PodTemplateContains(actual, partial)).To(BeFalse())
+	})
+
+	It("should detect additional list items", func() {
+		partial := actual.DeepCopy()
+		partial.Spec.Containers = append(partial.Spec.Containers, corev1.Container{Name: "sidecar"})
+		Expect(PodTemplateContains(actual, partial)).To(BeFalse())
+	})
+})
+
+func newReplicaSet(replicaSet *appsv1.ReplicaSet) Revision {
+	rev, err := NewReplicaSet(replicaSet)
+	Expect(err).NotTo(HaveOccurred())
+	return rev
+}
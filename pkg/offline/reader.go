@@ -0,0 +1,328 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/timebertt/kubectl-revisions/pkg/runutil"
+)
+
+// StdinPath is the path that can be passed to Reader.LoadFiles for reading objects from stdin.
+const StdinPath = "-"
+
+var _ client.Reader = &Reader{}
+
+// Reader is a client.Reader that serves objects loaded from files (e.g., `kubectl get -o yaml` dumps or must-gather
+// archives) instead of reading them from a live cluster. Objects of kinds registered in the Reader's scheme are loaded as
+// typed objects, all other objects (e.g., Argo Rollouts or custom resources) are loaded as unstructured objects.
+type Reader struct {
+	scheme  *runtime.Scheme
+	objects map[schema.GroupVersionKind][]client.Object
+}
+
+// NewReader creates a new empty Reader for objects of the kinds registered in the given scheme.
+func NewReader(scheme *runtime.Scheme) *Reader {
+	return &Reader{
+		scheme:  scheme,
+		objects: make(map[schema.GroupVersionKind][]client.Object),
+	}
+}
+
+// Scheme returns the scheme of this Reader.
+func (r *Reader) Scheme() *runtime.Scheme {
+	return r.scheme
+}
+
+// Add adds the given objects to the Reader.
+func (r *Reader) Add(objs ...client.Object) error {
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, r.scheme)
+		if err != nil {
+			return err
+		}
+
+		obj = obj.DeepCopyObject().(client.Object)
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		r.objects[gvk] = append(r.objects[gvk], obj)
+	}
+
+	return nil
+}
+
+// Kinds returns the kinds of all loaded objects.
+func (r *Reader) Kinds() []schema.GroupVersionKind {
+	kinds := make([]schema.GroupVersionKind, 0, len(r.objects))
+	for gvk := range r.objects {
+		kinds = append(kinds, gvk)
+	}
+	return kinds
+}
+
+// New returns a new empty object of the given kind. It is a typed object if the kind is registered in the Reader's
+// scheme and an unstructured object otherwise.
+func (r *Reader) New(gvk schema.GroupVersionKind) (client.Object, error) {
+	if !r.scheme.Recognizes(gvk) {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		return obj, nil
+	}
+
+	obj, err := r.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a client.Object", gvk)
+	}
+	return clientObj, nil
+}
+
+// NewList returns a new empty list for objects of the given kind, see New.
+func (r *Reader) NewList(gvk schema.GroupVersionKind) (client.ObjectList, error) {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	if !r.scheme.Recognizes(gvk) {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(listGVK)
+		return list, nil
+	}
+
+	obj, err := r.scheme.New(listGVK)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a client.ObjectList", listGVK)
+	}
+	return list, nil
+}
+
+// Objects returns all loaded objects of the given kind.
+func (r *Reader) Objects(gk schema.GroupKind) []client.Object {
+	var objs []client.Object
+	for gvk, list := range r.objects {
+		if gvk.GroupKind() != gk {
+			continue
+		}
+		for _, obj := range list {
+			objs = append(objs, obj.DeepCopyObject().(client.Object))
+		}
+	}
+	return objs
+}
+
+// LoadFiles loads all objects from the given files. If a directory is given, all files with a .yaml, .yml, or .json
+// extension in the directory and its subdirectories are loaded. If StdinPath is given, objects are read from stdin.
+func (r *Reader) LoadFiles(paths []string, stdin io.Reader) error {
+	for _, path := range paths {
+		if path == StdinPath {
+			if err := r.Load(stdin); err != nil {
+				return fmt.Errorf("error loading objects from stdin: %w", err)
+			}
+			continue
+		}
+
+		root := path
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			// always load explicitly specified files, but skip unrelated files in directories
+			if path != root && !isManifest(path) {
+				return nil
+			}
+
+			return r.loadFile(path)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isManifest(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func (r *Reader) loadFile(path string) (err error) {
+	// nolint:gosec // the file is explicitly specified by the user
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer runutil.CaptureError(&err, file.Close)
+
+	if err := r.Load(file); err != nil {
+		return fmt.Errorf("error loading objects from %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load loads all objects from the given YAML or JSON stream. The stream may contain multiple documents and List
+// objects.
+func (r *Reader) Load(in io.Reader) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(in, 4096)
+
+	for {
+		content := map[string]any{}
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if len(content) == 0 {
+			continue
+		}
+
+		if err := r.addUnstructured(&unstructured.Unstructured{Object: content}); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Reader) addUnstructured(u *unstructured.Unstructured) error {
+	if u.IsList() {
+		return u.EachListItem(func(item runtime.Object) error {
+			return r.addUnstructured(item.(*unstructured.Unstructured))
+		})
+	}
+
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		// ignore documents that are not API objects
+		return nil
+	}
+	if !r.scheme.Recognizes(gvk) {
+		return r.Add(u)
+	}
+
+	obj, err := r.scheme.New(gvk)
+	if err != nil {
+		return err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return fmt.Errorf("error converting %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(u), err)
+	}
+
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return nil
+	}
+
+	return r.Add(clientObj)
+}
+
+// Get retrieves an object for the given object key from the loaded objects.
+func (r *Reader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range r.objects[gvk] {
+		if o.GetNamespace() == key.Namespace && o.GetName() == key.Name {
+			return copyInto(o, obj)
+		}
+	}
+
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	return apierrors.NewNotFound(gvr.GroupResource(), key.Name)
+}
+
+// List retrieves a list of objects matching the given options from the loaded objects. Only the namespace and label
+// selector options are supported.
+func (r *Reader) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, r.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	listOptions := (&client.ListOptions{}).ApplyOptions(opts)
+	if listOptions.FieldSelector != nil && !listOptions.FieldSelector.Empty() {
+		return fmt.Errorf("field selectors are not supported when reading objects from files")
+	}
+
+	_, unstructuredList := list.(*unstructured.UnstructuredList)
+
+	var items []runtime.Object
+	for _, o := range r.objects[gvk] {
+		if listOptions.Namespace != "" && o.GetNamespace() != listOptions.Namespace {
+			continue
+		}
+		if listOptions.LabelSelector != nil && !listOptions.LabelSelector.Matches(labels.Set(o.GetLabels())) {
+			continue
+		}
+
+		var item client.Object
+		if unstructuredList {
+			item = &unstructured.Unstructured{}
+		} else if item, err = r.New(gvk); err != nil {
+			return err
+		}
+
+		if err := copyInto(o, item); err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+
+	return meta.SetList(list, items)
+}
+
+// copyInto copies the given loaded object into the given object. Typed objects are converted to unstructured objects
+// and vice versa if the types differ, e.g., when reading an object of a kind registered in the scheme as unstructured.
+func copyInto(src, dst client.Object) error {
+	if reflect.TypeOf(src) == reflect.TypeOf(dst) {
+		reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(src.DeepCopyObject()).Elem())
+		return nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(src)
+	if err != nil {
+		return fmt.Errorf("error converting %T to unstructured: %w", src, err)
+	}
+
+	if u, ok := dst.(runtime.Unstructured); ok {
+		u.SetUnstructuredContent(content)
+		return nil
+	}
+	if _, ok := src.(runtime.Unstructured); !ok {
+		return fmt.Errorf("cannot convert %T to %T", src, dst)
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, dst); err != nil {
+		return fmt.Errorf("error converting unstructured object to %T: %w", dst, err)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package offline_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	. "github.com/timebertt/kubectl-revisions/pkg/offline"
+	. "github.com/timebertt/kubectl-revisions/pkg/test/matcher"
+)
+
+var _ = Describe("Reader", func() {
+	const manifests = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: test
+  labels:
+    app: app
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: apps/v1
+  kind: ReplicaSet
+  metadata:
+    name: app-1
+    namespace: test
+    labels:
+      app: app
+- apiVersion: apps/v1
+  kind: ReplicaSet
+  metadata:
+    name: app-2
+    namespace: other
+    labels:
+      app: app
+- apiVersion: apps/v1
+  kind: ReplicaSet
+  metadata:
+    name: other-1
+    namespace: test
+    labels:
+      app: other
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: foo
+---
+`
+
+	var (
+		ctx context.Context
+		r   *Reader
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		r = NewReader(history.Scheme)
+	})
+
+	Describe("#Load", func() {
+		It("should load objects from multiple documents and lists", func() {
+			Expect(r.Load(strings.NewReader(manifests))).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, deployment)).To(Succeed())
+			Expect(deployment.Labels).To(HaveKeyWithValue("app", "app"))
+
+			replicaSetList := &appsv1.ReplicaSetList{}
+			Expect(r.List(ctx, replicaSetList)).To(Succeed())
+			Expect(replicaSetList.Items).To(HaveLen(3))
+		})
+
+		It("should load JSON objects", func() {
+			Expect(r.Load(strings.NewReader(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod","namespace":"test"}}`))).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "pod"}, &corev1.Pod{})).To(Succeed())
+		})
+
+		It("should load objects of unknown kinds as unstructured objects", func() {
+			Expect(r.Load(strings.NewReader(manifests))).To(Succeed())
+
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"})
+			Expect(r.Get(ctx, client.ObjectKey{Name: "foo"}, obj)).To(Succeed())
+			Expect(obj.GetName()).To(Equal("foo"))
+
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "UnknownList"})
+			Expect(r.List(ctx, list)).To(Succeed())
+			Expect(list.Items).To(HaveExactElements(HaveField("Object", HaveKeyWithValue("kind", "Unknown"))))
+		})
+
+		It("should fail on invalid input", func() {
+			Expect(r.Load(strings.NewReader("foo: ["))).NotTo(Succeed())
+		})
+	})
+
+	Describe("#LoadFiles", func() {
+		var dir string
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			Expect(os.MkdirAll(filepath.Join(dir, "sub"), 0700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "sub", "manifests.yaml"), []byte(manifests), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("# not a manifest: ["), 0600)).To(Succeed())
+		})
+
+		It("should load all manifests in a directory recursively", func() {
+			Expect(r.LoadFiles([]string{dir}, nil)).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, &appsv1.Deployment{})).To(Succeed())
+		})
+
+		It("should load explicitly specified files regardless of their extension", func() {
+			file := filepath.Join(dir, "manifests.txt")
+			Expect(os.WriteFile(file, []byte(manifests), 0600)).To(Succeed())
+
+			Expect(r.LoadFiles([]string{file}, nil)).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, &appsv1.Deployment{})).To(Succeed())
+		})
+
+		It("should load objects from stdin", func() {
+			Expect(r.LoadFiles([]string{StdinPath}, strings.NewReader(manifests))).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, &appsv1.Deployment{})).To(Succeed())
+		})
+
+		It("should fail if the file doesn't exist", func() {
+			Expect(r.LoadFiles([]string{filepath.Join(dir, "non-existing")}, nil)).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Describe("#Get", func() {
+		BeforeEach(func() {
+			Expect(r.Add(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test"}})).To(Succeed())
+		})
+
+		It("should return a NotFound error", func() {
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "other", Name: "app"}, &appsv1.Deployment{})).To(BeNotFoundError())
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, &appsv1.StatefulSet{})).To(BeNotFoundError())
+		})
+
+		It("should return a copy of the object", func() {
+			deployment := &appsv1.Deployment{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, deployment)).To(Succeed())
+			deployment.Labels = map[string]string{"foo": "bar"}
+
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, deployment)).To(Succeed())
+			Expect(deployment.Labels).To(BeEmpty())
+		})
+
+		It("should convert typed objects to unstructured objects", func() {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "app"}, obj)).To(Succeed())
+			Expect(obj.GetName()).To(Equal("app"))
+			Expect(obj.GetKind()).To(Equal("Deployment"))
+		})
+
+		It("should convert unstructured objects to typed objects", func() {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+			obj.SetNamespace("test")
+			obj.SetName("web")
+			Expect(r.Add(obj)).To(Succeed())
+
+			statefulSet := &appsv1.StatefulSet{}
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "test", Name: "web"}, statefulSet)).To(Succeed())
+			Expect(statefulSet.Name).To(Equal("web"))
+		})
+	})
+
+	Describe("#Kinds", func() {
+		It("should return the kinds of all loaded objects", func() {
+			Expect(r.Load(strings.NewReader(manifests))).To(Succeed())
+
+			Expect(r.Kinds()).To(ConsistOf(
+				appsv1.SchemeGroupVersion.WithKind("Deployment"),
+				appsv1.SchemeGroupVersion.WithKind("ReplicaSet"),
+				schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"},
+			))
+		})
+	})
+
+	Describe("#New", func() {
+		It("should return typed objects for kinds registered in the scheme", func() {
+			Expect(r.New(appsv1.SchemeGroupVersion.WithKind("Deployment"))).To(BeAssignableToTypeOf(&appsv1.Deployment{}))
+			Expect(r.NewList(appsv1.SchemeGroupVersion.WithKind("Deployment"))).To(BeAssignableToTypeOf(&appsv1.DeploymentList{}))
+		})
+
+		It("should return unstructured objects for unknown kinds", func() {
+			gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"}
+
+			obj, err := r.New(gvk)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj).To(BeAssignableToTypeOf(&unstructured.Unstructured{}))
+			Expect(obj.GetObjectKind().GroupVersionKind()).To(Equal(gvk))
+
+			list, err := r.NewList(gvk)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(BeAssignableToTypeOf(&unstructured.UnstructuredList{}))
+			Expect(list.GetObjectKind().GroupVersionKind().Kind).To(Equal("UnknownList"))
+		})
+	})
+
+	Describe("#Objects", func() {
+		It("should return all objects of the given kind", func() {
+			Expect(r.Load(strings.NewReader(manifests))).To(Succeed())
+
+			Expect(r.Objects(appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind())).To(ConsistOf(
+				HaveField("ObjectMeta.Name", "app"),
+			))
+			Expect(r.Objects(appsv1.SchemeGroupVersion.WithKind("ReplicaSet").GroupKind())).To(HaveLen(3))
+			Expect(r.Objects(appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind())).To(BeEmpty())
+		})
+	})
+
+	Describe("#List", func() {
+		BeforeEach(func() {
+			Expect(r.Load(strings.NewReader(manifests))).To(Succeed())
+		})
+
+		It("should filter by namespace", func() {
+			replicaSetList := &appsv1.ReplicaSetList{}
+			Expect(r.List(ctx, replicaSetList, client.InNamespace("test"))).To(Succeed())
+			Expect(replicaSetList.Items).To(ConsistOf(
+				HaveField("Name", "app-1"),
+				HaveField("Name", "other-1"),
+			))
+		})
+
+		It("should filter by label selector", func() {
+			replicaSetList := &appsv1.ReplicaSetList{}
+			Expect(r.List(ctx, replicaSetList, client.MatchingLabels{"app": "app"})).To(Succeed())
+			Expect(replicaSetList.Items).To(ConsistOf(
+				HaveField("Name", "app-1"),
+				HaveField("Name", "app-2"),
+			))
+		})
+
+		It("should convert the items to unstructured objects", func() {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("ReplicaSetList"))
+			Expect(r.List(ctx, list, client.InNamespace("test"))).To(Succeed())
+			Expect(list.Items).To(ConsistOf(
+				HaveField("Object", HaveKeyWithValue("metadata", HaveKeyWithValue("name", "app-1"))),
+				HaveField("Object", HaveKeyWithValue("metadata", HaveKeyWithValue("name", "other-1"))),
+			))
+		})
+
+		It("should reject field selectors", func() {
+			Expect(r.List(ctx, &appsv1.ReplicaSetList{}, client.MatchingFields{"metadata.name": "app-1"})).To(MatchError(ContainSubstring("not supported")))
+		})
+	})
+})
@@ -0,0 +1,58 @@
+package printer
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/timebertt/kubectl-revisions/pkg/audit"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// AuditTableColumns is the list of column definitions of AuditTable.
+var AuditTableColumns = []metav1.TableColumnDefinition{
+	{Name: "Revision", Type: "integer"},
+	{Name: "Time", Type: "string"},
+	{Name: "User", Type: "string"},
+	{Name: "Verb", Type: "string"},
+	{Name: "User-Agent", Type: "string"},
+	{Name: "Source-IP", Type: "string"},
+	{Name: "Name", Type: "string", Format: "name", Priority: 1},
+	{Name: "Audit-ID", Type: "string", Priority: 1},
+}
+
+// AuditTable transforms the given revisions and the audit events that caused them (see audit.Match) to a metav1.Table
+// for printing them with a table printer. There is one row per event, ordered by revision and time. Revisions without
+// events are printed with an unknown user.
+func AuditTable(revs history.Revisions, events []audit.RevisionEvent) *metav1.Table {
+	t := &metav1.Table{ColumnDefinitions: AuditTableColumns}
+
+	for _, rev := range revs {
+		found := false
+		for _, e := range events {
+			if e.Revision != rev {
+				continue
+			}
+			found = true
+
+			t.Rows = append(t.Rows, metav1.TableRow{Cells: []any{
+				rev.Number(),
+				e.Event.StageTimestamp.UTC().Format(time.RFC3339),
+				e.Event.Username(),
+				e.Event.Verb,
+				valueOrNone(e.Event.UserAgent),
+				valueOrNone(e.Event.SourceIP()),
+				rev.Name(),
+				e.Event.AuditID,
+			}})
+		}
+
+		if !found {
+			t.Rows = append(t.Rows, metav1.TableRow{Cells: []any{
+				rev.Number(), "<unknown>", "<unknown>", "<none>", "<none>", "<none>", rev.Name(), "<none>",
+			}})
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,43 @@
+package printer_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/timebertt/kubectl-revisions/pkg/audit"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	. "github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+var _ = Describe("AuditTable", func() {
+	var rev1, rev2 history.Revision
+
+	BeforeEach(func() {
+		var err error
+		rev1, err = history.NewReplicaSet(replicaSet(1))
+		Expect(err).NotTo(HaveOccurred())
+		rev2, err = history.NewReplicaSet(replicaSet(2))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should print one row per event and unknown users for revisions without events", func() {
+		event := &audit.Event{
+			AuditID:        "a1",
+			Verb:           "patch",
+			User:           audit.UserInfo{Username: "alice"},
+			SourceIPs:      []string{"10.0.0.1"},
+			UserAgent:      "kubectl/v1.30.0",
+			StageTimestamp: metav1.NewMicroTime(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)),
+		}
+
+		table := AuditTable(history.Revisions{rev1, rev2}, []audit.RevisionEvent{{Revision: rev2, Event: event}})
+		Expect(table.ColumnDefinitions).To(Equal(AuditTableColumns))
+		Expect(table.Rows).To(HaveExactElements(
+			HaveField("Cells", []any{int64(1), "<unknown>", "<unknown>", "<none>", "<none>", "<none>", rev1.Name(), "<none>"}),
+			HaveField("Cells", []any{int64(2), "2024-01-02T15:04:05Z", "alice", "patch", "kubectl/v1.30.0", "10.0.0.1", rev2.Name(), "a1"}),
+		))
+	})
+})
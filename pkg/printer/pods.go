@@ -0,0 +1,78 @@
+package printer
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta/table"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/timebertt/kubectl-revisions/pkg/helper"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// PodTableColumns is the list of column definitions of PodTable.
+var PodTableColumns = []metav1.TableColumnDefinition{
+	{Name: "Name", Type: "string", Format: "name"},
+	{Name: "Revision", Type: "integer"},
+	{Name: "Role", Type: "string"},
+	{Name: "Ready", Type: "string"},
+	{Name: "Status", Type: "string"},
+	{Name: "Restarts", Type: "integer"},
+	{Name: "Age", Type: "string"},
+	{Name: "Node", Type: "string"},
+	{Name: "IP", Type: "string", Priority: 1},
+	{Name: "Revision-Name", Type: "string", Priority: 1},
+}
+
+// PodTable transforms the given pods to a metav1.Table for printing them with a table printer. The ROLE column is
+// omitted if none of the pods' revisions know their role.
+func PodTable(pods []history.RevisionPod) *metav1.Table {
+	withRole := false
+	for _, pod := range pods {
+		if history.RoleOf(pod.Revision) != "" {
+			withRole = true
+			break
+		}
+	}
+
+	t := &metav1.Table{}
+	for _, column := range PodTableColumns {
+		if column.Name == "Role" && !withRole {
+			continue
+		}
+		t.ColumnDefinitions = append(t.ColumnDefinitions, column)
+	}
+
+	for _, pod := range pods {
+		ready, total := helper.ReadyContainers(pod.Pod)
+
+		cells := []any{pod.Pod.Name, pod.Revision.Number()}
+		if withRole {
+			cells = append(cells, string(history.RoleOf(pod.Revision)))
+		}
+		cells = append(cells,
+			fmt.Sprintf("%d/%d", ready, total),
+			helper.PodStatus(pod.Pod),
+			int64(helper.RestartCount(pod.Pod)),
+			table.ConvertToHumanReadableDateType(pod.Pod.CreationTimestamp),
+			valueOrNone(pod.Pod.Spec.NodeName),
+			valueOrNone(pod.Pod.Status.PodIP),
+			pod.Revision.Name(),
+		)
+
+		t.Rows = append(t.Rows, metav1.TableRow{
+			Cells:  cells,
+			Object: runtime.RawExtension{Object: pod.Pod},
+		})
+	}
+
+	return t
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package printer_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+	. "github.com/timebertt/kubectl-revisions/pkg/printer"
+)
+
+var _ = Describe("PodTable", func() {
+	var (
+		rev history.Revision
+		pod *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		var err error
+		rev, err = history.NewReplicaSet(replicaSet(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo-2-abc",
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: corev1.PodSpec{
+				NodeName:   "node-1",
+				Containers: []corev1.Container{{Name: "test"}},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				PodIP: "10.0.0.1",
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "test",
+					Ready:        true,
+					RestartCount: 1,
+				}},
+			},
+		}
+	})
+
+	It("should transform the pods to table rows", func() {
+		table := PodTable([]history.RevisionPod{{Pod: pod, Revision: rev}})
+
+		Expect(table.ColumnDefinitions).To(HaveExactElements(
+			HaveField("Name", "Name"),
+			HaveField("Name", "Revision"),
+			HaveField("Name", "Ready"),
+			HaveField("Name", "Status"),
+			HaveField("Name", "Restarts"),
+			HaveField("Name", "Age"),
+			HaveField("Name", "Node"),
+			HaveField("Name", "IP"),
+			HaveField("Name", "Revision-Name"),
+		))
+
+		Expect(table.Rows).To(HaveExactElements(metav1.TableRow{
+			Cells:  []any{"foo-2-abc", int64(2), "1/1", "Running", int64(1), "0s", "node-1", "10.0.0.1", "foo-2"},
+			Object: runtime.RawExtension{Object: pod},
+		}))
+	})
+
+	It("should add the role column if a revision knows its role", func() {
+		roled := roledRevision{Revision: &fake.Revision{Num: 3, Obj: &corev1.Pod{}}, role: history.RoleUpdate}
+		pending := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pending"}}
+
+		table := PodTable([]history.RevisionPod{{Pod: pod, Revision: rev}, {Pod: pending, Revision: roled}})
+
+		Expect(table.ColumnDefinitions[2].Name).To(Equal("Role"))
+		Expect(table.Rows[0].Cells[2]).To(BeEmpty())
+		Expect(table.Rows[1].Cells).To(HaveExactElements(
+			"pending", int64(3), "update", "0/0", "Unknown", int64(0), "<unknown>", "<none>", "<none>", "",
+		))
+	})
+})
+
+type roledRevision struct {
+	*fake.Revision
+	role history.Role
+}
+
+func (r roledRevision) Role() history.Role {
+	return r.role
+}
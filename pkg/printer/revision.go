@@ -1,6 +1,7 @@
 package printer
 
 import (
+	"fmt"
 	"io"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -19,13 +20,38 @@ var _ printers.ResourcePrinter = RevisionPrinter{}
 type RevisionPrinter struct {
 	Delegate     printers.ResourcePrinter
 	TemplateOnly bool
+	// Sections are added to the printed revision objects (not to pod templates).
+	Sections []ObjectSection
+}
+
+// ObjectSection is an additional top-level field of printed revision objects, e.g., for including information about
+// the revisions that is not part of the revision objects themselves in the YAML output.
+type ObjectSection struct {
+	// Name is the name of the added field.
+	Name string
+	// Extract returns the value of the field for the given revision: a pointer to a struct or nil for omitting the field.
+	Extract func(rev history.Revision) any
+}
+
+// WithSections returns a copy of the printer that adds the given sections to the printed revision objects.
+func (p RevisionPrinter) WithSections(sections ...ObjectSection) RevisionPrinter {
+	p.Sections = append(append([]ObjectSection{}, p.Sections...), sections...)
+	return p
 }
 
 // PrintObj prints a revision or list of revisions to the given writer using the printer's delegate.
 func (p RevisionPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
 	switch r := obj.(type) {
 	case history.Revision:
-		return p.Delegate.PrintObj(Printable(r, p.TemplateOnly), w)
+		if len(p.Sections) == 0 || p.TemplateOnly {
+			return p.Delegate.PrintObj(Printable(r, p.TemplateOnly), w)
+		}
+
+		u, err := p.toUnstructured(r)
+		if err != nil {
+			return err
+		}
+		return p.Delegate.PrintObj(u, w)
 	case history.Revisions:
 		// collect all revision objects in an unstructured list, which is properly handled by all used printers
 		list := &unstructured.UnstructuredList{
@@ -39,21 +65,11 @@ func (p RevisionPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
 		}
 
 		for _, rev := range r {
-			var object client.Object = rev.PodTemplate()
-			if !p.TemplateOnly {
-				object = rev.Object()
-				gvk, err := apiutil.GVKForObject(object, scheme.Scheme)
-				if err != nil {
-					return err
-				}
-				object.GetObjectKind().SetGroupVersionKind(gvk)
-			}
-
-			unstructuredContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+			u, err := p.toUnstructured(rev)
 			if err != nil {
 				return err
 			}
-			list.Items = append(list.Items, unstructured.Unstructured{Object: unstructuredContent})
+			list.Items = append(list.Items, *u)
 		}
 
 		return p.Delegate.PrintObj(list, w)
@@ -62,6 +78,41 @@ func (p RevisionPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
 	return p.Delegate.PrintObj(obj, w)
 }
 
+// toUnstructured converts the printable object of the given revision to unstructured and adds the printer's sections.
+func (p RevisionPrinter) toUnstructured(rev history.Revision) (*unstructured.Unstructured, error) {
+	var object client.Object = rev.PodTemplate()
+	if !p.TemplateOnly {
+		object = rev.Object()
+		gvk, err := apiutil.GVKForObject(object, scheme.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		object.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	unstructuredContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.TemplateOnly {
+		for _, section := range p.Sections {
+			value := section.Extract(rev)
+			if value == nil {
+				continue
+			}
+
+			sectionContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(value)
+			if err != nil {
+				return nil, fmt.Errorf("error converting %s of revision %s: %w", section.Name, rev.Name(), err)
+			}
+			unstructuredContent[section.Name] = sectionContent
+		}
+	}
+
+	return &unstructured.Unstructured{Object: unstructuredContent}, nil
+}
+
 // Printable returns the actually printable object of a Revision based on the --template-only flag.
 func Printable(rev history.Revision, templateOnly bool) client.Object {
 	if templateOnly {
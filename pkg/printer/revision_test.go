@@ -51,6 +51,18 @@ var _ = Describe("RevisionPrinter", func() {
 
 				Expect(delegate.printed).To(Equal(rev.Object()))
 			})
+
+			It("should add the sections to the revision object", func() {
+				*p = p.WithSections(ObjectSection{
+					Name:    "extra",
+					Extract: func(rev history.Revision) any { return &struct{ Name string }{Name: rev.Name()} },
+				})
+				Expect(p.PrintObj(rev, nil)).To(Succeed())
+
+				Expect(delegate.printed).To(BeAssignableToTypeOf(&unstructured.Unstructured{}))
+				Expect(delegate.printed.(*unstructured.Unstructured).GetName()).To(Equal(rev.Name()))
+				Expect(delegate.printed.(*unstructured.Unstructured).Object).To(HaveKeyWithValue("extra", map[string]any{"Name": rev.Name()}))
+			})
 		})
 
 		Context("TemplateOnly=true", func() {
@@ -101,6 +113,27 @@ var _ = Describe("RevisionPrinter", func() {
 					expectedUnstructuredReplicaSet(revs[1].Object().(*appsv1.ReplicaSet)),
 				}))
 			})
+
+			It("should add the sections to the revision objects", func() {
+				type section struct {
+					Number int64 `json:"number"`
+				}
+
+				*p = p.WithSections(ObjectSection{
+					Name: "extra",
+					Extract: func(rev history.Revision) any {
+						if rev.Number() == 1 {
+							return nil
+						}
+						return &section{Number: rev.Number()}
+					},
+				})
+				Expect(p.PrintObj(revs, nil)).To(Succeed())
+
+				list := delegate.printed.(*unstructured.UnstructuredList)
+				Expect(list.Items[0].Object).NotTo(HaveKey("extra"))
+				Expect(list.Items[1].Object).To(HaveKeyWithValue("extra", map[string]any{"number": int64(2)}))
+			})
 		})
 
 		Context("TemplateOnly=true", func() {
@@ -3,6 +3,7 @@ package printer
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta/table"
@@ -10,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/printers"
 
+	"github.com/timebertt/kubectl-revisions/pkg/diff"
 	"github.com/timebertt/kubectl-revisions/pkg/history"
 )
 
@@ -22,12 +24,21 @@ type RevisionsToTablePrinter struct {
 
 	// Columns is the list of columns that should be printed.
 	Columns []TableColumn
+	// History is the sorted revision history containing the printed revisions. It is used for determining the
+	// predecessors of the printed revisions, see TableColumn.ExtractWithPredecessor. Defaults to the printed revisions.
+	History history.Revisions
 }
 
 // TableColumn represents a single column with a header and logic for extracting a revision's cell value.
 type TableColumn struct {
 	metav1.TableColumnDefinition
 	Extract func(rev history.Revision) any
+	// ExtractWithPredecessor is used instead of Extract if set. It extracts a revision's cell value based on the
+	// revision's predecessor in the history of the same object. predecessor is nil if there is no known predecessor.
+	ExtractWithPredecessor func(rev, predecessor history.Revision) any
+
+	// OmitEmpty causes the column to be omitted if its value is empty for all printed revisions.
+	OmitEmpty bool
 }
 
 // DefaultTableColumns is the list of default column definitions.
@@ -56,6 +67,15 @@ var DefaultTableColumns = []TableColumn{
 			return fmt.Sprintf("%d/%d", rev.ReadyReplicas(), rev.CurrentReplicas())
 		},
 	},
+	{
+		TableColumnDefinition: metav1.TableColumnDefinition{
+			Name: "Role",
+			Type: "string",
+		},
+		Extract: func(rev history.Revision) any { return string(history.RoleOf(rev)) },
+		// only relevant for revisions that know their role
+		OmitEmpty: true,
+	},
 	{
 		TableColumnDefinition: metav1.TableColumnDefinition{
 			Name: "Age",
@@ -65,6 +85,82 @@ var DefaultTableColumns = []TableColumn{
 			return table.ConvertToHumanReadableDateType(rev.Object().GetCreationTimestamp())
 		},
 	},
+	{
+		TableColumnDefinition: metav1.TableColumnDefinition{
+			Name: "Markers",
+			Type: "string",
+		},
+		Extract: func(rev history.Revision) any {
+			if marked, ok := rev.(history.MarkedRevision); ok {
+				return strings.Join(marked.Markers(), ",")
+			}
+			return ""
+		},
+		// only relevant for some kinds, e.g., Argo Rollouts
+		OmitEmpty: true,
+	},
+	{
+		TableColumnDefinition: metav1.TableColumnDefinition{
+			Name: "Change-Cause",
+			Type: "string",
+		},
+		Extract: func(rev history.Revision) any { return history.ChangeCause(rev) },
+		// the annotation is only set if users record change causes explicitly
+		OmitEmpty: true,
+	},
+	{
+		TableColumnDefinition: metav1.TableColumnDefinition{
+			Name: "Source",
+			Type: "string",
+		},
+		Extract: func(rev history.Revision) any {
+			source, _ := history.SourceOf(rev)
+			return source.Location()
+		},
+		// only relevant for objects deployed by GitOps tools
+		OmitEmpty: true,
+	},
+	{
+		TableColumnDefinition: metav1.TableColumnDefinition{
+			Name: "Commit",
+			Type: "string",
+		},
+		Extract: func(rev history.Revision) any {
+			source, _ := history.SourceOf(rev)
+			return source.ShortCommit()
+		},
+		// only relevant for objects deployed by GitOps tools
+		OmitEmpty: true,
+	},
+	{
+		TableColumnDefinition: metav1.TableColumnDefinition{
+			Name:     "Trigger",
+			Type:     "string",
+			Priority: 1,
+		},
+		ExtractWithPredecessor: func(rev, predecessor history.Revision) any {
+			if predecessor == nil {
+				if rev.Number() == 1 {
+					return "initial"
+				}
+				return "<unknown>"
+			}
+
+			changes, err := diff.FieldChanges(predecessor.PodTemplate(), rev.PodTemplate())
+			if err != nil {
+				return "<unknown>"
+			}
+
+			var triggers []string
+			for _, trigger := range diff.InferTriggers(changes) {
+				triggers = append(triggers, string(trigger))
+			}
+			if len(triggers) == 0 {
+				return "<none>"
+			}
+			return strings.Join(triggers, ",")
+		},
+	},
 	{
 		TableColumnDefinition: metav1.TableColumnDefinition{
 			Name:     "Containers",
@@ -112,26 +208,148 @@ func (p RevisionsToTablePrinter) PrintObj(obj runtime.Object, w io.Writer) error
 		return p.Delegate.PrintObj(obj, w)
 	}
 
+	// extract all cells
+	cells := make([][]any, len(revs))
+	for i, rev := range revs {
+		for _, column := range p.Columns {
+			cells[i] = append(cells[i], p.extract(column, rev, revs))
+		}
+	}
+
 	t := &metav1.Table{}
 
-	// build column definitions
-	for _, column := range p.Columns {
+	// build column definitions, omit empty columns if requested
+	var columns []int
+	for j, column := range p.Columns {
+		if column.OmitEmpty && isEmptyColumn(cells, j) {
+			continue
+		}
+
+		columns = append(columns, j)
 		t.ColumnDefinitions = append(t.ColumnDefinitions, *column.DeepCopy())
 	}
 
 	// build rows
-	for _, rev := range revs {
-		var cells []any
-
-		for _, column := range p.Columns {
-			cells = append(cells, column.Extract(rev))
+	for i, rev := range revs {
+		row := make([]any, 0, len(columns))
+		for _, j := range columns {
+			row = append(row, cells[i][j])
 		}
 
 		t.Rows = append(t.Rows, metav1.TableRow{
-			Cells:  cells,
+			Cells:  row,
 			Object: runtime.RawExtension{Object: rev.Object()},
 		})
 	}
 
 	return p.Delegate.PrintObj(t, w)
 }
+
+func isEmptyColumn(cells [][]any, j int) bool {
+	for _, row := range cells {
+		if row[j] != nil && row[j] != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ForRevisions returns a copy of the printer that decides whether to omit empty columns based on the given revisions
+// instead of the printed ones. This keeps the columns stable when printing a subset of the revisions, e.g., only the
+// revisions that changed while watching.
+func (p RevisionsToTablePrinter) ForRevisions(revs history.Revisions) RevisionsToTablePrinter {
+	columns := make([]TableColumn, 0, len(p.Columns))
+	for _, column := range p.Columns {
+		if column.OmitEmpty {
+			empty := true
+			for _, rev := range revs {
+				if value := p.extract(column, rev, revs); value != nil && value != "" {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				continue
+			}
+			column.OmitEmpty = false
+		}
+
+		columns = append(columns, column)
+	}
+
+	p.Columns = columns
+	return p
+}
+
+// WithColumns returns a copy of the printer that prints the given additional columns. Columns that are printed by
+// default are inserted before the first column that is only printed in the wide format, other columns are appended.
+func (p RevisionsToTablePrinter) WithColumns(columns ...TableColumn) RevisionsToTablePrinter {
+	wideIndex := slices.IndexFunc(p.Columns, func(column TableColumn) bool { return column.Priority > 0 })
+	if wideIndex < 0 {
+		wideIndex = len(p.Columns)
+	}
+
+	out := slices.Clone(p.Columns[:wideIndex])
+	for _, column := range columns {
+		if column.Priority == 0 {
+			out = append(out, column)
+		}
+	}
+	out = append(out, p.Columns[wideIndex:]...)
+	for _, column := range columns {
+		if column.Priority > 0 {
+			out = append(out, column)
+		}
+	}
+
+	p.Columns = out
+	return p
+}
+
+// WithHistory returns a copy of the printer that uses the given revision history for determining the predecessors of
+// the printed revisions, e.g., when printing only a single selected revision.
+func (p RevisionsToTablePrinter) WithHistory(revs history.Revisions) RevisionsToTablePrinter {
+	p.History = revs
+	return p
+}
+
+// extract returns the cell value of the given column for the given revision. printed is the list of printed revisions,
+// which is used for determining the revision's predecessor if History is not set.
+func (p RevisionsToTablePrinter) extract(column TableColumn, rev history.Revision, printed history.Revisions) any {
+	if column.ExtractWithPredecessor == nil {
+		return column.Extract(rev)
+	}
+
+	revs := p.History
+	if len(revs) == 0 {
+		revs = printed
+	}
+	return column.ExtractWithPredecessor(rev, predecessorOf(rev, revs))
+}
+
+// predecessorOf returns the revision with the highest number lower than the given revision's number that belongs to the
+// same object (i.e., has the same namespace and controller) in the given list.
+func predecessorOf(rev history.Revision, revs history.Revisions) history.Revision {
+	var predecessor history.Revision
+
+	owner := ownerKey(rev)
+	for _, r := range revs {
+		if r.Number() >= rev.Number() || ownerKey(r) != owner {
+			continue
+		}
+		if predecessor == nil || r.Number() > predecessor.Number() {
+			predecessor = r
+		}
+	}
+
+	return predecessor
+}
+
+func ownerKey(rev history.Revision) string {
+	obj := rev.Object()
+	key := obj.GetNamespace()
+	if ref := metav1.GetControllerOf(obj); ref != nil {
+		key += "/" + string(ref.UID)
+	}
+	return key
+}
@@ -3,6 +3,7 @@ package printer_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -83,4 +84,262 @@ var _ = Describe("RevisionsToTablePrinter", func() {
 			},
 		))
 	})
+
+	Describe("OmitEmpty", func() {
+		var rev1, rev2 history.Revision
+
+		BeforeEach(func() {
+			var err error
+			rev1, err = history.NewReplicaSet(replicaSet(1))
+			Expect(err).NotTo(HaveOccurred())
+			rev2, err = history.NewReplicaSet(replicaSet(2))
+			Expect(err).NotTo(HaveOccurred())
+
+			p.Columns = append(p.Columns, TableColumn{
+				TableColumnDefinition: metav1.TableColumnDefinition{
+					Name: "Marker",
+				},
+				Extract: func(rev history.Revision) any {
+					if rev.Number() == 2 {
+						return "latest"
+					}
+					return ""
+				},
+				OmitEmpty: true,
+			})
+		})
+
+		It("should print the column if any revision has a value", func() {
+			Expect(p.PrintObj(history.Revisions{rev1, rev2}, nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.ColumnDefinitions).To(HaveExactElements(p.Columns[0].TableColumnDefinition, p.Columns[1].TableColumnDefinition))
+			Expect(table.Rows).To(HaveExactElements(
+				HaveField("Cells", []any{rev1.Name(), ""}),
+				HaveField("Cells", []any{rev2.Name(), "latest"}),
+			))
+		})
+
+		It("should omit the column if all values are empty", func() {
+			Expect(p.PrintObj(rev1, nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.ColumnDefinitions).To(HaveExactElements(p.Columns[0].TableColumnDefinition))
+			Expect(table.Rows).To(HaveExactElements(
+				HaveField("Cells", []any{rev1.Name()}),
+			))
+		})
+
+		Describe("#ForRevisions", func() {
+			It("should keep the column if any of the given revisions has a value", func() {
+				Expect(p.ForRevisions(history.Revisions{rev1, rev2}).PrintObj(rev1, nil)).To(Succeed())
+
+				table := delegate.printed.(*metav1.Table)
+				Expect(table.ColumnDefinitions).To(HaveExactElements(p.Columns[0].TableColumnDefinition, p.Columns[1].TableColumnDefinition))
+				Expect(table.Rows).To(HaveExactElements(
+					HaveField("Cells", []any{rev1.Name(), ""}),
+				))
+			})
+
+			It("should omit the column if all values of the given revisions are empty", func() {
+				Expect(p.ForRevisions(history.Revisions{rev1}).PrintObj(history.Revisions{rev1, rev2}, nil)).To(Succeed())
+
+				table := delegate.printed.(*metav1.Table)
+				Expect(table.ColumnDefinitions).To(HaveExactElements(p.Columns[0].TableColumnDefinition))
+				Expect(table.Rows).To(HaveExactElements(
+					HaveField("Cells", []any{rev1.Name()}),
+					HaveField("Cells", []any{rev2.Name()}),
+				))
+			})
+		})
+	})
+
+	Describe("#WithColumns", func() {
+		column := func(name string, priority int32) TableColumn {
+			return TableColumn{TableColumnDefinition: metav1.TableColumnDefinition{Name: name, Priority: priority}}
+		}
+
+		It("should insert default columns before the wide columns and append wide columns", func() {
+			p.Columns = append(p.Columns, column("Wide", 1))
+
+			withColumns := p.WithColumns(column("Default", 0), column("OtherWide", 1))
+			Expect(withColumns.Columns).To(HaveExactElements(
+				HaveField("Name", "Name"),
+				HaveField("Name", "Default"),
+				HaveField("Name", "Wide"),
+				HaveField("Name", "OtherWide"),
+			))
+			Expect(p.Columns).To(HaveLen(2))
+		})
+
+		It("should append default columns if there are no wide columns", func() {
+			Expect(p.WithColumns(column("Default", 0)).Columns).To(HaveExactElements(
+				HaveField("Name", "Name"),
+				HaveField("Name", "Default"),
+			))
+		})
+	})
+
+	Describe("ExtractWithPredecessor", func() {
+		var rev1, rev2, rev3 history.Revision
+
+		BeforeEach(func() {
+			var err error
+			rev1, err = history.NewReplicaSet(replicaSet(1))
+			Expect(err).NotTo(HaveOccurred())
+			rev2, err = history.NewReplicaSet(replicaSet(2))
+			Expect(err).NotTo(HaveOccurred())
+			rev3, err = history.NewReplicaSet(replicaSet(3))
+			Expect(err).NotTo(HaveOccurred())
+
+			p.Columns = append(p.Columns, TableColumn{
+				TableColumnDefinition: metav1.TableColumnDefinition{
+					Name: "Predecessor",
+				},
+				ExtractWithPredecessor: func(_, predecessor history.Revision) any {
+					if predecessor == nil {
+						return "<none>"
+					}
+					return predecessor.Name()
+				},
+			})
+		})
+
+		It("should pass the predecessor among the printed revisions", func() {
+			Expect(p.PrintObj(history.Revisions{rev1, rev3}, nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.Rows).To(HaveExactElements(
+				HaveField("Cells", []any{rev1.Name(), "<none>"}),
+				HaveField("Cells", []any{rev3.Name(), rev1.Name()}),
+			))
+		})
+
+		It("should pass the predecessor in the given history", func() {
+			Expect(p.WithHistory(history.Revisions{rev1, rev2, rev3}).PrintObj(rev3, nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.Rows).To(HaveExactElements(
+				HaveField("Cells", []any{rev3.Name(), rev2.Name()}),
+			))
+		})
+
+		It("should not consider revisions of other objects as predecessors", func() {
+			other := replicaSet(1)
+			other.Namespace = "other"
+			otherRev, err := history.NewReplicaSet(other)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.WithHistory(history.Revisions{otherRev, rev2}).PrintObj(rev2, nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.Rows).To(HaveExactElements(
+				HaveField("Cells", []any{rev2.Name(), "<none>"}),
+			))
+		})
+	})
+})
+
+var _ = Describe("DefaultTableColumns", func() {
+	var (
+		p        RevisionsToTablePrinter
+		delegate *fakePrinter
+	)
+
+	BeforeEach(func() {
+		delegate = &fakePrinter{}
+		p = RevisionsToTablePrinter{Delegate: delegate, Columns: DefaultTableColumns}
+	})
+
+	columnValues := func(name string) []any {
+		GinkgoHelper()
+
+		table := delegate.printed.(*metav1.Table)
+		for i, column := range table.ColumnDefinitions {
+			if column.Name == name {
+				var values []any
+				for _, row := range table.Rows {
+					values = append(values, row.Cells[i])
+				}
+				return values
+			}
+		}
+
+		Fail("column " + name + " not found")
+		return nil
+	}
+
+	newRevision := func(rs *appsv1.ReplicaSet) history.Revision {
+		GinkgoHelper()
+
+		rev, err := history.NewReplicaSet(rs)
+		Expect(err).NotTo(HaveOccurred())
+		return rev
+	}
+
+	Describe("Role", func() {
+		It("should omit the column if no revision has a role", func() {
+			Expect(p.PrintObj(newRevision(replicaSet(1)), nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.ColumnDefinitions).NotTo(ContainElement(HaveField("Name", "Role")))
+		})
+	})
+
+	Describe("Change-Cause", func() {
+		It("should omit the column if no revision has a change cause", func() {
+			Expect(p.PrintObj(history.Revisions{newRevision(replicaSet(1))}, nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.ColumnDefinitions).NotTo(ContainElement(HaveField("Name", "Change-Cause")))
+		})
+
+		It("should print the change cause annotation", func() {
+			rs := replicaSet(2)
+			rs.Annotations[history.ChangeCauseAnnotation] = "kubectl set image"
+
+			Expect(p.PrintObj(history.Revisions{newRevision(replicaSet(1)), newRevision(rs)}, nil)).To(Succeed())
+			Expect(columnValues("Change-Cause")).To(HaveExactElements("", "kubectl set image"))
+		})
+	})
+
+	Describe("Source", func() {
+		It("should omit the columns if no revision has a source", func() {
+			Expect(p.PrintObj(history.Revisions{newRevision(replicaSet(1))}, nil)).To(Succeed())
+
+			table := delegate.printed.(*metav1.Table)
+			Expect(table.ColumnDefinitions).NotTo(ContainElement(HaveField("Name", "Source")))
+			Expect(table.ColumnDefinitions).NotTo(ContainElement(HaveField("Name", "Commit")))
+		})
+
+		It("should print the source location and commit", func() {
+			rs := replicaSet(2)
+			rs.Annotations[history.ArgoCDTrackingIDAnnotation] = "nginx:apps/Deployment:default/nginx"
+			rs.Spec.Template.Annotations = map[string]string{history.SourceCommitAnnotation: "3f2a9c1e5b"}
+
+			Expect(p.PrintObj(history.Revisions{newRevision(replicaSet(1)), newRevision(rs)}, nil)).To(Succeed())
+			Expect(columnValues("Source")).To(HaveExactElements("", "argocd:nginx"))
+			Expect(columnValues("Commit")).To(HaveExactElements("", "3f2a9c1"))
+		})
+	})
+
+	Describe("Trigger", func() {
+		It("should infer the trigger from the predecessor", func() {
+			restarted := replicaSet(2)
+			restarted.Spec.Template.Spec.Containers[0].Image = "test:1"
+			restarted.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "now"}
+
+			Expect(p.PrintObj(history.Revisions{
+				newRevision(replicaSet(1)),
+				newRevision(restarted),
+				newRevision(replicaSet(3)),
+			}, nil)).To(Succeed())
+			Expect(columnValues("Trigger")).To(HaveExactElements("initial", "restart", "image,restart"))
+		})
+
+		It("should print unknown triggers if there is no predecessor", func() {
+			Expect(p.PrintObj(newRevision(replicaSet(2)), nil)).To(Succeed())
+			Expect(columnValues("Trigger")).To(HaveExactElements("<unknown>"))
+		})
+	})
 })
@@ -0,0 +1,43 @@
+package printer
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/timebertt/kubectl-revisions/pkg/timeline"
+)
+
+// TimelineTableColumns is the list of column definitions of TimelineTable.
+var TimelineTableColumns = []metav1.TableColumnDefinition{
+	{Name: "Time", Type: "string"},
+	{Name: "Revision", Type: "integer"},
+	{Name: "Type", Type: "string"},
+	{Name: "Reason", Type: "string"},
+	{Name: "Object", Type: "string"},
+	{Name: "Message", Type: "string"},
+}
+
+// TimelineTable transforms the given timeline entries to a metav1.Table for printing them with a table printer. Entries
+// that don't belong to any revision are printed without a revision number.
+func TimelineTable(entries timeline.Entries) *metav1.Table {
+	t := &metav1.Table{ColumnDefinitions: TimelineTableColumns}
+
+	for _, entry := range entries {
+		var revision any = "<none>"
+		if entry.Revision != nil {
+			revision = entry.Revision.Number()
+		}
+
+		t.Rows = append(t.Rows, metav1.TableRow{Cells: []any{
+			entry.Time.UTC().Format(time.RFC3339),
+			revision,
+			entry.Type,
+			entry.Reason,
+			entry.Kind + "/" + entry.Name,
+			entry.Message,
+		}})
+	}
+
+	return t
+}
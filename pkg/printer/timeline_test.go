@@ -0,0 +1,32 @@
+package printer_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	. "github.com/timebertt/kubectl-revisions/pkg/printer"
+	"github.com/timebertt/kubectl-revisions/pkg/timeline"
+)
+
+var _ = Describe("TimelineTable", func() {
+	It("should print one row per entry", func() {
+		rev, err := history.NewReplicaSet(replicaSet(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		t := metav1.NewTime(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC))
+		table := TimelineTable(timeline.Entries{
+			{Time: t, Revision: rev, Type: "Normal", Kind: "ReplicaSet", Name: rev.Name(), Reason: "RevisionCreated", Message: "Created revision 2"},
+			{Time: t, Type: "Normal", Kind: "Deployment", Name: "nginx", Reason: "ScalingReplicaSet", Message: "Scaled down replica set nginx-0 from 1 to 0"},
+		})
+
+		Expect(table.ColumnDefinitions).To(Equal(TimelineTableColumns))
+		Expect(table.Rows).To(HaveExactElements(
+			HaveField("Cells", []any{"2024-01-02T15:04:05Z", int64(2), "Normal", "RevisionCreated", "ReplicaSet/" + rev.Name(), "Created revision 2"}),
+			HaveField("Cells", []any{"2024-01-02T15:04:05Z", "<none>", "Normal", "ScalingReplicaSet", "Deployment/nginx", "Scaled down replica set nginx-0 from 1 to 0"}),
+		))
+	})
+})
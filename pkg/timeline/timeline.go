@@ -0,0 +1,274 @@
+package timeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/timebertt/kubectl-revisions/pkg/helper"
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+)
+
+// Reasons of the entries that are not based on Events.
+const (
+	// ReasonRevisionCreated is the reason of entries for the creation of a revision.
+	ReasonRevisionCreated = "RevisionCreated"
+	// ReasonPodReady is the reason of entries for pods that became ready.
+	ReasonPodReady = "PodReady"
+	// ReasonPodNotReady is the reason of entries for pods that became not ready.
+	ReasonPodNotReady = "PodNotReady"
+	// ReasonContainerRestarted is the reason of entries for restarted containers.
+	ReasonContainerRestarted = "ContainerRestarted"
+)
+
+// EventReasons is the list of Event reasons that are included in the timeline, i.e., scaling of ReplicaSets by the
+// Deployment controller and creation of pods by the workload controllers.
+var EventReasons = []string{"ScalingReplicaSet", "SuccessfulCreate"}
+
+// Entry is a single point in the timeline of a workload object.
+type Entry struct {
+	// Time is the time at which the entry occurred.
+	Time metav1.Time
+	// Revision is the revision the entry belongs to, nil if it cannot be related to any revision.
+	Revision history.Revision
+	// Type is the type of the entry like the type of Events, i.e., Normal or Warning.
+	Type string
+	// Kind and Name identify the object the entry is about, e.g., the revision object or a pod.
+	Kind, Name string
+	// Reason is a short machine-readable reason of the entry, e.g., RevisionCreated or ScalingReplicaSet.
+	Reason string
+	// Message is a human-readable description of the entry.
+	Message string
+}
+
+// MarshalJSON implements json.Marshaler. The revision is represented by its number.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Time     metav1.Time `json:"time"`
+		Revision int64       `json:"revision,omitempty"`
+		Type     string      `json:"type"`
+		Kind     string      `json:"kind"`
+		Name     string      `json:"name"`
+		Reason   string      `json:"reason"`
+		Message  string      `json:"message"`
+	}{
+		Time:    e.Time,
+		Type:    e.Type,
+		Kind:    e.Kind,
+		Name:    e.Name,
+		Reason:  e.Reason,
+		Message: e.Message,
+	}
+	if e.Revision != nil {
+		out.Revision = e.Revision.Number()
+	}
+	return json.Marshal(out)
+}
+
+// Entries is a list of timeline entries sorted by time.
+type Entries []Entry
+
+// Since returns the entries that occurred at or after the given time.
+func (e Entries) Since(t time.Time) Entries {
+	return slices.DeleteFunc(slices.Clone(e), func(entry Entry) bool {
+		return entry.Time.Time.Before(t)
+	})
+}
+
+// Build merges the creation of the given revisions of the given workload object, the relevant Events (see
+// EventReasons), and the readiness transitions and container restarts of the given pods into a single chronologically
+// sorted timeline.
+//
+// Events are included if they are about the workload object or one of the revision objects. They are related to the
+// revision whose object they are about or whose object or pod they mention, e.g., "Scaled up replica set nginx-1 from 0
+// to 1". Pods only record their last readiness transition and the last termination of each container, earlier
+// transitions and restarts are not part of the timeline.
+func Build(obj client.Object, revs history.Revisions, events []corev1.Event, pods []history.RevisionPod) Entries {
+	var entries Entries
+
+	for _, rev := range revs {
+		entries = append(entries, revisionEntry(rev))
+	}
+
+	for i := range events {
+		if entry, ok := eventEntry(&events[i], obj, revs, pods); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, pod := range pods {
+		entries = append(entries, podEntries(pod)...)
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return a.Time.Compare(b.Time.Time)
+	})
+
+	return entries
+}
+
+func revisionEntry(rev history.Revision) Entry {
+	message := fmt.Sprintf("Created revision %d", rev.Number())
+	if changeCause := history.ChangeCause(rev); changeCause != "" {
+		message += ": " + changeCause
+	}
+
+	return Entry{
+		Time:     rev.Object().GetCreationTimestamp(),
+		Revision: rev,
+		Type:     corev1.EventTypeNormal,
+		Kind:     kindOf(rev.Object()),
+		Name:     rev.Name(),
+		Reason:   ReasonRevisionCreated,
+		Message:  message,
+	}
+}
+
+func eventEntry(event *corev1.Event, obj client.Object, revs history.Revisions, pods []history.RevisionPod) (Entry, bool) {
+	if !slices.Contains(EventReasons, event.Reason) {
+		return Entry{}, false
+	}
+
+	ref := event.InvolvedObject
+	if ref.Namespace != obj.GetNamespace() {
+		return Entry{}, false
+	}
+
+	var rev history.Revision
+	if refersTo(ref, obj) {
+		rev = mentionedRevision(event.Message, revs, pods)
+	} else {
+		i := slices.IndexFunc(revs, func(rev history.Revision) bool {
+			return refersTo(ref, rev.Object())
+		})
+		if i < 0 {
+			return Entry{}, false
+		}
+		rev = revs[i]
+	}
+
+	message := event.Message
+	if event.Count > 1 {
+		message += fmt.Sprintf(" (x%d)", event.Count)
+	}
+
+	return Entry{
+		Time:     eventTime(event),
+		Revision: rev,
+		Type:     event.Type,
+		Kind:     ref.Kind,
+		Name:     ref.Name,
+		Reason:   event.Reason,
+		Message:  message,
+	}, true
+}
+
+// refersTo returns true if the given reference points to the given object. The UID is only compared if both have one.
+func refersTo(ref corev1.ObjectReference, obj client.Object) bool {
+	if ref.UID != "" && obj.GetUID() != "" {
+		return ref.UID == obj.GetUID()
+	}
+	return ref.Name == obj.GetName() && ref.Kind == kindOf(obj)
+}
+
+// mentionedRevision returns the revision whose object or pod is mentioned in the given Event message.
+func mentionedRevision(message string, revs history.Revisions, pods []history.RevisionPod) history.Revision {
+	words := strings.FieldsFunc(message, func(r rune) bool {
+		return r == ' ' || r == ':' || r == ','
+	})
+
+	for _, word := range words {
+		for _, rev := range revs {
+			if rev.Name() == word {
+				return rev
+			}
+		}
+		for _, pod := range pods {
+			if pod.Pod.Name == word {
+				return pod.Revision
+			}
+		}
+	}
+
+	return nil
+}
+
+// eventTime returns the time of the last occurrence of the given Event.
+func eventTime(event *corev1.Event) metav1.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return metav1.NewTime(event.Series.LastObservedTime.Time)
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp
+	case !event.EventTime.IsZero():
+		return metav1.NewTime(event.EventTime.Time)
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp
+	}
+	return event.CreationTimestamp
+}
+
+func podEntries(pod history.RevisionPod) Entries {
+	var entries Entries
+
+	newEntry := func(t metav1.Time, eventType, reason, message string) Entry {
+		return Entry{
+			Time:     t,
+			Revision: pod.Revision,
+			Type:     eventType,
+			Kind:     "Pod",
+			Name:     pod.Pod.Name,
+			Reason:   reason,
+			Message:  message,
+		}
+	}
+
+	if condition := helper.GetPodCondition(pod.Pod.Status.Conditions, corev1.PodReady); condition != nil && !condition.LastTransitionTime.IsZero() {
+		if condition.Status == corev1.ConditionTrue {
+			entries = append(entries, newEntry(condition.LastTransitionTime, corev1.EventTypeNormal, ReasonPodReady, "Pod became ready"))
+		} else {
+			message := "Pod became not ready"
+			if condition.Message != "" {
+				message += ": " + condition.Message
+			}
+			entries = append(entries, newEntry(condition.LastTransitionTime, corev1.EventTypeWarning, ReasonPodNotReady, message))
+		}
+	}
+
+	for _, container := range slices.Concat(pod.Pod.Status.InitContainerStatuses, pod.Pod.Status.ContainerStatuses) {
+		terminated := container.LastTerminationState.Terminated
+		if container.RestartCount == 0 || terminated == nil {
+			continue
+		}
+
+		message := fmt.Sprintf("Container %s restarted (%d restarts in total), last terminated with exit code %d",
+			container.Name, container.RestartCount, terminated.ExitCode)
+		if terminated.Reason != "" {
+			message += " and reason " + terminated.Reason
+		}
+
+		t := terminated.FinishedAt
+		if t.IsZero() {
+			t = terminated.StartedAt
+		}
+		entries = append(entries, newEntry(t, corev1.EventTypeWarning, ReasonContainerRestarted, message))
+	}
+
+	return entries
+}
+
+// kindOf returns the kind of the given object, also for typed objects without TypeMeta.
+func kindOf(obj client.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, history.Scheme)
+	if err != nil {
+		return obj.GetObjectKind().GroupVersionKind().Kind
+	}
+	return gvk.Kind
+}
@@ -0,0 +1,162 @@
+package timeline_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	gomegatypes "github.com/onsi/gomega/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/timebertt/kubectl-revisions/pkg/history"
+	"github.com/timebertt/kubectl-revisions/pkg/history/fake"
+	"github.com/timebertt/kubectl-revisions/pkg/timeline"
+)
+
+var _ = Describe("Timeline", func() {
+	var (
+		start      time.Time
+		deployment *appsv1.Deployment
+
+		rev1, rev2 history.Revision
+		revs       history.Revisions
+		pod        *corev1.Pod
+		pods       []history.RevisionPod
+	)
+
+	BeforeEach(func() {
+		start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", UID: "d1"}}
+
+		rev1 = fake.ReplicaSetRevision(Default, 1, start, corev1.PodSpec{})
+		rev2 = fake.ReplicaSetRevision(Default, 2, start.Add(time.Hour), corev1.PodSpec{})
+		revs = history.Revisions{rev1, rev2}
+
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx-2-abc", Namespace: "default"}}
+		pods = []history.RevisionPod{{Pod: pod, Revision: rev2}}
+	})
+
+	Describe("#Build", func() {
+		It("should merge revisions, events, and pod transitions chronologically", func() {
+			pod.Status.Conditions = []corev1.PodCondition{{
+				Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(start.Add(time.Hour + 2*time.Minute)),
+			}}
+
+			events := []corev1.Event{
+				event("ScalingReplicaSet", "Deployment", "nginx", "d1", "Scaled up replica set nginx-2 from 0 to 1", start.Add(time.Hour)),
+				event("SuccessfulCreate", "ReplicaSet", "nginx-2", "rs-2", "Created pod: nginx-2-abc", start.Add(time.Hour+time.Minute)),
+				event("SuccessfulCreate", "ReplicaSet", "nginx-1", "rs-1", "Created pod: nginx-1-abc", start.Add(time.Minute)),
+			}
+
+			Expect(timeline.Build(deployment, revs, events, pods)).To(HaveExactElements(
+				entry(rev1, "ReplicaSet", "nginx-1", timeline.ReasonRevisionCreated, "Created revision 1", start),
+				entry(rev1, "ReplicaSet", "nginx-1", "SuccessfulCreate", "Created pod: nginx-1-abc", start.Add(time.Minute)),
+				entry(rev2, "ReplicaSet", "nginx-2", timeline.ReasonRevisionCreated, "Created revision 2", start.Add(time.Hour)),
+				entry(rev2, "Deployment", "nginx", "ScalingReplicaSet", "Scaled up replica set nginx-2 from 0 to 1", start.Add(time.Hour)),
+				entry(rev2, "ReplicaSet", "nginx-2", "SuccessfulCreate", "Created pod: nginx-2-abc", start.Add(time.Hour+time.Minute)),
+				entry(rev2, "Pod", "nginx-2-abc", timeline.ReasonPodReady, "Pod became ready", start.Add(time.Hour+2*time.Minute)),
+			))
+		})
+
+		It("should skip events of other objects and with other reasons", func() {
+			events := []corev1.Event{
+				event("ScalingReplicaSet", "Deployment", "other", "d2", "Scaled up replica set other-1 from 0 to 1", start),
+				event("SuccessfulCreate", "ReplicaSet", "other-1", "rs-3", "Created pod: other-1-abc", start),
+				event("DeploymentRollback", "Deployment", "nginx", "d1", "Rolled back deployment to revision 1", start),
+			}
+
+			Expect(timeline.Build(deployment, revs, events, nil)).To(HaveExactElements(
+				HaveField("Reason", timeline.ReasonRevisionCreated),
+				HaveField("Reason", timeline.ReasonRevisionCreated),
+			))
+		})
+
+		It("should relate events of the workload object to the revision of the mentioned pod", func() {
+			e := event("SuccessfulCreate", "Deployment", "nginx", "d1", "Created pod: nginx-2-abc", start.Add(2*time.Hour))
+			e.Count = 3
+
+			Expect(timeline.Build(deployment, revs, []corev1.Event{e}, pods)).To(ContainElement(
+				entry(rev2, "Deployment", "nginx", "SuccessfulCreate", "Created pod: nginx-2-abc (x3)", start.Add(2*time.Hour)),
+			))
+		})
+
+		It("should add entries for pods that became not ready and restarted containers", func() {
+			pod.Status.Conditions = []corev1.PodCondition{{
+				Type: corev1.PodReady, Status: corev1.ConditionFalse, Message: "containers with unready status: [nginx]",
+				LastTransitionTime: metav1.NewTime(start.Add(3 * time.Hour)),
+			}}
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:         "nginx",
+				RestartCount: 2,
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: 137, Reason: "OOMKilled", FinishedAt: metav1.NewTime(start.Add(2 * time.Hour)),
+				}},
+			}, {
+				Name: "sidecar",
+			}}
+
+			Expect(timeline.Build(deployment, history.Revisions{rev2}, nil, pods)).To(HaveExactElements(
+				HaveField("Reason", timeline.ReasonRevisionCreated),
+				And(
+					entry(rev2, "Pod", "nginx-2-abc", timeline.ReasonContainerRestarted,
+						"Container nginx restarted (2 restarts in total), last terminated with exit code 137 and reason OOMKilled", start.Add(2*time.Hour)),
+					HaveField("Type", corev1.EventTypeWarning),
+				),
+				And(
+					entry(rev2, "Pod", "nginx-2-abc", timeline.ReasonPodNotReady,
+						"Pod became not ready: containers with unready status: [nginx]", start.Add(3*time.Hour)),
+					HaveField("Type", corev1.EventTypeWarning),
+				),
+			))
+		})
+	})
+
+	Describe("Entries#Since", func() {
+		It("should only return entries at or after the given time", func() {
+			entries := timeline.Build(deployment, revs, nil, nil)
+			Expect(entries.Since(start.Add(time.Hour))).To(HaveExactElements(HaveField("Revision", rev2)))
+			Expect(entries.Since(start)).To(HaveLen(2))
+			Expect(entries).To(HaveLen(2))
+		})
+	})
+
+	Describe("Entry#MarshalJSON", func() {
+		It("should represent the revision by its number", func() {
+			data, err := json.Marshal(timeline.Build(deployment, history.Revisions{rev2}, nil, nil)[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"time":"2024-01-01T01:00:00Z","revision":2,"type":"Normal","kind":"ReplicaSet","name":"nginx-2","reason":"RevisionCreated","message":"Created revision 2"}`))
+		})
+
+		It("should omit unknown revisions", func() {
+			data, err := json.Marshal(timeline.Entry{Time: metav1.NewTime(start), Type: "Normal", Kind: "Deployment", Name: "nginx"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"time":"2024-01-01T00:00:00Z","type":"Normal","kind":"Deployment","name":"nginx","reason":"","message":""}`))
+		})
+	})
+})
+
+func event(reason, kind, name string, uid types.UID, message string, t time.Time) corev1.Event {
+	return corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: "default", Name: name, UID: uid},
+		Reason:         reason,
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		LastTimestamp:  metav1.NewTime(t),
+	}
+}
+
+func entry(rev history.Revision, kind, name, reason, message string, t time.Time) gomegatypes.GomegaMatcher {
+	return MatchFields(IgnoreExtras, Fields{
+		"Time":     Equal(metav1.NewTime(t)),
+		"Revision": BeIdenticalTo(rev),
+		"Kind":     Equal(kind),
+		"Name":     Equal(name),
+		"Reason":   Equal(reason),
+		"Message":  Equal(message),
+	})
+}
@@ -0,0 +1,94 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/timebertt/kubectl-revisions/pkg/audit"
+	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
+	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
+)
+
+var _ = Describe("audit command", func() {
+	var (
+		namespace string
+		object    client.Object
+		auditLog  string
+
+		args []string
+	)
+
+	BeforeEach(func() {
+		namespace = workload.PrepareTestNamespace()
+		auditLog = filepath.Join(GinkgoT().TempDir(), "audit.log")
+
+		object = workload.CreateDeployment(namespace, workload.AppName)
+		workload.BumpImage(object)
+		Eventually(komega.ObjectList(&appsv1.ReplicaSetList{}, client.InNamespace(namespace))).Should(HaveField("Items", HaveLen(2)))
+
+		args = []string{"audit", "-n", namespace, "deployment", object.GetName(), "--audit-log", auditLog}
+	})
+
+	It("should print the user of the request that caused each revision", func() {
+		// the e2e cluster doesn't write audit logs, simulate the requests that created the revisions instead
+		replicaSetList := &appsv1.ReplicaSetList{}
+		Expect(testClient.List(context.Background(), replicaSetList, client.InNamespace(namespace))).To(Succeed())
+
+		file, err := os.Create(auditLog)
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+
+		users := map[string]string{"1": "alice", "2": "bob"}
+		for _, replicaSet := range replicaSetList.Items {
+			deployment := object.DeepCopyObject().(*appsv1.Deployment)
+			deployment.Spec.Template = *replicaSet.Spec.Template.DeepCopy()
+			delete(deployment.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+			responseObject, err := json.Marshal(deployment)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(json.NewEncoder(file).Encode(&audit.Event{
+				AuditID: string(replicaSet.UID),
+				Stage:   audit.StageResponseComplete,
+				Verb:    "update",
+				User:    audit.UserInfo{Username: users[replicaSet.Annotations[deploymentutil.RevisionAnnotation]]},
+				ObjectRef: &audit.ObjectReference{
+					Resource:  "deployments",
+					Namespace: namespace,
+					Name:      object.GetName(),
+					APIGroup:  "apps",
+				},
+				ResponseObject: responseObject,
+				StageTimestamp: metav1.NewMicroTime(replicaSet.CreationTimestamp.Time),
+			})).To(Succeed())
+		}
+
+		session := RunPluginAndWait(args...)
+		Eventually(session).Should(Say(`REVISION\s+TIME\s+USER\s+VERB\s+USER-AGENT\s+SOURCE-IP\n`))
+		Eventually(session).Should(Say(`1\s+\S+\s+alice\s+update\s+`))
+		Eventually(session).Should(Say(`2\s+\S+\s+bob\s+update\s+`))
+
+		session = RunPluginAndWait(append(args, "-o", "jsonpath={.items[*].user.username}")...)
+		Eventually(session).Should(Say(`^(alice bob|bob alice)$`))
+	})
+
+	It("should print unknown users if the audit log doesn't contain any matching request", func() {
+		Expect(os.WriteFile(auditLog, nil, 0600)).To(Succeed())
+
+		session := RunPluginAndWait(args...)
+		Eventually(session.Err).Should(Say(`no requests found in the audit logs`))
+		Eventually(session).Should(Say(`1\s+<unknown>\s+<unknown>\s+`))
+		Eventually(session).Should(Say(`2\s+<unknown>\s+<unknown>\s+`))
+	})
+})
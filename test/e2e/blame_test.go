@@ -0,0 +1,73 @@
+package e2e
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
+	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
+)
+
+var _ = Describe("blame command", func() {
+	var (
+		namespace string
+		object    client.Object
+
+		args []string
+	)
+
+	BeforeEach(func() {
+		namespace = workload.PrepareTestNamespace()
+		args = []string{"blame", "-n", namespace}
+	})
+
+	testCommon := func() {
+		It("should annotate the latest pod template", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(args...)
+			Eventually(session).Should(Say(`1\s+\S+\s+kind: Pod\n`))
+			Eventually(session).Should(Say(`3\s+\S+\s+- image: \S+:0.3\n`))
+		})
+
+		It("should annotate the given revision's pod template", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--revision=-2")...)
+			Eventually(session).Should(Say(`1\s+\S+\s+kind: Pod\n`))
+			Eventually(session).Should(Say(`2\s+\S+\s+- image: \S+:0.2\n`))
+			Consistently(session).ShouldNot(Say(`:0.3`))
+		})
+	}
+
+	Context("Deployment", func() {
+		BeforeEach(func() {
+			object = workload.CreateDeployment(namespace, workload.AppName)
+			args = append(args, "deployment", object.GetName())
+		})
+
+		testCommon()
+	})
+
+	Context("StatefulSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateStatefulSet(namespace, workload.AppName)
+			args = append(args, "statefulset", object.GetName())
+		})
+
+		testCommon()
+	})
+
+	Context("DaemonSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateDaemonSet(namespace, workload.AppName)
+			args = append(args, "daemonset", object.GetName())
+		})
+
+		testCommon()
+	})
+})
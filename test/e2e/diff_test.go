@@ -1,10 +1,17 @@
 package e2e
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
 
 	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
 	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
@@ -87,6 +94,33 @@ var _ = Describe("diff command", func() {
 			Eventually(session).Should(Say(`\+.+:0.2\n`))
 		})
 
+		It("should diff every pair of consecutive revisions in the given range", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--revision=1..3")...)
+			Eventually(session).Should(Say(`# comparing revisions 1 and 2 of \S+/pause\n`))
+			Eventually(session).Should(Say(`--- \S+\/1-pause-\S+\s`))
+			Eventually(session).Should(Say(`\+\+\+ \S+\/2-pause-\S+\s`))
+			Eventually(session).Should(Say(`-.+:0.1\n`))
+			Eventually(session).Should(Say(`\+.+:0.2\n`))
+			Eventually(session).Should(Say(`# comparing revisions 2 and 3 of \S+/pause\n`))
+			Eventually(session).Should(Say(`--- \S+\/2-pause-\S+\s`))
+			Eventually(session).Should(Say(`\+\+\+ \S+\/3-pause-\S+\s`))
+			Eventually(session).Should(Say(`-.+:0.2\n`))
+			Eventually(session).Should(Say(`\+.+:0.3\n`))
+		})
+
+		It("should diff the update revision and its predecessor", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--revision=update")...)
+			Eventually(session).Should(Say(`--- \S+\/1-pause-\S+\s`))
+			Eventually(session).Should(Say(`\+\+\+ \S+\/2-pause-\S+\s`))
+			Eventually(session).Should(Say(`-.+:0.1\n`))
+			Eventually(session).Should(Say(`\+.+:0.2\n`))
+		})
+
 		It("should diff the revisions in the given format", func() {
 			workload.BumpImage(object)
 			workload.BumpImage(object)
@@ -98,6 +132,107 @@ var _ = Describe("diff command", func() {
 			Eventually(session).Should(Say(`\+.+:0.3\n`))
 		})
 
+		It("should diff the latest revision and the workload resource in the given manifest", func() {
+			gvk, err := apiutil.GVKForObject(object, testClient.Scheme())
+			Expect(err).NotTo(HaveOccurred())
+			manifest := object.DeepCopyObject().(client.Object)
+			manifest.GetObjectKind().SetGroupVersionKind(gvk)
+
+			data, err := yaml.Marshal(manifest)
+			Expect(err).NotTo(HaveOccurred())
+			file := filepath.Join(GinkgoT().TempDir(), "manifest.yaml")
+			Expect(os.WriteFile(file, []byte(strings.ReplaceAll(string(data), ":0.1", ":manifest")), 0600)).To(Succeed())
+
+			// the workload resource is inferred from the manifest
+			session := RunPluginAndWait("diff", "-n", namespace, "-f", file)
+			Eventually(session.Err).Should(Say(`comparing revision 1 of \S+/pause with ` + file))
+			Eventually(session).Should(Say(`--- \S+\/1-pause-\S+\s`))
+			Eventually(session).Should(Say(`\+\+\+ \S+\/manifest-pause-\S+\s`))
+			Eventually(session).Should(Say(`-.+:0.1\n`))
+			Eventually(session).Should(Say(`\+.+:manifest\n`))
+		})
+
+		It("should default the hand-written manifest before comparing it", func() {
+			file := filepath.Join(GinkgoT().TempDir(), "manifest.yaml")
+			Expect(os.WriteFile(file, []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: `+object.GetName()+`
+spec:
+  selector:
+    matchLabels:
+      app: `+workload.AppName+`
+      e2e-test: kubectl-revisions
+  template:
+    metadata:
+      labels:
+        app: `+workload.AppName+`
+        e2e-test: kubectl-revisions
+    spec:
+      containers:
+      - name: `+workload.AppName+`
+        image: `+workload.ImageRepository+`:manifest
+`), 0600)).To(Succeed())
+
+			session := RunPluginAndWait("diff", "-n", namespace, "-f", file)
+			Eventually(session).Should(Say(`-.+:0.1\n`))
+			Eventually(session).Should(Say(`\+.+:manifest\n`))
+			// fields defaulted by the API server must not show up as removed
+			Expect(string(session.Out.Contents())).NotTo(MatchRegexp(`\n-\s+(imagePullPolicy|terminationMessagePath|dnsPolicy|restartPolicy):`))
+		})
+
+		It("should print the changed fields on -o fieldpath", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "-o", "fieldpath")...)
+			Eventually(session).Should(Say(`spec.containers\[name=pause\].image: \S+:0.1 -> \S+:0.2\n`))
+		})
+
+		It("should print a JSON patch on -o json-patch", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "-o", "json-patch")...)
+			Eventually(session).Should(Say(`"op": "replace",\s+"path": "/spec/containers/0/image",\s+"value": "\S+:0.2"`))
+		})
+
+		It("should print a merge patch on -o merge-patch", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "-o", "merge-patch")...)
+			Eventually(session).Should(Say(`"image": "\S+:0.2"`))
+		})
+
+		It("should ignore the given fields", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "-o", "fieldpath", "--ignore-path=spec.containers[*].image")...)
+			Consistently(session).ShouldNot(Say(`image`))
+		})
+
+		Context("exit code mode", func() {
+			It("should exit with 1 and print the changed fields if there are differences", func() {
+				workload.BumpImage(object)
+
+				session := RunPlugin(append(args, "--exit-code")...)
+				Eventually(session).Should(gexec.Exit(1))
+				Expect(session).To(Say(`"failed": true`))
+				Expect(session).To(Say(`"path": "spec.containers\[name=pause\].image"`))
+			})
+
+			It("should exit with 0 if there are no relevant differences", func() {
+				workload.BumpImage(object)
+
+				session := RunPluginAndWait(append(args, "--fail-on-path=spec.containers[*].resources")...)
+				Expect(session).To(Say(`"failed": false`))
+				Expect(session).To(Say(`"failedPaths": \[\]`))
+			})
+
+			It("should exit with 2 on errors", func() {
+				session := RunPlugin("diff", "-n", namespace, "deployment", "non-existing", "--exit-code")
+				Eventually(session).Should(gexec.Exit(2))
+			})
+		})
+
 		Context("external diff", func() {
 			It("should invoke the external diff program", func() {
 				workload.BumpImage(object)
@@ -129,6 +264,35 @@ var _ = Describe("diff command", func() {
 				Eventually(session).Should(Say(`\+.+:0.2\n`))
 			})
 		})
+
+		Context("builtin diff", func() {
+			It("should use the builtin diff engine", func() {
+				workload.BumpImage(object)
+
+				cmd := NewPluginCommand(append(args, "--diff-engine=builtin")...)
+				// ensure the external diff program is not used
+				cmd.Env = append(cmd.Env, "KUBECTL_EXTERNAL_DIFF=ls")
+
+				session := Wait(RunCommand(cmd))
+				Eventually(session).Should(Say(`--- \S+\/1-pause-\S+\n`))
+				Eventually(session).Should(Say(`\+\+\+ \S+\/2-pause-\S+\n`))
+				Eventually(session).Should(Say(`-.+:0.1\n`))
+				Eventually(session).Should(Say(`\+.+:0.2\n`))
+			})
+
+			It("should fall back to the builtin diff engine if the external program cannot be found", func() {
+				workload.BumpImage(object)
+
+				cmd := NewPluginCommand(args...)
+				cmd.Env = append(cmd.Env, "KUBECTL_EXTERNAL_DIFF=non-existing-diff")
+
+				session := Wait(RunCommand(cmd))
+				Eventually(session).Should(Say(`--- \S+\/1-pause-\S+\n`))
+				Eventually(session).Should(Say(`\+\+\+ \S+\/2-pause-\S+\n`))
+				Eventually(session).Should(Say(`-.+:0.1\n`))
+				Eventually(session).Should(Say(`\+.+:0.2\n`))
+			})
+		})
 	}
 
 	Context("Deployment", func() {
@@ -199,6 +363,32 @@ var _ = Describe("diff command", func() {
 			Eventually(session).Should(Say(`-.+:0.1\n`))
 			Eventually(session).Should(Say(`\+.+:0.2\n`))
 		})
+
+		Context("against namespace", func() {
+			var againstNamespace string
+
+			BeforeEach(func() {
+				againstNamespace = workload.PrepareTestNamespace()
+				workload.BumpImage(workload.CreateDeployment(againstNamespace, workload.AppName))
+			})
+
+			It("should diff the latest revisions in both namespaces", func() {
+				session := RunPluginAndWait(append(args, "--against-namespace", againstNamespace)...)
+				Eventually(session.Err).Should(Say(`comparing revision 1 of deployment.apps/pause in namespace ` + namespace +
+					` with revision 2 of deployment.apps/pause in namespace ` + againstNamespace))
+				Eventually(session).Should(Say(`--- \S+\/1-pause-\S+\s`))
+				Eventually(session).Should(Say(`\+\+\+ \S+\/against-2-pause-\S+\s`))
+				Eventually(session).Should(Say(`-.+:0.1\n`))
+				Eventually(session).Should(Say(`\+.+:0.2\n`))
+			})
+
+			It("should list the revisions with identical pod templates", func() {
+				session := RunPluginAndWait(append(args, "--against-namespace", againstNamespace, "--identical")...)
+				Eventually(session).Should(Say(`REVISION\s+NAME\s+ROLE\s+AGAINST-REVISION\s+AGAINST-NAME\s+AGAINST-ROLE\n`))
+				Eventually(session).Should(Say(`1\s+pause-\S+\s+current\s+1\s+pause-\S+\s+old\n`))
+				Consistently(session).ShouldNot(Say(`pause`))
+			})
+		})
 	})
 
 	Context("StatefulSet", func() {
@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -38,20 +39,20 @@ var _ = Describe("get command", func() {
 
 		It("should work with alias ls", func() {
 			args[0] = "ls"
-			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 
 		It("should work with alias list", func() {
 			args[0] = "list"
-			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 	})
 
 	testCommon := func(createObject func(namespace, name string) client.Object) {
 		It("should print a single revision in list format", func() {
 			session := RunPluginAndWait(args...)
-			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+AGE\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 			Consistently(session).ShouldNot(Say(`pause-`))
 		})
 
@@ -60,10 +61,10 @@ var _ = Describe("get command", func() {
 			workload.BumpImage(object)
 
 			session := RunPluginAndWait(append(args, "-o", "wide")...)
-			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+AGE\s+CONTAINERS\s+IMAGES\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+pause\s+\S+:0.1\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+pause\s+\S+:0.2\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+3\s+\d/\d\s+\S+\s+pause\s+\S+:0.3\n`))
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\s+TRIGGER\s+CONTAINERS\s+IMAGES\s+MANAGER\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\s+initial\s+pause\s+\S+:0.1\s+\S+\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.2\s+\S+\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+3\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.3\s+\S+\n`))
 			Consistently(session).ShouldNot(Say(`pause-`))
 		})
 
@@ -72,7 +73,7 @@ var _ = Describe("get command", func() {
 			workload.BumpImage(object)
 
 			session := RunPluginAndWait(append(args, "--revision=2")...)
-			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\n`))
 			Consistently(session).ShouldNot(Say(`pause-`))
 		})
 
@@ -81,7 +82,25 @@ var _ = Describe("get command", func() {
 			workload.BumpImage(object)
 
 			session := RunPluginAndWait(append(args, "--revision=2", "-o", "wide")...)
-			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+pause\s+\S+:0.2\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.2\s+\S+\n`))
+			Consistently(session).ShouldNot(Say(`pause-`))
+		})
+
+		It("should print the revision that is being rolled out (update revision)", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--revision=update")...)
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+(update|current)\s+\S+\n`))
+			Consistently(session).ShouldNot(Say(`pause-`))
+		})
+
+		It("should print the newest revision running the given image", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--revision=image="+workload.ImageRepository+":0.2", "-o", "wide")...)
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.2\s+\S+\n`))
 			Consistently(session).ShouldNot(Say(`pause-`))
 		})
 
@@ -98,6 +117,13 @@ var _ = Describe("get command", func() {
 			Expect(runtime.DecodeInto(decoder, yamlBytes, workload.RevisionObjectFor(object))).To(Succeed())
 		})
 
+		It("should attribute the latest revision to the field manager in yaml format", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--revision=latest", "-o", "yaml")...)
+			Eventually(session).Should(Say(`attribution:\n\s+exact: true\n\s+manager: \S+\n\s+operation: Update\n`))
+		})
+
 		It("should print a specific revision's pod template in json format on --template-only", func() {
 			workload.BumpImage(object)
 			workload.BumpImage(object)
@@ -129,17 +155,44 @@ var _ = Describe("get command", func() {
 			Expect(runtime.DecodeInto(decoder, list.Items[0].Raw, workload.RevisionObjectFor(object))).To(Succeed())
 		})
 
+		It("should print changed revisions on --watch", func() {
+			session := RunPlugin(append(args, "--watch", "-o", "wide")...)
+			DeferCleanup(func() {
+				Eventually(session.Kill()).Should(gexec.Exit())
+			})
+
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\s+TRIGGER\s+CONTAINERS\s+IMAGES\s+MANAGER\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\s+initial\s+pause\s+\S+:0.1\s+\S+\n`))
+
+			workload.BumpImage(object)
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.2\s+\S+\n`))
+			Consistently(session).ShouldNot(Say(`NAME\s+REVISION`))
+		})
+
+		It("should only print changed revisions on --watch-only", func() {
+			session := RunPlugin(append(args, "--watch-only")...)
+			DeferCleanup(func() {
+				Eventually(session.Kill()).Should(gexec.Exit())
+			})
+
+			Consistently(session).ShouldNot(Say(`pause-`))
+
+			workload.BumpImage(object)
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\n`))
+		})
+
 		It("should list revisions of all resources in the namespace", func() {
 			createObject(namespace, workload.AppName+"1")
 			createObject(namespace, workload.AppName+"2")
 			createObject(namespace, workload.AppName+"3")
 
 			session := RunPluginAndWait(args[:len(args)-1]...)
-			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+AGE\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
-			Eventually(session).Should(Say(`pause1-\S+\s+1\s+\d/\d\s+\S+\n`))
-			Eventually(session).Should(Say(`pause2-\S+\s+1\s+\d/\d\s+\S+\n`))
-			Eventually(session).Should(Say(`pause3-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
+			Eventually(session).Should(Say(`pause1-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
+			Eventually(session).Should(Say(`pause2-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
+			Eventually(session).Should(Say(`pause3-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 
 		It("should list revisions of label-selected resources in all namespaces", func() {
@@ -152,11 +205,11 @@ var _ = Describe("get command", func() {
 			labelSelector := labels.SelectorFromValidatedSet(workload.CommonLabels())
 
 			session := RunPluginAndWait("get", args[3], "-A", "-l", labelSelector.String())
-			Eventually(session).Should(Say(`NAMESPACE\s+NAME\s+REVISION\s+READY\s+AGE\n`))
-			Eventually(session).Should(Say(namespace + `\s+pause-\S+\s+1\s+\d/\d\s+\S+\n`))
-			Eventually(session).Should(Say(namespace + `\s+pause1-\S+\s+1\s+\d/\d\s+\S+\n`))
-			Eventually(session).Should(Say(namespace2 + `\s+pause2-\S+\s+1\s+\d/\d\s+\S+\n`))
-			Eventually(session).Should(Say(namespace2 + `\s+pause3-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(session).Should(Say(`NAMESPACE\s+NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(namespace + `\s+pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
+			Eventually(session).Should(Say(namespace + `\s+pause1-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
+			Eventually(session).Should(Say(namespace2 + `\s+pause2-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
+			Eventually(session).Should(Say(namespace2 + `\s+pause3-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 	}
 
@@ -170,23 +223,41 @@ var _ = Describe("get command", func() {
 
 		It("should work with short type", func() {
 			args[3] = "deploy"
-			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 
 		It("should work with grouped type", func() {
 			args[3] = "deployments.apps"
-			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 
 		It("should work with fully-qualified type", func() {
 			args[3] = "deployments.v1.apps"
-			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 
 		It("should work with slash name", func() {
 			args[3] = "deployment/pause"
 			args = args[:len(args)-1]
-			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(RunPluginAndWait(args...)).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
+		})
+
+		It("should read revisions from files on --from-file", func() {
+			workload.BumpImage(object)
+
+			dir := GinkgoT().TempDir()
+			workload.DumpObjectsInNamespace(namespace, "deployments", dir)
+			workload.DumpObjectsInNamespace(namespace, "replicasets", dir)
+
+			cmd := NewPluginCommand(append(args, "-o", "wide", "--from-file", dir)...)
+			// ensure the cluster is not accessed
+			cmd.Env = append(cmd.Env, "KUBECONFIG=/non-existing")
+
+			session := Wait(RunCommand(cmd))
+			// `kubectl get -o yaml` strips managedFields, so there is no MANAGER column
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\s+TRIGGER\s+CONTAINERS\s+IMAGES\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\s+initial\s+pause\s+\S+:0.1\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.2\n`))
 		})
 
 		It("should support the -v flag", func() {
@@ -194,8 +265,8 @@ var _ = Describe("get command", func() {
 			Eventually(session.Err).Should(Say(`Config loaded from file`))
 			Eventually(session.Err).Should(Say(`GET[ \S]+/apis/apps/v1/namespaces/` + namespace + `/deployments/pause`))
 			Eventually(session.Err).Should(Say(`GET[ \S]+/apis/apps/v1/namespaces/` + namespace + `/replicasets\?labelSelector=app%3Dpause%2Ce2e-test%3Dkubectl-revisions`))
-			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+AGE\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\n`))
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+\d/\d\s+\S+\s+\S+\n`))
 		})
 
 		It("should correctly print replicas", func() {
@@ -208,9 +279,9 @@ var _ = Describe("get command", func() {
 			Eventually(komega.Object(object)).Should(HaveField("Status.UpdatedReplicas", int32(1)))
 
 			session := RunPluginAndWait(args...)
-			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+AGE\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+1\s+2/2\s+\S+\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+2\s+0/1\s+\S+\n`))
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+2/2\s+current\s+\S+\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+0/1\s+update\s+\S+\n`))
 		})
 	})
 
@@ -232,9 +303,9 @@ var _ = Describe("get command", func() {
 			Eventually(komega.Object(object)).Should(HaveField("Status.UpdatedReplicas", int32(1)))
 
 			session := RunPluginAndWait(args...)
-			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+AGE\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+1\s+1/1\s+\S+\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+2\s+0/1\s+\S+\n`))
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+1/1\s+current\s+\S+\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+0/1\s+update\s+\S+\n`))
 		})
 	})
 
@@ -264,9 +335,9 @@ var _ = Describe("get command", func() {
 			Eventually(komega.ObjectList(&corev1.PodList{}, client.InNamespace(namespace))).Should(HaveField("Items", HaveLen(3)))
 
 			session := RunPluginAndWait(args...)
-			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+AGE\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+1\s+2/2\s+\S+\n`))
-			Eventually(session).Should(Say(`pause-\S+\s+2\s+0/1\s+\S+\n`))
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+2/2\s+current\s+\S+\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+0/1\s+update\s+\S+\n`))
 		})
 	})
 })
@@ -17,6 +17,13 @@ var _ = Describe("command help", func() {
 		Eventually(session).Should(Say(`Available Commands:\n`))
 		Eventually(session).Should(Say(`\s+get\s+`))
 		Eventually(session).Should(Say(`\s+diff\s+`))
+		Eventually(session).Should(Say(`\s+rollback\s+`))
+		Eventually(session).Should(Say(`\s+record\s+`))
+		Eventually(session).Should(Say(`\s+blame\s+`))
+		Eventually(session).Should(Say(`\s+log\s+`))
+		Eventually(session).Should(Say(`\s+pods\s+`))
+		Eventually(session).Should(Say(`\s+audit\s+`))
+		Eventually(session).Should(Say(`\s+timeline\s+`))
 		Eventually(session).Should(Say(`Other Commands:\n`))
 		Eventually(session).Should(Say(`\s+completion\s+`))
 		Eventually(session).Should(Say(`\s+version\s+`))
@@ -0,0 +1,96 @@
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
+	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
+)
+
+var _ = Describe("log command", func() {
+	var (
+		namespace string
+		object    client.Object
+
+		args []string
+	)
+
+	BeforeEach(func() {
+		namespace = workload.PrepareTestNamespace()
+		args = []string{"log", "-n", namespace}
+	})
+
+	testCommon := func() {
+		It("should summarize all revisions from newest to oldest", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(args...)
+			Eventually(session).Should(Say(`revision 3 \(pause-\S+\)\n`))
+			Eventually(session).Should(Say(`Date: .+\n`))
+			Eventually(session).Should(Say(`\s+image pause: \S+:0.2 -> \S+:0.3\n`))
+			Eventually(session).Should(Say(`revision 2 \(pause-\S+\)\n`))
+			Eventually(session).Should(Say(`\s+image pause: \S+:0.1 -> \S+:0.2\n`))
+			Eventually(session).Should(Say(`revision 1 \(pause-\S+\)\n`))
+			Eventually(session).Should(Say(`\s+initial revision\n`))
+		})
+
+		It("should print the diff of every revision on --patch", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--patch")...)
+			Eventually(session).Should(Say(`revision 3 \(pause-\S+\)\n`))
+			Eventually(session).Should(Say(`-.+:0.2\n`))
+			Eventually(session).Should(Say(`\+.+:0.3\n`))
+			Eventually(session).Should(Say(`revision 2 \(pause-\S+\)\n`))
+			Eventually(session).Should(Say(`-.+:0.1\n`))
+			Eventually(session).Should(Say(`\+.+:0.2\n`))
+			Eventually(session).Should(Say(`revision 1 \(pause-\S+\)\n`))
+		})
+	}
+
+	Context("Deployment", func() {
+		BeforeEach(func() {
+			object = workload.CreateDeployment(namespace, workload.AppName)
+			args = append(args, "deployment", object.GetName())
+		})
+
+		testCommon()
+
+		It("should print the change cause", func() {
+			Expect(testClient.Patch(context.Background(), object, client.RawPatch(types.MergePatchType,
+				[]byte(`{"metadata":{"annotations":{"kubernetes.io/change-cause":"bump image"}}}`),
+			))).To(Succeed())
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(args...)
+			Eventually(session).Should(Say(`revision 2 \(pause-\S+\)\n`))
+			Eventually(session).Should(Say(`Change-Cause: bump image\n`))
+		})
+	})
+
+	Context("StatefulSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateStatefulSet(namespace, workload.AppName)
+			args = append(args, "statefulset", object.GetName())
+		})
+
+		testCommon()
+	})
+
+	Context("DaemonSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateDaemonSet(namespace, workload.AppName)
+			args = append(args, "daemonset", object.GetName())
+		})
+
+		testCommon()
+	})
+})
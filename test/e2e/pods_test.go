@@ -0,0 +1,132 @@
+package e2e
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
+	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
+)
+
+var _ = Describe("pods command", func() {
+	var (
+		namespace string
+		object    client.Object
+
+		args []string
+	)
+
+	BeforeEach(func() {
+		namespace = workload.PrepareTestNamespace()
+		args = []string{"pods", "-n", namespace}
+	})
+
+	testCommon := func() {
+		JustBeforeEach(func() {
+			Eventually(komega.ObjectList(&corev1.PodList{}, client.InNamespace(namespace))).Should(HaveField("Items", Not(BeEmpty())))
+		})
+
+		It("should print the pods of all revisions", func() {
+			session := RunPluginAndWait(args...)
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+ROLE\s+READY\s+STATUS\s+RESTARTS\s+AGE\s+NODE\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+current\s+\d/1\s+\S+\s+0\s+\S+\s+\S+\n`))
+		})
+
+		It("should print additional columns in wide format", func() {
+			session := RunPluginAndWait(append(args, "-o", "wide")...)
+			Eventually(session).Should(Say(`NAME\s+REVISION\s+ROLE\s+READY\s+STATUS\s+RESTARTS\s+AGE\s+NODE\s+IP\s+REVISION-NAME\n`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+current\s+.+\s+pause-\S+\n`))
+		})
+
+		It("should print the pod names", func() {
+			Eventually(RunPluginAndWait(append(args, "-o", "name")...)).Should(Say(`pod/pause-\S+\n`))
+		})
+	}
+
+	Context("Deployment", func() {
+		BeforeEach(func() {
+			object = workload.CreateDeployment(namespace, workload.AppName)
+			args = append(args, "deployment", object.GetName())
+		})
+
+		testCommon()
+
+		It("should print the pods of each revision during a rollout", func() {
+			workload.Scale(object, 2)
+			Eventually(komega.Object(object)).Should(HaveField("Status.ReadyReplicas", int32(2)))
+
+			// prepare second revision with broken image
+			// this make it easy and deterministic to test which replicas still run the old revision
+			workload.SetImage(object, workload.ImageRepository+":non-existing")
+			Eventually(komega.Object(object)).Should(HaveField("Status.UpdatedReplicas", int32(1)))
+
+			session := RunPluginAndWait(args...)
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+current\s+1/1\s+Running\s+`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+current\s+1/1\s+Running\s+`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+update\s+0/1\s+\S+\s+`))
+		})
+
+		It("should only print the pods of the selected revision", func() {
+			workload.Scale(object, 2)
+			Eventually(komega.Object(object)).Should(HaveField("Status.ReadyReplicas", int32(2)))
+
+			workload.SetImage(object, workload.ImageRepository+":non-existing")
+			Eventually(komega.Object(object)).Should(HaveField("Status.UpdatedReplicas", int32(1)))
+
+			session := RunPluginAndWait(append(args, "--revision=update", "--no-headers")...)
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+update\s+0/1\s+`))
+			Consistently(session).ShouldNot(Say(`pause-`))
+		})
+	})
+
+	Context("StatefulSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateStatefulSet(namespace, workload.AppName)
+			args = append(args, "statefulset", object.GetName())
+		})
+
+		testCommon()
+
+		It("should print the pods of each revision during a rollout", func() {
+			workload.Scale(object, 2)
+			Eventually(komega.Object(object)).Should(HaveField("Status.ReadyReplicas", int32(2)))
+
+			workload.SetImage(object, workload.ImageRepository+":non-existing")
+			Eventually(komega.Object(object)).Should(HaveField("Status.UpdatedReplicas", int32(1)))
+
+			session := RunPluginAndWait(args...)
+			Eventually(session).Should(Say(`pause-0\s+1\s+current\s+1/1\s+Running\s+`))
+			Eventually(session).Should(Say(`pause-1\s+2\s+update\s+0/1\s+\S+\s+`))
+		})
+	})
+
+	Context("DaemonSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateDaemonSet(namespace, workload.AppName)
+			args = append(args, "daemonset", object.GetName())
+		})
+
+		testCommon()
+
+		It("should print the pods of each revision during a rollout", func() {
+			Eventually(komega.Object(object)).Should(HaveField("Status.NumberReady", int32(3)))
+
+			workload.SetImage(object, workload.ImageRepository+":non-existing")
+			Eventually(komega.Object(object)).Should(And(
+				HaveField("Status.NumberReady", int32(2)),
+				HaveField("Status.UpdatedNumberScheduled", int32(1)),
+			))
+			// wait until the old pod has finished terminating, see get command test
+			Eventually(komega.ObjectList(&corev1.PodList{}, client.InNamespace(namespace))).Should(HaveField("Items", HaveLen(3)))
+
+			session := RunPluginAndWait(args...)
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+current\s+1/1\s+Running\s+`))
+			Eventually(session).Should(Say(`pause-\S+\s+1\s+current\s+1/1\s+Running\s+`))
+			Eventually(session).Should(Say(`pause-\S+\s+2\s+update\s+0/1\s+\S+\s+`))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	appsv1 "k8s.io/api/apps/v1"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
+	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
+)
+
+var _ = Describe("record command", func() {
+	var (
+		namespace  string
+		object     client.Object
+		archiveDir string
+
+		args []string
+	)
+
+	BeforeEach(func() {
+		namespace = workload.PrepareTestNamespace()
+		archiveDir = GinkgoT().TempDir()
+
+		object = workload.CreateDeployment(namespace, workload.AppName)
+		workload.BumpImage(object)
+		workload.BumpImage(object)
+
+		args = []string{"record", "-n", namespace, "--archive-dir", archiveDir, "deployment", object.GetName()}
+	})
+
+	It("should record all revisions only once", func() {
+		Eventually(RunPluginAndWait(args...)).Should(Say(`deployment.apps/pause recorded 3 new revisions\n`))
+		Eventually(RunPluginAndWait(args...)).Should(Say(`deployment.apps/pause recorded 0 new revisions\n`))
+	})
+
+	It("should merge archived revisions with live revisions", func() {
+		Eventually(RunPluginAndWait(args...)).Should(Say(`recorded 3 new revisions\n`))
+
+		// simulate garbage collection of the first revision
+		replicaSetList := &appsv1.ReplicaSetList{}
+		Expect(testClient.List(context.Background(), replicaSetList, client.InNamespace(namespace))).To(Succeed())
+		for _, replicaSet := range replicaSetList.Items {
+			if replicaSet.Annotations[deploymentutil.RevisionAnnotation] == "1" {
+				Expect(testClient.Delete(context.Background(), &replicaSet)).To(Succeed())
+			}
+		}
+
+		session := RunPluginAndWait("get", "-n", namespace, "deployment", object.GetName())
+		Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\n`))
+		Consistently(session).ShouldNot(Say(`pause-\S+\s+1\s+`))
+
+		session = RunPluginAndWait("get", "-n", namespace, "--archive", "--archive-dir", archiveDir, "deployment", object.GetName(), "-o", "wide")
+		Eventually(session).Should(Say(`NAME\s+REVISION\s+READY\s+ROLE\s+AGE\s+MARKERS\s+TRIGGER\s+CONTAINERS\s+IMAGES\s+MANAGER\n`))
+		Eventually(session).Should(Say(`pause-\S+\s+1\s+0/0\s+old\s+\S+\s+archived\s+initial\s+pause\s+\S+:0.1\s+\S+\n`))
+		Eventually(session).Should(Say(`pause-\S+\s+2\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.2\s+\S+\n`))
+		Eventually(session).Should(Say(`pause-\S+\s+3\s+\d/\d\s+\S+\s+\S+\s+image\s+pause\s+\S+:0.3\s+\S+\n`))
+
+		session = RunPluginAndWait("diff", "-n", namespace, "--archive", "--archive-dir", archiveDir, "deployment", object.GetName(), "--revision=1,2")
+		Eventually(session).Should(Say(`--- \S+\/1-pause-\S+\s`))
+		Eventually(session).Should(Say(`\+\+\+ \S+\/2-pause-\S+\s`))
+		Eventually(session).Should(Say(`-.+:0.1\n`))
+		Eventually(session).Should(Say(`\+.+:0.2\n`))
+	})
+})
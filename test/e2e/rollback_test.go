@@ -0,0 +1,146 @@
+package e2e
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
+	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
+)
+
+var _ = Describe("rollback command", func() {
+	var (
+		namespace string
+		object    client.Object
+
+		args []string
+	)
+
+	BeforeEach(func() {
+		namespace = workload.PrepareTestNamespace()
+		args = []string{"rollback", "-n", namespace}
+	})
+
+	haveImage := func(tag string) OmegaMatcher {
+		return HaveField("Spec.Template.Spec.Containers", ConsistOf(HaveField("Image", workload.ImageRepository+":"+tag)))
+	}
+
+	Describe("command aliases", func() {
+		BeforeEach(func() {
+			object = workload.CreateDeployment(namespace, workload.AppName)
+			args = append(args, "deployment", object.GetName())
+		})
+
+		It("should work with alias undo", func() {
+			args[0] = "undo"
+
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--yes")...)
+			Eventually(session).Should(Say(`deployment.apps/pause rolled back to revision 1\n`))
+			Eventually(komega.Object(object)).Should(haveImage("0.1"))
+		})
+	})
+
+	testCommon := func() {
+		It("should roll back to the previous revision", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--yes")...)
+			Eventually(session).Should(Say(`-.+:0.3\n`))
+			Eventually(session).Should(Say(`\+.+:0.2\n`))
+			Eventually(session).Should(Say(`rolled back to revision 2\n`))
+			Eventually(komega.Object(object)).Should(haveImage("0.2"))
+		})
+
+		It("should roll back to the given revision", func() {
+			workload.BumpImage(object)
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--yes", "--revision=1")...)
+			Eventually(session).Should(Say(`rolled back to revision 1\n`))
+			Eventually(komega.Object(object)).Should(haveImage("0.1"))
+		})
+
+		It("should roll back after confirmation", func() {
+			workload.BumpImage(object)
+
+			cmd := NewPluginCommand(args...)
+			cmd.Stdin = strings.NewReader("y\n")
+
+			session := Wait(RunCommand(cmd))
+			Eventually(session.Err).Should(Say(`to revision 1\? \[y/N\]: `))
+			Eventually(session).Should(Say(`rolled back to revision 1\n`))
+			Eventually(komega.Object(object)).Should(haveImage("0.1"))
+		})
+
+		It("should not roll back without confirmation", func() {
+			workload.BumpImage(object)
+
+			cmd := NewPluginCommand(args...)
+			cmd.Stdin = strings.NewReader("n\n")
+
+			session := Wait(RunCommand(cmd))
+			Eventually(session.Err).Should(Say(`rollback aborted\n`))
+			Consistently(komega.Object(object)).Should(haveImage("0.2"))
+		})
+
+		It("should not roll back on --dry-run=client", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--dry-run=client")...)
+			Eventually(session).Should(Say(`-.+:0.2\n`))
+			Eventually(session).Should(Say(`\+.+:0.1\n`))
+			Eventually(session).Should(Say(`rolled back to revision 1 \(dry run\)\n`))
+			Consistently(komega.Object(object)).Should(haveImage("0.2"))
+		})
+
+		It("should not roll back on --dry-run=server", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--dry-run=server", "--yes")...)
+			Eventually(session).Should(Say(`rolled back to revision 1 \(server dry run\)\n`))
+			Consistently(komega.Object(object)).Should(haveImage("0.2"))
+		})
+
+		It("should skip rolling back to the current revision", func() {
+			workload.BumpImage(object)
+
+			session := RunPluginAndWait(append(args, "--revision=-1")...)
+			Eventually(session).Should(Say(`skipped rollback \(current template already matches revision 2\)\n`))
+		})
+	}
+
+	Context("Deployment", func() {
+		BeforeEach(func() {
+			object = workload.CreateDeployment(namespace, workload.AppName)
+			args = append(args, "deployment", object.GetName())
+		})
+
+		testCommon()
+	})
+
+	Context("StatefulSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateStatefulSet(namespace, workload.AppName)
+			args = append(args, "statefulset", object.GetName())
+		})
+
+		testCommon()
+	})
+
+	Context("DaemonSet", func() {
+		BeforeEach(func() {
+			object = workload.CreateDaemonSet(namespace, workload.AppName)
+			args = append(args, "daemonset", object.GetName())
+		})
+
+		testCommon()
+	})
+})
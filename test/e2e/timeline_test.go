@@ -0,0 +1,52 @@
+package e2e
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	. "github.com/timebertt/kubectl-revisions/test/e2e/exec"
+	"github.com/timebertt/kubectl-revisions/test/e2e/workload"
+)
+
+var _ = Describe("timeline command", func() {
+	var (
+		namespace string
+		object    client.Object
+
+		args []string
+	)
+
+	BeforeEach(func() {
+		namespace = workload.PrepareTestNamespace()
+
+		object = workload.CreateDeployment(namespace, workload.AppName)
+		workload.BumpImage(object)
+		Eventually(komega.ObjectList(&appsv1.ReplicaSetList{}, client.InNamespace(namespace))).Should(HaveField("Items", HaveLen(2)))
+		Eventually(komega.Object(object)).Should(HaveField("Status.UpdatedReplicas", int32(1)))
+
+		args = []string{"timeline", "-n", namespace, "deployment", object.GetName()}
+	})
+
+	It("should print the revisions and events in chronological order", func() {
+		session := RunPluginAndWait(args...)
+		Eventually(session).Should(Say(`TIME\s+REVISION\s+TYPE\s+REASON\s+OBJECT\s+MESSAGE\n`))
+		Eventually(session).Should(Say(`\S+\s+1\s+Normal\s+RevisionCreated\s+ReplicaSet/pause-\S+\s+Created revision 1\n`))
+		Eventually(session).Should(Say(`\S+\s+2\s+Normal\s+RevisionCreated\s+ReplicaSet/pause-\S+\s+Created revision 2\n`))
+		Eventually(session).Should(Say(`\S+\s+2\s+Normal\s+ScalingReplicaSet\s+Deployment/pause\s+Scaled up replica set pause-\S+`))
+	})
+
+	It("should print the timeline as JSON", func() {
+		session := RunPluginAndWait(append(args, "-o", "json")...)
+		Eventually(session).Should(Say(`"revision": 1,\s+"type": "Normal",\s+"kind": "ReplicaSet",\s+"name": "pause-\S+",\s+"reason": "RevisionCreated"`))
+		Eventually(session).Should(Say(`"revision": 2,\s+"type": "Normal",\s+"kind": "ReplicaSet",\s+"name": "pause-\S+",\s+"reason": "RevisionCreated"`))
+	})
+
+	It("should only print recent entries", func() {
+		session := RunPluginAndWait(append(args, "--since=1ms")...)
+		Eventually(session.Err).Should(Say(`No timeline entries found for deployment.apps/pause`))
+	})
+})